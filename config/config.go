@@ -3,27 +3,43 @@ package config
 import (
 	"log"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
-// AppConfig 全局配置实例
-var AppConfig *Config
-
 // Config 应用配置结构体
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Log      LogConfig      `mapstructure:"log"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	S3       S3Config       `mapstructure:"s3"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	Log        LogConfig        `mapstructure:"log"`
+	JWT        JWTConfig        `mapstructure:"jwt"`
+	S3         S3Config         `mapstructure:"s3"`
+	Storage    StorageConfig    `mapstructure:"storage"`
+	Attachment AttachmentConfig `mapstructure:"attachment"`
+	Admin      AdminConfig      `mapstructure:"admin"`
+	OIDC       OIDCConfig       `mapstructure:"oidc"`
+	Mail       MailConfig       `mapstructure:"mail"`
+	Lockout    LockoutConfig    `mapstructure:"lockout"`
 }
 
-// ServerConfig 服务器配置
+// ServerConfig 服务器配置，时长需要带单位（例如 30s）
+// Timeout 为读取请求与写入响应的超时时间，IdleTimeout 为 keep-alive 连接的空闲超时时间，
+// TransferTimeout 为附件上传下载等大文件传输的读写超时时间，这些路由不受 Timeout 限制
+// ShutdownTimeout 为停止服务时等待处理中的请求与后台任务完成的最长时间
+// EnableCORS 为 true 时允许 CORSOrigins 中的来源跨域访问，* 表示任意来源且不允许携带凭证
+// TrustedProxies 为可信的反向代理地址（IP 或 CIDR），只采信这些地址转发的 X-Forwarded-For，为空时以连接地址作为客户端 IP
 type ServerConfig struct {
-	Host        string `mapstructure:"host"`
-	Port        string `mapstructure:"port"`
-	Environment string `mapstructure:"environment"`
+	Host            string        `mapstructure:"host"`
+	Port            string        `mapstructure:"port"`
+	Environment     string        `mapstructure:"environment"`
+	Timeout         time.Duration `mapstructure:"timeout"`
+	IdleTimeout     time.Duration `mapstructure:"idle_timeout"`
+	TransferTimeout time.Duration `mapstructure:"transfer_timeout"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	EnableCORS      bool          `mapstructure:"enable_cors"`
+	CORSOrigins     []string      `mapstructure:"cors_origins"`
+	TrustedProxies  []string      `mapstructure:"trusted_proxies"`
 }
 
 // DatabaseConfig 数据库配置
@@ -31,7 +47,7 @@ type DatabaseConfig struct {
 	Host     string `mapstructure:"host"`
 	Port     string `mapstructure:"port"`
 	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
+	Password string `mapstructure:"password" secret:"true"`
 	Name     string `mapstructure:"name"`
 	SSLMode  string `mapstructure:"sslmode"`
 	Timezone string `mapstructure:"timezone"`
@@ -54,20 +70,139 @@ type LogConfig struct {
 // S3Config S3配置
 type S3Config struct {
 	Enabled         bool   `mapstructure:"enabled"`
-	AccessKeyID     string `mapstructure:"access_key_id"`
-	SecretAccessKey string `mapstructure:"secret_access_key"`
+	AccessKeyID     string `mapstructure:"access_key_id" secret:"true"`
+	SecretAccessKey string `mapstructure:"secret_access_key" secret:"true"`
 	Region          string `mapstructure:"region"`
 	Endpoint        string `mapstructure:"endpoint"`
 }
 
+// StorageConfig 对象存储配置
+// Driver 为 s3 时使用 S3 兼容存储（参数见 S3Config），为 local 时存储在本地目录；
+// 未设置时 s3.enabled 为 true 则使用 s3，否则使用 local
+type StorageConfig struct {
+	Driver  string             `mapstructure:"driver"`
+	Local   LocalStorageConfig `mapstructure:"local"`
+	Buckets BucketsConfig      `mapstructure:"buckets"`
+}
+
+// BucketsConfig 各业务使用的 bucket 名称
+type BucketsConfig struct {
+	Attachments string `mapstructure:"attachments"`
+}
+
+// LocalStorageConfig 本地存储配置，BaseURL 为本服务对外地址下的存储路径，用于生成预签名 URL
+type LocalStorageConfig struct {
+	Dir     string `mapstructure:"dir"`
+	BaseURL string `mapstructure:"base_url"`
+}
+
+// AttachmentConfig 提示词附件配置
+// MaxSize 为单个附件的最大字节数，AllowedTypes 为允许的 MIME 类型（按文件内容识别），
+// UploadExpires、DownloadExpires 为预签名上传、下载 URL 的有效期（秒）
+type AttachmentConfig struct {
+	MaxSize         int64    `mapstructure:"max_size"`
+	AllowedTypes    []string `mapstructure:"allowed_types"`
+	UploadExpires   int64    `mapstructure:"upload_expires"`
+	DownloadExpires int64    `mapstructure:"download_expires"`
+}
+
 // JWTConfig JWT配置
+// Expired 为访问令牌有效期（秒），RefreshExpired 为刷新令牌有效期（秒）
+// Algorithm 为 RS256 或 EdDSA 时使用数据库中的密钥环签名，每隔 RotationInterval 秒轮换一次（0 表示不轮换），
+// 被替换的密钥继续保留 KeyRetention 秒用于验证，公钥通过 /.well-known/jwks.json 发布
 type JWTConfig struct {
-	Expired int64  `mapstructure:"expired"`
-	Secret  string `mapstructure:"secret"`
+	Expired          int64  `mapstructure:"expired"`
+	RefreshExpired   int64  `mapstructure:"refresh_expired"`
+	Secret           string `mapstructure:"secret" secret:"true"`
+	Algorithm        string `mapstructure:"algorithm"`
+	RotationInterval int64  `mapstructure:"rotation_interval"`
+	KeyRetention     int64  `mapstructure:"key_retention"`
+}
+
+// AdminConfig 初始管理员配置，执行迁移时若系统中没有管理员则按此创建
+// 未配置密码时会生成随机密码并输出到日志
+type AdminConfig struct {
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password" secret:"true"`
+	Email    string `mapstructure:"email"`
+}
+
+// OIDCConfig OpenID Connect 登录配置，providers 的键为提供方名称，用于登录路径 /auth/oidc/{name}
+type OIDCConfig struct {
+	Providers map[string]OIDCProviderConfig `mapstructure:"providers"`
+}
+
+// OIDCProviderConfig OIDC 提供方配置
+// AutoProvision 为 true 时，未关联且邮箱未被占用的用户首次登录会自动创建账号
+// Mock 为 true 时使用进程内的模拟提供方，仅限 test 环境，issuer 需指向本服务下的路径
+// 模拟提供方可以任意指定登录邮箱，通过它登录时不会关联已有账号
+type OIDCProviderConfig struct {
+	Issuer        string   `mapstructure:"issuer"`
+	ClientID      string   `mapstructure:"client_id"`
+	ClientSecret  string   `mapstructure:"client_secret" secret:"true"`
+	RedirectURL   string   `mapstructure:"redirect_url"`
+	Scopes        []string `mapstructure:"scopes"`
+	AutoProvision bool     `mapstructure:"auto_provision"`
+	Mock          bool     `mapstructure:"mock"`
+}
+
+// MailConfig 邮件配置
+// Driver 为 smtp 时通过 SMTP 发送，为 file 时写入 FileDir 目录（开发与测试使用）
+// LinkBaseURL 为邮件中验证/重置链接的前端地址
+type MailConfig struct {
+	Driver      string     `mapstructure:"driver"`
+	From        string     `mapstructure:"from"`
+	LinkBaseURL string     `mapstructure:"link_base_url"`
+	FileDir     string     `mapstructure:"file_dir"`
+	SMTP        SMTPConfig `mapstructure:"smtp"`
+}
+
+// SMTPConfig SMTP 配置，TLS 为 tls 时使用隐式 TLS（通常为 465 端口），否则在服务端支持时自动启用 STARTTLS
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password" secret:"true"`
+	TLS      string `mapstructure:"tls"`
+}
+
+// LockoutConfig 登录失败锁定配置，时间单位均为秒
+// 同一账号或同一 IP 在 Window 内连续失败达到阈值后锁定，锁定时长从 BaseDuration 开始每次翻倍，最长 MaxDuration
+type LockoutConfig struct {
+	AccountMaxFailures int   `mapstructure:"account_max_failures"`
+	IPMaxFailures      int   `mapstructure:"ip_max_failures"`
+	Window             int64 `mapstructure:"window"`
+	BaseDuration       int64 `mapstructure:"base_duration"`
+	MaxDuration        int64 `mapstructure:"max_duration"`
 }
 
-// Init 初始化配置
+// Init 初始化配置，配置文件格式错误或校验不通过时退出程序
 func Init(configPath string) {
+	if err := read(configPath); err != nil {
+		log.Fatalf("配置文件读取失败: %v", err)
+	}
+
+	// 解析配置
+	cfg, err := load()
+	if err != nil {
+		log.Fatalf("配置校验失败:\n%v", err)
+	}
+	store(cfg)
+
+	log.Println("配置加载成功")
+}
+
+// Check 读取并校验配置但不替换当前配置，返回使用的配置文件（未找到时为空）
+func Check(configPath string) (string, error) {
+	if err := read(configPath); err != nil {
+		return "", err
+	}
+	_, err := load()
+	return viper.ConfigFileUsed(), err
+}
+
+// read 读取配置文件并设置默认值与环境变量绑定
+func read(configPath string) error {
 	// 设置配置文件名和路径
 	viper.SetConfigName("config")
 	if configPath != "" {
@@ -76,18 +211,18 @@ func Init(configPath string) {
 		viper.AddConfigPath(".")
 	}
 
-	// 设置环境变量前缀
-	viper.SetEnvPrefix("STARTER")
+	// 设置环境变量前缀，配置项 a.b_c 对应环境变量 PROOMET_A_B_C
+	viper.SetEnvPrefix(envPrefix)
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
+	warnLegacyEnvs()
 
 	// 读取配置文件
 	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			log.Println("配置文件未找到，使用默认配置")
-		} else {
-			log.Fatalf("配置文件读取失败: %v", err)
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return err
 		}
+		log.Println("配置文件未找到，使用默认配置")
 	} else {
 		log.Printf("配置文件加载成功: %s", viper.ConfigFileUsed())
 	}
@@ -97,14 +232,7 @@ func Init(configPath string) {
 
 	// 绑定环境变量
 	bindEnvs()
-
-	// 解析配置
-	AppConfig = &Config{}
-	if err := viper.Unmarshal(AppConfig); err != nil {
-		log.Fatalf("配置解析失败: %v", err)
-	}
-
-	log.Println("配置加载成功")
+	return nil
 }
 
 // setDefaults 设置默认配置值
@@ -113,6 +241,11 @@ func setDefaults() {
 	viper.SetDefault("server.host", "0.0.0.0")
 	viper.SetDefault("server.port", "7070")
 	viper.SetDefault("server.environment", "development")
+	viper.SetDefault("server.timeout", "30s")
+	viper.SetDefault("server.idle_timeout", "120s")
+	viper.SetDefault("server.transfer_timeout", "1h")
+	viper.SetDefault("server.shutdown_timeout", "30s")
+	viper.SetDefault("server.cors_origins", []string{"*"})
 
 	// 数据库配置默认值
 	viper.SetDefault("database.host", "localhost")
@@ -136,43 +269,40 @@ func setDefaults() {
 	viper.SetDefault("log.timestamp_format", "2006-01-02 15:04:05")
 
 	// JWT配置默认值
-	viper.SetDefault("jwt.secret", "proomet-secret-key")
-}
-
-// bindEnvs 绑定环境变量
-func bindEnvs() {
-	// 服务器配置环境变量绑定
-	viper.BindEnv("server.host", "STARTER_SERVER_HOST")
-	viper.BindEnv("server.port", "STARTER_SERVER_PORT")
-	viper.BindEnv("server.environment", "STARTER_SERVER_ENVIRONMENT")
-
-	// 数据库配置环境变量绑定
-	viper.BindEnv("database.host", "STARTER_DATABASE_HOST")
-	viper.BindEnv("database.port", "STARTER_DATABASE_PORT")
-	viper.BindEnv("database.user", "STARTER_DATABASE_USER")
-	viper.BindEnv("database.password", "STARTER_DATABASE_PASSWORD")
-	viper.BindEnv("database.name", "STARTER_DATABASE_NAME")
-	viper.BindEnv("database.sslmode", "STARTER_DATABASE_SSLMODE")
-	viper.BindEnv("database.timezone", "STARTER_DATABASE_TIMEZONE")
-
-	// 日志配置环境变量绑定
-	viper.BindEnv("log.enabled", "STARTER_LOG_ENABLED")
-	viper.BindEnv("log.level", "STARTER_LOG_LEVEL")
-	viper.BindEnv("log.file", "STARTER_LOG_FILE")
-	viper.BindEnv("log.format", "STARTER_LOG_FORMAT")
-	viper.BindEnv("log.max_size", "STARTER_LOG_MAX_SIZE")
-	viper.BindEnv("log.max_backups", "STARTER_LOG_MAX_BACKUPS")
-	viper.BindEnv("log.max_age", "STARTER_LOG_MAX_AGE")
-	viper.BindEnv("log.compress", "STARTER_LOG_COMPRESS")
-	viper.BindEnv("log.enable_colors", "STARTER_LOG_ENABLE_COLORS")
-	viper.BindEnv("log.timestamp_format", "STARTER_LOG_TIMESTAMP_FORMAT")
-
-	// JWT配置环境变量绑定
-	viper.BindEnv("jwt.secret", "STARTER_JWT_SECRET")
-
-	// S3配置环境变量绑定
-	viper.BindEnv("s3.access_key_id", "STARTER_S3_ACCESS_KEY_ID")
-	viper.BindEnv("s3.secret_access_key", "STARTER_S3_SECRET_ACCESS_KEY")
-	viper.BindEnv("s3.region", "STARTER_S3_REGION")
-	viper.BindEnv("s3.endpoint", "STARTER_S3_ENDPOINT")
+	viper.SetDefault("jwt.secret", defaultJWTSecret)
+	viper.SetDefault("jwt.expired", 900)
+	viper.SetDefault("jwt.refresh_expired", 2592000)
+	viper.SetDefault("jwt.algorithm", "HS256")
+	viper.SetDefault("jwt.rotation_interval", 2592000)
+	viper.SetDefault("jwt.key_retention", 86400)
+
+	// 初始管理员默认值
+	viper.SetDefault("admin.username", "admin")
+
+	// 登录锁定默认值
+	viper.SetDefault("lockout.account_max_failures", 5)
+	viper.SetDefault("lockout.ip_max_failures", 20)
+	viper.SetDefault("lockout.window", 900)
+	viper.SetDefault("lockout.base_duration", 60)
+	viper.SetDefault("lockout.max_duration", 3600)
+
+	// 邮件配置默认值
+	viper.SetDefault("mail.driver", "file")
+	viper.SetDefault("mail.from", "proomet <no-reply@proomet.local>")
+	viper.SetDefault("mail.file_dir", "./logs/mail")
+	viper.SetDefault("mail.smtp.port", 587)
+
+	// 对象存储配置默认值
+	viper.SetDefault("storage.local.dir", "./data/ofs")
+	viper.SetDefault("storage.local.base_url", "http://localhost:7070/ofs")
+	viper.SetDefault("storage.buckets.attachments", "proomet-attachments")
+
+	// 附件配置默认值
+	viper.SetDefault("attachment.max_size", 10<<20)
+	viper.SetDefault("attachment.allowed_types", []string{
+		"image/png", "image/jpeg", "image/gif", "image/webp",
+		"application/pdf", "text/plain", "text/markdown", "text/csv", "application/json",
+	})
+	viper.SetDefault("attachment.upload_expires", 900)
+	viper.SetDefault("attachment.download_expires", 300)
 }
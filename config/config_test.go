@@ -0,0 +1,176 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// loadFile 以给定内容作为配置文件加载配置
+func loadFile(t *testing.T, content string) (*Config, error) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := read(dir); err != nil {
+		return nil, err
+	}
+	return load()
+}
+
+// writeSecret 写入密钥文件并返回路径
+func writeSecret(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	secretFile := writeSecret(t, "from-file\n")
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+		wantErr []string // 错误信息需包含的全部片段
+		check   func(t *testing.T, cfg *Config)
+	}{
+		{
+			name:    "默认配置",
+			content: "{}",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != "7070" || cfg.JWT.Secret != defaultJWTSecret || cfg.StorageDriver() != "local" {
+					t.Fatalf("默认值错误: %+v", cfg.Server)
+				}
+			},
+		},
+		{
+			name:    "未知配置项与取值错误同时报告",
+			content: "server:\n  prot: 80\n  environment: staging\n  timeout: abc\n",
+			wantErr: []string{"未知配置项 server.prot", "server.environment", "server.timeout"},
+		},
+		{
+			name:    "超时缺少单位",
+			content: "server:\n  timeout: 30\n",
+			wantErr: []string{"server.timeout 不能小于 1s"},
+		},
+		{
+			name:    "生产环境不能使用默认密钥",
+			content: "server:\n  environment: production\n",
+			wantErr: []string{"jwt.secret"},
+		},
+		{
+			name: "模拟 OIDC 提供方只能在 test 环境使用",
+			content: `oidc:
+  providers:
+    mock:
+      issuer: "http://localhost:7070/oidc/mock"
+      client_id: "proomet"
+      redirect_url: "http://localhost:7070/auth/oidc/mock/callback"
+      mock: true
+`,
+			wantErr: []string{"oidc.providers.mock.mock 只能在 test 环境使用"},
+		},
+		{
+			name:    "环境变量覆盖配置文件",
+			content: "server:\n  port: \"8080\"\n",
+			env:     map[string]string{"PROOMET_SERVER_PORT": "9090", "PROOMET_LOG_LEVEL": "debug"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != "9090" || cfg.Log.Level != "debug" {
+					t.Fatalf("环境变量未生效: port=%s level=%s", cfg.Server.Port, cfg.Log.Level)
+				}
+			},
+		},
+		{
+			name:    "可信代理地址无效",
+			content: "server:\n  trusted_proxies: [\"10.0.0.0/8\", \"proxy.local\"]\n",
+			wantErr: []string{`server.trusted_proxies 中的 "proxy.local" 不是有效的 IP 或 CIDR`},
+		},
+		{
+			name:    "默认不信任任何代理，可通过环境变量设置列表",
+			content: "{}",
+			env:     map[string]string{"PROOMET_SERVER_TRUSTED_PROXIES": "10.0.0.0/8,127.0.0.1"},
+			check: func(t *testing.T, cfg *Config) {
+				if !slices.Equal(cfg.Server.TrustedProxies, []string{"10.0.0.0/8", "127.0.0.1"}) {
+					t.Fatalf("可信代理解析错误: %v", cfg.Server.TrustedProxies)
+				}
+			},
+		},
+		{
+			name:    "配置文件引用环境变量",
+			content: "jwt:\n  secret: \"${TEST_JWT_SECRET}\"\ndatabase:\n  host: \"${TEST_DB_HOST:-db.internal}\"\n",
+			env:     map[string]string{"TEST_JWT_SECRET": "from-env"},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.JWT.Secret != "from-env" || cfg.Database.Host != "db.internal" {
+					t.Fatalf("环境变量引用未展开: secret=%s host=%s", cfg.JWT.Secret, cfg.Database.Host)
+				}
+			},
+		},
+		{
+			name:    "引用的环境变量未设置",
+			content: "jwt:\n  secret: \"${TEST_MISSING_SECRET}\"\n",
+			wantErr: []string{"TEST_MISSING_SECRET 未设置"},
+		},
+		{
+			name:    "从文件读取密钥",
+			content: "jwt:\n  secret: inline\n  secret_file: " + secretFile + "\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.JWT.Secret != "from-file" {
+					t.Fatalf("密钥文件未生效: %q", cfg.JWT.Secret)
+				}
+			},
+		},
+		{
+			name:    "通过环境变量指定密钥文件",
+			content: "{}",
+			env:     map[string]string{"PROOMET_DATABASE_PASSWORD_FILE": secretFile},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Database.Password != "from-file" {
+					t.Fatalf("密钥文件未生效: %q", cfg.Database.Password)
+				}
+			},
+		},
+		{
+			name:    "密钥文件不存在",
+			content: "jwt:\n  secret_file: /nonexistent/secret\n",
+			wantErr: []string{"jwt.secret_file 读取失败"},
+		},
+		{
+			name:    "非敏感配置项不支持 _file",
+			content: "server:\n  port_file: /tmp/port\n",
+			wantErr: []string{"未知配置项 server.port_file"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg, err := loadFile(t, tt.content)
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatal("期望校验失败")
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Fatalf("错误信息缺少 %q:\n%v", want, err)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/spf13/viper"
+)
+
+// envPrefix 环境变量前缀
+const envPrefix = "PROOMET"
+
+// legacyEnvPrefix 旧版本使用的环境变量前缀，已不再读取
+const legacyEnvPrefix = "STARTER_"
+
+// defaultJWTSecret 默认 JWT 密钥，仅用于开发，生产环境拒绝启动
+const defaultJWTSecret = "proomet-secret-key"
+
+// secretFileSuffix 敏感配置项可以通过 <key>_file 指定从文件读取（如 Docker/Kubernetes secrets）
+const secretFileSuffix = "_file"
+
+// envReference 配置文件中的环境变量引用：${NAME} 或 ${NAME:-默认值}
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// bindEnvs 为 Config 中的每个配置项绑定 PROOMET_ 前缀的环境变量，敏感配置项额外绑定 _FILE 变量
+// map 类型（如 oidc.providers）的键无法预先确定，不绑定环境变量，可在配置文件中使用 ${ENV} 引用
+func bindEnvs() {
+	for _, key := range envKeys(reflect.TypeOf(Config{}), "") {
+		viper.BindEnv(key)
+	}
+}
+
+// envKeys 按 mapstructure 标签列出可绑定环境变量的配置项
+func envKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" || key == "-" {
+			continue
+		}
+		key = prefix + key
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			keys = append(keys, envKeys(field.Type, key+".")...)
+		case reflect.Map:
+		default:
+			keys = append(keys, key)
+			if field.Tag.Get("secret") == "true" {
+				keys = append(keys, key+secretFileSuffix)
+			}
+		}
+	}
+	return keys
+}
+
+// warnLegacyEnvs 提示仍在使用旧前缀的环境变量
+func warnLegacyEnvs() {
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		if strings.HasPrefix(name, legacyEnvPrefix) {
+			log.Printf("环境变量 %s 已不再生效，请改为 %s_%s", name, envPrefix, strings.TrimPrefix(name, legacyEnvPrefix))
+		}
+	}
+}
+
+// decodeHook 解析配置时先展开 ${ENV} 引用，再按 viper 默认规则转换时长与列表
+func decodeHook() viper.DecoderConfigOption {
+	return viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		expandEnvHook,
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))
+}
+
+// expandEnvHook 展开字符串中的 ${NAME}、${NAME:-默认值}，引用的环境变量未设置且没有默认值时报错
+func expandEnvHook(from, to reflect.Type, data any) (any, error) {
+	value, ok := data.(string)
+	if from.Kind() != reflect.String || !ok || !strings.Contains(value, "${") {
+		return data, nil
+	}
+	var missing []string
+	expanded := envReference.ReplaceAllStringFunc(value, func(ref string) string {
+		matches := envReference.FindStringSubmatch(ref)
+		if env, ok := os.LookupEnv(matches[1]); ok {
+			return env
+		}
+		if matches[2] != "" {
+			return matches[3]
+		}
+		missing = append(missing, matches[1])
+		return ""
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("引用的环境变量 %s 未设置", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
+
+// readSecretFiles 敏感配置项设置了 <key>_file 时读取文件内容作为取值（去掉末尾换行），优先于直接配置的值
+func readSecretFiles(v reflect.Value, prefix string) []error {
+	var errs []error
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := t.Field(i)
+			key := field.Tag.Get("mapstructure")
+			if key == "" || key == "-" {
+				continue
+			}
+			key = prefix + key
+			if field.Tag.Get("secret") != "true" {
+				errs = append(errs, readSecretFiles(v.Field(i), key+".")...)
+				continue
+			}
+			path := viper.GetString(key + secretFileSuffix)
+			if path == "" {
+				continue
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s%s 读取失败: %v", key, secretFileSuffix, err))
+				continue
+			}
+			v.Field(i).SetString(strings.TrimRight(string(content), "\r\n"))
+		}
+	case reflect.Map:
+		// map 的值不可寻址，复制后修改再写回
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			errs = append(errs, readSecretFiles(elem, fmt.Sprintf("%s%v.", prefix, iter.Key().Interface()))...)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,315 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// 取值范围固定的配置项
+var (
+	environments   = []string{"development", "production", "test"}
+	logFormats     = []string{"text", "json"}
+	sslModes       = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	jwtAlgorithms  = []string{"HS256", "RS256", "EdDSA"}
+	storageDrivers = []string{"s3", "local"}
+	mailDrivers    = []string{"smtp", "file"}
+	smtpTLSModes   = []string{"", "starttls", "tls"}
+)
+
+// maxPresignExpires 预签名 URL 最长有效期，S3 不支持超过 7 天
+const maxPresignExpires = 7 * 24 * 3600
+
+// StorageDriver 实际使用的对象存储类型，storage.driver 未设置时按 s3.enabled 选择
+func (c *Config) StorageDriver() string {
+	if c.Storage.Driver != "" {
+		return c.Storage.Driver
+	}
+	if c.S3.Enabled {
+		return "s3"
+	}
+	return "local"
+}
+
+// IsProduction 是否为生产环境
+func (c *Config) IsProduction() bool {
+	return c.Server.Environment == "production"
+}
+
+// load 从 viper 解析配置并校验，返回全部问题而不是第一个
+func load() (*Config, error) {
+	var errs []error
+	for _, key := range unknownKeys(viper.AllKeys()) {
+		errs = append(errs, fmt.Errorf("未知配置项 %s", key))
+	}
+
+	// 类型错误的字段保持零值，其余字段继续校验，跳过与类型错误字段重复的提示
+	cfg := &Config{}
+	invalid := decodeErrors(viper.Unmarshal(cfg, decodeHook()))
+	invalidKeys := slices.Sorted(maps.Keys(invalid))
+	for _, key := range invalidKeys {
+		errs = append(errs, fmt.Errorf("%s 取值无效: %v", key, invalid[key]))
+	}
+	errs = append(errs, readSecretFiles(reflect.ValueOf(cfg).Elem(), "")...)
+	for _, err := range validate(cfg) {
+		duplicated := slices.ContainsFunc(invalidKeys, func(key string) bool {
+			return strings.Contains(err.Error(), key)
+		})
+		if !duplicated {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decodeErrors 按配置项展开解析错误
+func decodeErrors(err error) map[string]error {
+	invalid := map[string]error{}
+	var walk func(err error)
+	walk = func(err error) {
+		var decodeErr *mapstructure.DecodeError
+		var joined interface{ Unwrap() []error }
+		switch {
+		case err == nil:
+		case errors.As(err, &joined):
+			for _, e := range joined.Unwrap() {
+				walk(e)
+			}
+		case errors.As(err, &decodeErr):
+			invalid[decodeErr.Name()] = decodeErr.Unwrap()
+		default:
+			invalid["config"] = err
+		}
+	}
+	walk(err)
+	return invalid
+}
+
+// unknownKeys 返回 Config 中不存在的配置项（通常是拼写错误）
+func unknownKeys(keys []string) []string {
+	var unknown []string
+	for _, key := range keys {
+		if !knownKey(reflect.TypeOf(Config{}), strings.Split(key, ".")) {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// knownKey 按 mapstructure 标签判断配置路径是否存在，map 类型的键不做限制
+// 敏感配置项额外允许 <key>_file
+func knownKey(t reflect.Type, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			key := field.Tag.Get("mapstructure")
+			if key == path[0] {
+				return knownKey(field.Type, path[1:])
+			}
+			if key+secretFileSuffix == path[0] && field.Tag.Get("secret") == "true" {
+				return len(path) == 1
+			}
+		}
+		return false
+	case reflect.Map:
+		return knownKey(t.Elem(), path[1:])
+	case reflect.Pointer:
+		return knownKey(t.Elem(), path)
+	default:
+		return false
+	}
+}
+
+// checker 收集校验错误
+type checker struct {
+	errs []error
+}
+
+// check ok 为 false 时记录错误
+func (c *checker) check(ok bool, format string, args ...any) {
+	if !ok {
+		c.errs = append(c.errs, fmt.Errorf(format, args...))
+	}
+}
+
+// oneOf 校验取值在允许范围内
+func (c *checker) oneOf(key, value string, allowed []string) {
+	c.check(slices.Contains(allowed, value), "%s 取值 %q 无效，可选: %s", key, value, strings.Join(allowed, ", "))
+}
+
+// port 校验端口号
+func (c *checker) port(key string, value int) {
+	c.check(value > 0 && value <= 65535, "%s 端口 %d 无效，范围为 1-65535", key, value)
+}
+
+// portString 校验字符串形式的端口号
+func (c *checker) portString(key, value string) {
+	port, err := strconv.Atoi(value)
+	if err != nil {
+		c.check(false, "%s 端口 %q 无效，必须为数字", key, value)
+		return
+	}
+	c.port(key, port)
+}
+
+// url 校验绝对 URL
+func (c *checker) url(key, value string) {
+	u, err := url.Parse(value)
+	c.check(err == nil && u.Scheme != "" && u.Host != "", "%s 不是有效的 URL: %q", key, value)
+}
+
+// validate 逐项校验配置，不合法的配置不会被加载
+func validate(cfg *Config) []error {
+	c := &checker{}
+
+	// 服务器
+	server := cfg.Server
+	c.portString("server.port", server.Port)
+	c.oneOf("server.environment", server.Environment, environments)
+	c.check(server.Timeout >= time.Second, "server.timeout 不能小于 1s，需要带单位，例如 30s")
+	c.check(server.IdleTimeout >= 0, "server.idle_timeout 不能小于 0")
+	c.check(server.TransferTimeout >= server.Timeout, "server.transfer_timeout 不能小于 server.timeout")
+	c.check(server.ShutdownTimeout >= time.Second, "server.shutdown_timeout 不能小于 1s，需要带单位，例如 30s")
+	c.check(!server.EnableCORS || len(server.CORSOrigins) > 0, "启用 CORS 时 server.cors_origins 不能为空")
+	for _, proxy := range server.TrustedProxies {
+		_, _, err := net.ParseCIDR(proxy)
+		c.check(err == nil || net.ParseIP(proxy) != nil, "server.trusted_proxies 中的 %q 不是有效的 IP 或 CIDR", proxy)
+	}
+
+	// 数据库
+	db := cfg.Database
+	c.check(db.Host != "", "database.host 不能为空")
+	c.portString("database.port", db.Port)
+	c.check(db.Name != "", "database.name 不能为空")
+	c.oneOf("database.sslmode", db.SSLMode, sslModes)
+	if db.Timezone != "" {
+		_, err := time.LoadLocation(db.Timezone)
+		c.check(err == nil, "database.timezone 时区 %q 无效", db.Timezone)
+	}
+
+	// 日志
+	logCfg := cfg.Log
+	_, err := logrus.ParseLevel(logCfg.Level)
+	c.check(err == nil, "log.level 日志级别 %q 无效，可选: trace, debug, info, warn, error, fatal, panic", logCfg.Level)
+	c.oneOf("log.format", logCfg.Format, logFormats)
+	if logCfg.Enabled {
+		c.check(logCfg.File != "", "启用日志文件时 log.file 不能为空")
+		c.check(logCfg.MaxSize > 0, "log.max_size 必须大于 0")
+	}
+	c.check(logCfg.MaxBackups >= 0, "log.max_backups 不能小于 0")
+	c.check(logCfg.MaxAge >= 0, "log.max_age 不能小于 0")
+
+	// JWT
+	jwt := cfg.JWT
+	c.check(jwt.Secret != "", "jwt.secret 不能为空")
+	c.check(jwt.Expired > 0, "jwt.expired 必须大于 0")
+	c.check(jwt.RefreshExpired > 0, "jwt.refresh_expired 必须大于 0")
+	c.check(jwt.RefreshExpired >= jwt.Expired, "jwt.refresh_expired 不能小于 jwt.expired")
+	c.oneOf("jwt.algorithm", jwt.Algorithm, jwtAlgorithms)
+	c.check(jwt.RotationInterval >= 0, "jwt.rotation_interval 不能小于 0")
+	if jwt.Algorithm != "HS256" {
+		c.check(jwt.KeyRetention >= jwt.Expired, "jwt.key_retention 不能小于 jwt.expired，否则轮换后未过期的令牌无法验证")
+	}
+
+	// 对象存储
+	if cfg.Storage.Driver != "" {
+		c.oneOf("storage.driver", cfg.Storage.Driver, storageDrivers)
+	}
+	switch cfg.StorageDriver() {
+	case "s3":
+		c.check(cfg.S3.Region != "", "使用 s3 存储时 s3.region 不能为空")
+		if cfg.S3.Endpoint != "" {
+			c.url("s3.endpoint", cfg.S3.Endpoint)
+		}
+	case "local":
+		c.check(cfg.Storage.Local.Dir != "", "使用 local 存储时 storage.local.dir 不能为空")
+		c.url("storage.local.base_url", cfg.Storage.Local.BaseURL)
+	}
+	c.check(cfg.Storage.Buckets.Attachments != "", "storage.buckets.attachments 不能为空")
+
+	// 附件
+	attachment := cfg.Attachment
+	c.check(attachment.MaxSize > 0, "attachment.max_size 必须大于 0")
+	c.check(len(attachment.AllowedTypes) > 0, "attachment.allowed_types 不能为空")
+	c.check(attachment.UploadExpires > 0 && attachment.UploadExpires <= maxPresignExpires,
+		"attachment.upload_expires 必须在 1-%d 秒之间", maxPresignExpires)
+	c.check(attachment.DownloadExpires > 0 && attachment.DownloadExpires <= maxPresignExpires,
+		"attachment.download_expires 必须在 1-%d 秒之间", maxPresignExpires)
+
+	// 登录锁定
+	lockout := cfg.Lockout
+	c.check(lockout.AccountMaxFailures > 0, "lockout.account_max_failures 必须大于 0")
+	c.check(lockout.IPMaxFailures > 0, "lockout.ip_max_failures 必须大于 0")
+	c.check(lockout.Window > 0, "lockout.window 必须大于 0")
+	c.check(lockout.BaseDuration > 0, "lockout.base_duration 必须大于 0")
+	c.check(lockout.MaxDuration >= lockout.BaseDuration, "lockout.max_duration 不能小于 lockout.base_duration")
+
+	// 邮件
+	mail := cfg.Mail
+	c.oneOf("mail.driver", mail.Driver, mailDrivers)
+	c.check(mail.From != "", "mail.from 不能为空")
+	switch mail.Driver {
+	case "smtp":
+		c.check(mail.SMTP.Host != "", "使用 smtp 发送邮件时 mail.smtp.host 不能为空")
+		c.port("mail.smtp.port", mail.SMTP.Port)
+		c.oneOf("mail.smtp.tls", mail.SMTP.TLS, smtpTLSModes)
+	case "file":
+		c.check(mail.FileDir != "", "使用 file 发送邮件时 mail.file_dir 不能为空")
+	}
+	if mail.LinkBaseURL != "" {
+		c.url("mail.link_base_url", mail.LinkBaseURL)
+	}
+
+	// OIDC
+	names := make([]string, 0, len(cfg.OIDC.Providers))
+	for name := range cfg.OIDC.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		provider := cfg.OIDC.Providers[name]
+		key := "oidc.providers." + name
+		c.url(key+".issuer", provider.Issuer)
+		c.check(provider.ClientID != "", "%s.client_id 不能为空", key)
+		c.url(key+".redirect_url", provider.RedirectURL)
+		c.check(!provider.Mock || server.Environment == "test", "%s.mock 只能在 test 环境使用", key)
+	}
+
+	// 生产环境必须配置的密钥
+	if cfg.IsProduction() {
+		c.check(jwt.Secret != defaultJWTSecret, "生产环境不能使用默认的 jwt.secret，请通过 %s_JWT_SECRET、jwt.secret_file 或 ${ENV} 引用设置", envPrefix)
+		c.check(db.Password != "", "生产环境 database.password 不能为空")
+		if cfg.StorageDriver() == "s3" {
+			c.check(cfg.S3.AccessKeyID != "", "生产环境 s3.access_key_id 不能为空")
+			c.check(cfg.S3.SecretAccessKey != "", "生产环境 s3.secret_access_key 不能为空")
+		}
+		if mail.Driver == "smtp" && mail.SMTP.Username != "" {
+			c.check(mail.SMTP.Password != "", "生产环境 mail.smtp.password 不能为空")
+		}
+		for _, name := range names {
+			provider := cfg.OIDC.Providers[name]
+			c.check(provider.ClientSecret != "", "生产环境 oidc.providers.%s.client_secret 不能为空", name)
+		}
+	}
+	return c.errs
+}
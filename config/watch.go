@@ -0,0 +1,229 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// redactedValue 敏感配置项脱敏后的显示值
+const redactedValue = "******"
+
+// reloadDelay 配置文件变化后等待的时间，编辑器保存时通常会触发多次写入事件
+const reloadDelay = 200 * time.Millisecond
+
+// Snapshot 一次成功加载的配置，加载后不再修改
+type Snapshot struct {
+	Config   *Config
+	Version  int64
+	LoadedAt time.Time
+}
+
+// Subscriber 配置变更回调，在配置替换后按注册顺序调用
+type Subscriber func(old, new *Config)
+
+var (
+	current     atomic.Pointer[Snapshot]
+	subscribers []Subscriber
+	// reloadMu 串行化重新加载与订阅者通知
+	reloadMu  sync.Mutex
+	watchOnce sync.Once
+)
+
+// Get 获取当前配置，返回值只读，重新加载时整体替换而不是原地修改
+func Get() *Config {
+	if snapshot := current.Load(); snapshot != nil {
+		return snapshot.Config
+	}
+	return nil
+}
+
+// Current 获取当前配置快照（含版本号与加载时间）
+func Current() *Snapshot {
+	return current.Load()
+}
+
+// Set 直接替换当前配置，不通知订阅者
+func Set(cfg *Config) {
+	store(cfg)
+}
+
+// Subscribe 注册配置变更回调
+func Subscribe(fn Subscriber) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	subscribers = append(subscribers, fn)
+}
+
+// Watch 监听配置文件变化并自动重新加载，校验失败时保留原配置
+func Watch() {
+	if viper.ConfigFileUsed() == "" {
+		log.Println("未使用配置文件，跳过配置热加载")
+		return
+	}
+	watchOnce.Do(func() {
+		var (
+			timerMu sync.Mutex
+			timer   *time.Timer
+		)
+		viper.OnConfigChange(func(e fsnotify.Event) {
+			timerMu.Lock()
+			defer timerMu.Unlock()
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.AfterFunc(reloadDelay, reloadFile)
+		})
+		viper.WatchConfig()
+	})
+}
+
+// reloadFile 配置文件变化后重新加载，文件为空时视为写入未完成
+func reloadFile() {
+	if info, err := os.Stat(viper.ConfigFileUsed()); err != nil || info.Size() == 0 {
+		log.Println("配置文件为空或不可读，忽略本次变化")
+		return
+	}
+	if err := Reload(); err != nil {
+		log.Printf("配置重新加载失败，继续使用版本 %d: %v", Current().Version, err)
+	}
+}
+
+// Reload 重新解析配置并校验，成功且内容有变化时替换当前配置并通知订阅者
+func Reload() error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	cfg, err := load()
+	if err != nil {
+		return err
+	}
+	old := Get()
+	if reflect.DeepEqual(old, cfg) {
+		return nil
+	}
+
+	snapshot := store(cfg)
+	log.Printf("配置已重新加载，版本: %d", snapshot.Version)
+	if fields := restartRequired(old, cfg); len(fields) > 0 {
+		log.Printf("以下配置修改需要重启服务才能生效: %v", fields)
+	}
+	for _, fn := range subscribers {
+		notify(fn, old, cfg)
+	}
+	return nil
+}
+
+// Redacted 返回配置的键值结构（键与配置文件一致），敏感项替换为 ******
+func Redacted(cfg *Config) map[string]any {
+	return redact(reflect.ValueOf(cfg).Elem()).(map[string]any)
+}
+
+// store 保存新的配置快照，版本号递增
+func store(cfg *Config) *Snapshot {
+	snapshot := &Snapshot{Config: cfg, Version: 1, LoadedAt: time.Now()}
+	if previous := current.Load(); previous != nil {
+		snapshot.Version = previous.Version + 1
+	}
+	current.Store(snapshot)
+	return snapshot
+}
+
+// restartRequired 返回修改后需要重启才能生效的配置项
+func restartRequired(old, new *Config) []string {
+	if old == nil {
+		return nil
+	}
+	checks := map[string][2]any{
+		"server.host":            {old.Server.Host, new.Server.Host},
+		"server.port":            {old.Server.Port, new.Server.Port},
+		"server.timeout":         {old.Server.Timeout, new.Server.Timeout},
+		"server.idle_timeout":    {old.Server.IdleTimeout, new.Server.IdleTimeout},
+		"server.trusted_proxies": {old.Server.TrustedProxies, new.Server.TrustedProxies},
+		"database":               {old.Database, new.Database},
+		"log.enabled":            {old.Log.Enabled, new.Log.Enabled},
+		"log.file":               {old.Log.File, new.Log.File},
+		"s3":                     {old.S3, new.S3},
+		"storage":                {old.Storage, new.Storage},
+		"jwt.algorithm":          {old.JWT.Algorithm, new.JWT.Algorithm},
+		"jwt.secret":             {old.JWT.Secret, new.JWT.Secret},
+		"oidc":                   {old.OIDC, new.OIDC},
+		"mail":                   {old.Mail, new.Mail},
+		"server.environment":     {old.Server.Environment, new.Server.Environment},
+	}
+	var fields []string
+	for name, values := range checks {
+		if !reflect.DeepEqual(values[0], values[1]) {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// notify 调用订阅者，单个订阅者 panic 不影响其他订阅者
+func notify(fn Subscriber, old, new *Config) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("配置变更回调执行失败: %v", r)
+		}
+	}()
+	fn(old, new)
+}
+
+// redact 按 mapstructure 标签生成键值结构，带 secret 标签的非空字段替换为 ******
+func redact(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Struct:
+		out := make(map[string]any, v.NumField())
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := t.Field(i)
+			key := field.Tag.Get("mapstructure")
+			if key == "" || key == "-" {
+				continue
+			}
+			if field.Tag.Get("secret") == "true" {
+				if v.Field(i).IsZero() {
+					out[key] = ""
+				} else {
+					out[key] = redactedValue
+				}
+				continue
+			}
+			out[key] = redact(v.Field(i))
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out[fmt.Sprint(iter.Key().Interface())] = redact(iter.Value())
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		out := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = redact(v.Index(i))
+		}
+		return out
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return redact(v.Elem())
+	default:
+		if duration, ok := v.Interface().(time.Duration); ok {
+			return duration.String()
+		}
+		return v.Interface()
+	}
+}
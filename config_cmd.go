@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"proomet/config"
+)
+
+const configUsage = `用法: proomet config <命令>
+
+命令:
+  check [dir]    校验配置文件（默认读取当前目录下的 config.yaml），输出全部问题，不通过时返回非 0
+`
+
+// runConfig 执行 config 子命令
+func runConfig(args []string) {
+	if len(args) == 0 || args[0] != "check" || len(args) > 2 {
+		fmt.Fprint(os.Stderr, configUsage)
+		os.Exit(2)
+	}
+
+	dir := ""
+	if len(args) == 2 {
+		dir = args[1]
+	}
+	file, err := config.Check(dir)
+	if file == "" {
+		file = "默认配置"
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "配置校验失败: %s\n", file)
+		for _, problem := range problems(err) {
+			fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		os.Exit(1)
+	}
+	fmt.Printf("配置校验通过: %s\n", file)
+}
+
+// problems 展开聚合的错误
+func problems(err error) []string {
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) {
+		return []string{err.Error()}
+	}
+	var list []string
+	for _, e := range joined.Unwrap() {
+		list = append(list, problems(e)...)
+	}
+	return list
+}
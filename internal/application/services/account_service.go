@@ -0,0 +1,255 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"proomet/config"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"proomet/internal/infra/mailer"
+	"proomet/internal/interfaces/dto"
+	"proomet/pkg/utils"
+	"proomet/pkg/utils/jwt"
+	"proomet/pkg/utils/res"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// verifyEmailTTL 邮箱验证链接有效期
+	verifyEmailTTL = 24 * time.Hour
+	// resetPasswordTTL 重置密码链接有效期
+	resetPasswordTTL = 30 * time.Minute
+	// actionEmailInterval 同一用户同类邮件的最小发送间隔
+	actionEmailInterval = time.Minute
+	// mailSendTimeout 单封邮件发送超时时间
+	mailSendTimeout = 30 * time.Second
+)
+
+type AccountService struct{}
+
+// RequestEmailVerification 向当前用户的邮箱发送验证邮件
+func (s *AccountService) RequestEmailVerification(user models.JwtUser) error {
+	if user.UserID == 0 {
+		return res.ErrUnauthorized
+	}
+	account, err := findUser(user.UserID)
+	if err != nil {
+		return err
+	}
+	if account.Email == "" {
+		return res.ErrInvalidParam.Msg("请先设置邮箱")
+	}
+	if account.EmailVerifiedAt != nil {
+		return res.ErrInvalidParam.Msg("邮箱已验证")
+	}
+
+	sent, err := sendActionEmail(database.GetDB(), account, models.ActionVerifyEmail)
+	if err != nil {
+		return res.ErrInternalServer.Msg("发送验证邮件失败")
+	}
+	if !sent {
+		return res.ErrInvalidParam.Msg("邮件发送过于频繁，请稍后再试")
+	}
+	return nil
+}
+
+// ConfirmEmail 使用验证链接中的令牌完成邮箱验证
+func (s *AccountService) ConfirmEmail(dto *dto.ConfirmEmailDto) error {
+	claims, err := jwt.ParseActionToken(dto.Token, models.ActionVerifyEmail)
+	if err != nil {
+		return res.ErrActionTokenInvalid
+	}
+	db := database.GetDB()
+
+	var reject error
+	err = db.Transaction(func(tx *gorm.DB) error {
+		user, ok, err := consumeActionToken(tx, claims, models.ActionVerifyEmail)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			reject = res.ErrActionTokenInvalid
+			return nil
+		}
+		if user.EmailVerifiedAt != nil {
+			return nil
+		}
+		return tx.Model(user).Update("email_verified_at", time.Now()).Error
+	})
+	if err != nil {
+		return res.ErrInternalServer.Msg("验证邮箱失败")
+	}
+	return reject
+}
+
+// ForgotPassword 向邮箱发送重置密码邮件
+// 无论邮箱是否存在都返回成功，避免通过该接口探测已注册的邮箱
+func (s *AccountService) ForgotPassword(dto *dto.ForgotPasswordDto) error {
+	db := database.GetDB()
+
+	var user models.User
+	if err := db.Where("LOWER(email) = ?", strings.ToLower(dto.Email)).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return res.ErrInternalServer.Msg("查询用户失败")
+	}
+	if user.Status == models.UserStatusDisabled {
+		return nil
+	}
+
+	if _, err := sendActionEmail(db, &user, models.ActionResetPassword); err != nil {
+		return res.ErrInternalServer.Msg("发送重置密码邮件失败")
+	}
+	return nil
+}
+
+// ResetPassword 使用重置链接中的令牌设置新密码，并吊销该用户的全部刷新令牌
+func (s *AccountService) ResetPassword(dto *dto.ResetPasswordDto) error {
+	claims, err := jwt.ParseActionToken(dto.Token, models.ActionResetPassword)
+	if err != nil {
+		return res.ErrActionTokenInvalid
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(dto.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return res.ErrInternalServer.Msg("密码加密失败")
+	}
+	db := database.GetDB()
+
+	var reject error
+	err = db.Transaction(func(tx *gorm.DB) error {
+		user, ok, err := consumeActionToken(tx, claims, models.ActionResetPassword)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			reject = res.ErrActionTokenInvalid
+			return nil
+		}
+		if user.Status == models.UserStatusDisabled {
+			reject = res.ErrAccountDisabled
+			return nil
+		}
+
+		updates := map[string]any{"password_hash": string(hashedPassword)}
+		// 能够收到重置邮件即证明邮箱归属
+		if user.EmailVerifiedAt == nil {
+			updates["email_verified_at"] = time.Now()
+		}
+		if err := tx.Model(user).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", time.Now()).Error
+	})
+	if err != nil {
+		return res.ErrInternalServer.Msg("重置密码失败")
+	}
+	return reject
+}
+
+// sendActionEmail 签发操作令牌并异步发送邮件，发送间隔过短时不发送并返回 false
+func sendActionEmail(db *gorm.DB, user *models.User, purpose string) (bool, error) {
+	var recent int64
+	if err := db.Model(&models.ActionToken{}).
+		Where("user_id = ? AND purpose = ? AND created_at > ?", user.ID, purpose, time.Now().Add(-actionEmailInterval)).
+		Count(&recent).Error; err != nil {
+		return false, err
+	}
+	if recent > 0 {
+		return false, nil
+	}
+
+	ttl := verifyEmailTTL
+	if purpose == models.ActionResetPassword {
+		ttl = resetPasswordTTL
+	}
+	token, jti, err := jwt.GenerateActionToken(user.ID, user.Email, purpose, ttl)
+	if err != nil {
+		return false, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// 清理过期令牌，并使同类型的旧令牌失效
+		if err := tx.Where("expires_at < ?", time.Now()).Delete(&models.ActionToken{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ActionToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", user.ID, purpose).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ActionToken{
+			JTI:       jti,
+			UserID:    user.ID,
+			Purpose:   purpose,
+			ExpiresAt: time.Now().Add(ttl),
+		}).Error
+	})
+	if err != nil {
+		return false, err
+	}
+
+	msg := actionEmail(user, purpose, token, ttl)
+	mailer.SendAsync(msg, mailSendTimeout, func(err error) {
+		utils.Log.Errorf("发送邮件失败，用户: %d，类型: %s，错误: %v", user.ID, purpose, err)
+	})
+	return true, nil
+}
+
+// consumeActionToken 将操作令牌标记为已使用，令牌已使用、已失效或邮箱已变更时返回 false
+func consumeActionToken(tx *gorm.DB, claims *jwt.ActionClaims, purpose string) (*models.User, bool, error) {
+	result := tx.Model(&models.ActionToken{}).
+		Where("jti = ? AND user_id = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", claims.ID, claims.UserID, purpose, time.Now()).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, false, nil
+	}
+
+	var user models.User
+	if err := tx.First(&user, claims.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	if !strings.EqualFold(user.Email, claims.Email) {
+		return nil, false, nil
+	}
+	return &user, true, nil
+}
+
+// actionEmail 生成邮箱验证或重置密码邮件
+func actionEmail(user *models.User, purpose, token string, ttl time.Duration) *mailer.Message {
+	base := strings.TrimRight(config.Get().Mail.LinkBaseURL, "/")
+	name := utils.DefaultString(user.Nickname, user.Username)
+
+	if purpose == models.ActionResetPassword {
+		link := fmt.Sprintf("%s/reset-password?token=%s", base, url.QueryEscape(token))
+		return &mailer.Message{
+			To:      []string{user.Email},
+			Subject: "proomet 重置密码",
+			Body: fmt.Sprintf("%s，你好：\n\n我们收到了重置 proomet 账号密码的请求，请在 %d 分钟内打开以下链接设置新密码：\n\n%s\n\n"+
+				"如果不是你本人操作，请忽略本邮件，你的密码不会被修改。\n\n令牌：%s\n",
+				name, int(ttl.Minutes()), link, token),
+		}
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", base, url.QueryEscape(token))
+	return &mailer.Message{
+		To:      []string{user.Email},
+		Subject: "proomet 邮箱验证",
+		Body: fmt.Sprintf("%s，你好：\n\n请在 %d 小时内打开以下链接完成邮箱验证：\n\n%s\n\n"+
+			"如果你没有注册 proomet 账号，请忽略本邮件。\n\n令牌：%s\n",
+			name, int(ttl.Hours()), link, token),
+	}
+}
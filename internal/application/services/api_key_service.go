@@ -0,0 +1,165 @@
+package services
+
+import (
+	"errors"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/auth"
+	"proomet/internal/infra/database"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils/converter"
+	"proomet/pkg/utils/res"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyService struct{}
+
+// Create 创建 API Key，完整密钥只在返回结果中出现一次
+func (s *APIKeyService) Create(user models.JwtUser, dto *dto.CreateAPIKeyDto) (*vo.APIKeyCreatedVO, error) {
+	if user.UserID == 0 {
+		return nil, res.ErrUnauthorized
+	}
+	if dto.ExpiresAt != nil && !dto.ExpiresAt.After(time.Now()) {
+		return nil, res.ErrInvalidParam.Msg("过期时间必须晚于当前时间")
+	}
+	if dto.WorkspaceID != nil {
+		if _, _, err := findWorkspaceFor(user, *dto.WorkspaceID, models.WorkspaceRoleAdmin); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkAPIKeyScopes(dto.WorkspaceID, dto.Scopes); err != nil {
+		return nil, err
+	}
+
+	key, prefix, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("生成 API Key 失败")
+	}
+	apiKey := models.APIKey{
+		Name:        dto.Name,
+		UserID:      user.UserID,
+		WorkspaceID: dto.WorkspaceID,
+		Prefix:      prefix,
+		KeyHash:     auth.HashAPIKey(key),
+		Scopes:      slices.Compact(slices.Sorted(slices.Values(dto.Scopes))),
+		ExpiresAt:   dto.ExpiresAt,
+	}
+	if err := database.GetDB().Create(&apiKey).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("创建 API Key 失败")
+	}
+
+	return &vo.APIKeyCreatedVO{APIKeyVO: *toAPIKeyVO(&apiKey), Key: key}, nil
+}
+
+// List 查询个人 API Key，或指定工作区的 API Key（需要工作区管理员及以上角色）
+func (s *APIKeyService) List(user models.JwtUser, dto *dto.ListAPIKeyDto) ([]vo.APIKeyVO, error) {
+	query := database.GetDB().Model(&models.APIKey{})
+	if dto.WorkspaceID != 0 {
+		if _, _, err := findWorkspaceFor(user, dto.WorkspaceID, models.WorkspaceRoleAdmin); err != nil {
+			return nil, err
+		}
+		query = query.Where("workspace_id = ?", dto.WorkspaceID)
+	} else {
+		query = query.Where("user_id = ? AND workspace_id IS NULL", user.UserID)
+	}
+
+	var apiKeys []models.APIKey
+	if err := query.Order("created_at DESC").Find(&apiKeys).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询 API Key 失败")
+	}
+
+	list := make([]vo.APIKeyVO, 0, len(apiKeys))
+	for i := range apiKeys {
+		list = append(list, *toAPIKeyVO(&apiKeys[i]))
+	}
+	return list, nil
+}
+
+// Get 获取 API Key 详情
+func (s *APIKeyService) Get(user models.JwtUser, id uint) (*vo.APIKeyVO, error) {
+	apiKey, err := findAPIKeyFor(user, id)
+	if err != nil {
+		return nil, err
+	}
+	return toAPIKeyVO(apiKey), nil
+}
+
+// Update 更新 API Key 名称与权限范围
+func (s *APIKeyService) Update(user models.JwtUser, id uint, dto *dto.UpdateAPIKeyDto) (*vo.APIKeyVO, error) {
+	apiKey, err := findAPIKeyFor(user, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.Name != nil {
+		apiKey.Name = *dto.Name
+	}
+	if dto.Scopes != nil {
+		if err := checkAPIKeyScopes(apiKey.WorkspaceID, dto.Scopes); err != nil {
+			return nil, err
+		}
+		apiKey.Scopes = slices.Compact(slices.Sorted(slices.Values(dto.Scopes)))
+	}
+	if err := database.GetDB().Select("name", "scopes").Save(apiKey).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("更新 API Key 失败")
+	}
+	return toAPIKeyVO(apiKey), nil
+}
+
+// Delete 吊销 API Key，立即生效
+func (s *APIKeyService) Delete(user models.JwtUser, id uint) error {
+	apiKey, err := findAPIKeyFor(user, id)
+	if err != nil {
+		return err
+	}
+	if err := database.GetDB().Delete(apiKey).Error; err != nil {
+		return res.ErrInternalServer.Msg("删除 API Key 失败")
+	}
+	return nil
+}
+
+// findAPIKeyFor 查询当前用户可管理的 API Key：个人密钥仅创建者可管理，工作区密钥由工作区管理员管理
+func findAPIKeyFor(user models.JwtUser, id uint) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	if err := database.GetDB().First(&apiKey, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, res.ErrAPIKeyNotFound
+		}
+		return nil, res.ErrInternalServer.Msg("查询 API Key 失败")
+	}
+
+	if apiKey.WorkspaceID != nil {
+		if _, _, err := findWorkspaceFor(user, *apiKey.WorkspaceID, models.WorkspaceRoleAdmin); err != nil {
+			return nil, res.ErrAPIKeyNotFound
+		}
+		return &apiKey, nil
+	}
+	if user.Role != models.RoleAdmin && apiKey.UserID != user.UserID {
+		return nil, res.ErrAPIKeyNotFound
+	}
+	return &apiKey, nil
+}
+
+// checkAPIKeyScopes 校验权限范围：工作区密钥只能访问提示词与标签
+func checkAPIKeyScopes(workspaceID *uint, scopes []string) error {
+	allowed := models.APIKeyScopes
+	if workspaceID != nil {
+		allowed = models.WorkspaceAPIKeyScopes
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(allowed, scope) {
+			return res.ErrInvalidParam.Msgf("权限范围 %s 不可用于该类型的 API Key", scope)
+		}
+	}
+	return nil
+}
+
+// toAPIKeyVO 模型转换为VO
+func toAPIKeyVO(apiKey *models.APIKey) *vo.APIKeyVO {
+	var apiKeyVO vo.APIKeyVO
+	converter.SafeConvert(&apiKeyVO, apiKey)
+	return &apiKeyVO
+}
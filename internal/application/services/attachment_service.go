@@ -0,0 +1,441 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"path/filepath"
+	"proomet/config"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"proomet/internal/infra/ofs"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils"
+	"proomet/pkg/utils/converter"
+	"proomet/pkg/utils/res"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// attachmentSniffLen 识别文件类型读取的字节数，与 http.DetectContentType 一致
+const attachmentSniffLen = 512
+
+// attachmentTextTypes 内容识别为纯文本时，按扩展名细分的文本类型
+var attachmentTextTypes = map[string]string{
+	".md":       "text/markdown",
+	".markdown": "text/markdown",
+	".csv":      "text/csv",
+	".json":     "application/json",
+}
+
+// attachmentTypeExts 对象键的扩展名，由校验后的内容类型决定，不采用客户端文件名中的扩展名
+var attachmentTypeExts = map[string]string{
+	"image/png":        ".png",
+	"image/jpeg":       ".jpg",
+	"image/gif":        ".gif",
+	"image/webp":       ".webp",
+	"application/pdf":  ".pdf",
+	"text/plain":       ".txt",
+	"text/markdown":    ".md",
+	"text/csv":         ".csv",
+	"application/json": ".json",
+}
+
+type AttachmentService struct{}
+
+// List 查询提示词的附件
+func (s *AttachmentService) List(user models.JwtUser, promptID uint) ([]vo.AttachmentVO, error) {
+	prompt, err := findPrompt(promptID)
+	if err != nil {
+		return nil, err
+	}
+	if !canReadPrompt(user, prompt) {
+		return nil, res.ErrPromptNotFound
+	}
+
+	var attachments []models.Attachment
+	if err := database.GetDB().
+		Where("prompt_id = ? AND status = ?", promptID, models.AttachmentStatusReady).
+		Order("id ASC").
+		Find(&attachments).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询附件失败")
+	}
+
+	list := make([]vo.AttachmentVO, 0, len(attachments))
+	for i := range attachments {
+		list = append(list, *toAttachmentVO(&attachments[i]))
+	}
+	return list, nil
+}
+
+// Get 获取附件详情，包含短期有效的预签名下载地址
+func (s *AttachmentService) Get(ctx context.Context, user models.JwtUser, promptID, attachmentID uint) (*vo.AttachmentVO, error) {
+	attachment, err := findReadableAttachment(user, promptID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := ofs.GetStorage().Presign(ctx, attachment.Bucket, attachment.ObjectKey, ofs.PresignOptions{
+		Method:  http.MethodGet,
+		Expires: time.Duration(config.Get().Attachment.DownloadExpires) * time.Second,
+	})
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("生成下载地址失败")
+	}
+	attachmentVO := toAttachmentVO(attachment)
+	attachmentVO.DownloadURL = url
+	return attachmentVO, nil
+}
+
+// Upload 通过服务端上传附件，按文件内容识别类型
+func (s *AttachmentService) Upload(ctx context.Context, user models.JwtUser, promptID uint, file *multipart.FileHeader) (*vo.AttachmentVO, error) {
+	prompt, err := findWritablePrompt(user, promptID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAttachmentSize(file.Size); err != nil {
+		return nil, err
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return nil, res.ErrInvalidParam.Msg("读取文件失败")
+	}
+	defer f.Close()
+
+	head, err := readAttachmentHead(f)
+	if err != nil {
+		return nil, res.ErrInvalidParam.Msg("读取文件失败")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, res.ErrInternalServer.Msg("读取文件失败")
+	}
+	contentType, err := detectAttachmentType(head, file.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := newAttachment(user, prompt, file.Filename, contentType, file.Size)
+	storage := ofs.GetStorage()
+	if err := storage.Put(ctx, attachment.Bucket, attachment.ObjectKey, f, file.Size, contentType); err != nil {
+		utils.Log.Errorf("附件上传失败，提示词: %d，错误: %v", prompt.ID, err)
+		return nil, res.ErrInternalServer.Msg("上传附件失败")
+	}
+	if err := database.GetDB().Create(attachment).Error; err != nil {
+		deleteAttachmentObject(attachment)
+		return nil, res.ErrInternalServer.Msg("保存附件失败")
+	}
+	return toAttachmentVO(attachment), nil
+}
+
+// Presign 创建待上传的附件并签发预签名上传 URL，客户端上传完成后需调用 Complete
+func (s *AttachmentService) Presign(ctx context.Context, user models.JwtUser, promptID uint, dto *dto.PresignAttachmentDto) (*vo.PresignAttachmentVO, error) {
+	prompt, err := findWritablePrompt(user, promptID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAttachmentSize(dto.Size); err != nil {
+		return nil, err
+	}
+	contentType, _, err := mime.ParseMediaType(dto.ContentType)
+	if err != nil || !slices.Contains(config.Get().Attachment.AllowedTypes, contentType) {
+		return nil, res.ErrAttachmentType
+	}
+
+	cleanupExpiredAttachments()
+
+	cfg := config.Get().Attachment
+	expiresIn := time.Duration(cfg.UploadExpires) * time.Second
+	expiresAt := time.Now().Add(expiresIn)
+	attachment := newAttachment(user, prompt, dto.FileName, contentType, dto.Size)
+	attachment.Status = models.AttachmentStatusPending
+	attachment.ExpiresAt = &expiresAt
+
+	url, err := ofs.GetStorage().Presign(ctx, attachment.Bucket, attachment.ObjectKey, ofs.PresignOptions{
+		Method:        http.MethodPut,
+		Expires:       expiresIn,
+		ContentType:   contentType,
+		ContentLength: dto.Size,
+	})
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("生成上传地址失败")
+	}
+	if err := database.GetDB().Create(attachment).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("保存附件失败")
+	}
+
+	return &vo.PresignAttachmentVO{
+		Attachment: *toAttachmentVO(attachment),
+		UploadURL:  url,
+		Method:     http.MethodPut,
+		Headers:    map[string]string{"Content-Type": contentType},
+		ExpiresIn:  cfg.UploadExpires,
+	}, nil
+}
+
+// Complete 确认预签名上传完成，校验实际大小与文件内容类型，不通过时删除已上传的文件
+func (s *AttachmentService) Complete(ctx context.Context, user models.JwtUser, promptID, attachmentID uint) (*vo.AttachmentVO, error) {
+	if _, err := findWritablePrompt(user, promptID); err != nil {
+		return nil, err
+	}
+	attachment, err := findAttachment(promptID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.Status == models.AttachmentStatusReady {
+		return toAttachmentVO(attachment), nil
+	}
+	if attachment.ExpiresAt != nil && attachment.ExpiresAt.Before(time.Now()) {
+		return nil, res.ErrAttachmentNotFound.Msg("上传已过期，请重新上传")
+	}
+
+	storage := ofs.GetStorage()
+	info, err := storage.Stat(ctx, attachment.Bucket, attachment.ObjectKey)
+	if err != nil {
+		if errors.Is(err, ofs.ErrNotFound) {
+			return nil, res.ErrInvalidParam.Msg("文件尚未上传")
+		}
+		return nil, res.ErrInternalServer.Msg("查询附件失败")
+	}
+
+	contentType, reject := verifyUploadedAttachment(ctx, attachment, info)
+	if reject != nil {
+		deleteAttachmentObject(attachment)
+		database.GetDB().Delete(attachment)
+		return nil, reject
+	}
+
+	result := database.GetDB().Model(attachment).
+		Where("status = ?", models.AttachmentStatusPending).
+		Updates(map[string]any{
+			"status":       models.AttachmentStatusReady,
+			"content_type": contentType,
+			"size":         info.Size,
+			"expires_at":   nil,
+		})
+	if result.Error != nil {
+		return nil, res.ErrInternalServer.Msg("保存附件失败")
+	}
+	attachment.Status = models.AttachmentStatusReady
+	attachment.ContentType = contentType
+	attachment.Size = info.Size
+	attachment.ExpiresAt = nil
+	return toAttachmentVO(attachment), nil
+}
+
+// Download 读取附件内容，调用方负责关闭返回的 ReadCloser
+func (s *AttachmentService) Download(ctx context.Context, user models.JwtUser, promptID, attachmentID uint) (io.ReadCloser, *models.Attachment, error) {
+	attachment, err := findReadableAttachment(user, promptID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, _, err := ofs.GetStorage().Get(ctx, attachment.Bucket, attachment.ObjectKey)
+	if err != nil {
+		if errors.Is(err, ofs.ErrNotFound) {
+			return nil, nil, res.ErrAttachmentNotFound
+		}
+		return nil, nil, res.ErrInternalServer.Msg("读取附件失败")
+	}
+	return body, attachment, nil
+}
+
+// Delete 删除附件及其文件
+func (s *AttachmentService) Delete(user models.JwtUser, promptID, attachmentID uint) error {
+	if _, err := findWritablePrompt(user, promptID); err != nil {
+		return err
+	}
+	attachment, err := findAttachment(promptID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := database.GetDB().Delete(attachment).Error; err != nil {
+		return res.ErrInternalServer.Msg("删除附件失败")
+	}
+	deleteAttachmentObject(attachment)
+	return nil
+}
+
+// findWritablePrompt 查询当前用户可以修改的提示词
+func findWritablePrompt(user models.JwtUser, promptID uint) (*models.Prompt, error) {
+	prompt, err := findPrompt(promptID)
+	if err != nil {
+		return nil, err
+	}
+	if !canReadPrompt(user, prompt) {
+		return nil, res.ErrPromptNotFound
+	}
+	if !canWritePrompt(user, prompt) {
+		return nil, res.ErrForbidden.Msg("无权修改该提示词")
+	}
+	return prompt, nil
+}
+
+// findReadableAttachment 查询当前用户可以查看的已上传附件
+func findReadableAttachment(user models.JwtUser, promptID, attachmentID uint) (*models.Attachment, error) {
+	prompt, err := findPrompt(promptID)
+	if err != nil {
+		return nil, err
+	}
+	if !canReadPrompt(user, prompt) {
+		return nil, res.ErrPromptNotFound
+	}
+	attachment, err := findAttachment(promptID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.Status != models.AttachmentStatusReady {
+		return nil, res.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// findAttachment 根据提示词ID与附件ID查询附件
+func findAttachment(promptID, attachmentID uint) (*models.Attachment, error) {
+	var attachment models.Attachment
+	if err := database.GetDB().
+		Where("id = ? AND prompt_id = ?", attachmentID, promptID).
+		First(&attachment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, res.ErrAttachmentNotFound
+		}
+		return nil, res.ErrInternalServer.Msg("查询附件失败")
+	}
+	return &attachment, nil
+}
+
+// newAttachment 创建附件记录，对象键按提示词分目录并使用随机文件名，扩展名由内容类型决定
+func newAttachment(user models.JwtUser, prompt *models.Prompt, fileName, contentType string, size int64) *models.Attachment {
+	fileName = sanitizeFileName(fileName)
+	key := fmt.Sprintf("prompts/%d/%s", prompt.ID, uuid.NewString()) + attachmentTypeExts[contentType]
+	return &models.Attachment{
+		PromptID:    prompt.ID,
+		UploaderID:  user.UserID,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        size,
+		Bucket:      ofs.Bucket.Attachments,
+		ObjectKey:   key,
+		Status:      models.AttachmentStatusReady,
+	}
+}
+
+// verifyUploadedAttachment 校验预签名上传的文件大小与内容类型，返回识别出的类型
+func verifyUploadedAttachment(ctx context.Context, attachment *models.Attachment, info *ofs.ObjectInfo) (string, error) {
+	if err := checkAttachmentSize(info.Size); err != nil {
+		return "", err
+	}
+	if info.Size != attachment.Size {
+		return "", res.ErrInvalidParam.Msg("文件大小与申请时不一致")
+	}
+
+	body, _, err := ofs.GetStorage().Get(ctx, attachment.Bucket, attachment.ObjectKey)
+	if err != nil {
+		return "", res.ErrInternalServer.Msg("读取附件失败")
+	}
+	defer body.Close()
+	head, err := readAttachmentHead(body)
+	if err != nil {
+		return "", res.ErrInternalServer.Msg("读取附件失败")
+	}
+	return detectAttachmentType(head, attachment.FileName)
+}
+
+// cleanupExpiredAttachments 清理超时未完成上传的附件记录与文件
+func cleanupExpiredAttachments() {
+	db := database.GetDB()
+	var expired []models.Attachment
+	if err := db.Where("status = ? AND expires_at < ?", models.AttachmentStatusPending, time.Now()).
+		Limit(100).
+		Find(&expired).Error; err != nil {
+		utils.Log.Errorf("查询过期附件失败: %v", err)
+		return
+	}
+	for i := range expired {
+		deleteAttachmentObject(&expired[i])
+		db.Delete(&expired[i])
+	}
+}
+
+// deleteAttachmentObject 删除附件文件，失败只记录日志
+func deleteAttachmentObject(attachment *models.Attachment) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := ofs.GetStorage().Delete(ctx, attachment.Bucket, attachment.ObjectKey); err != nil {
+		utils.Log.Errorf("删除附件文件失败，对象: %s，错误: %v", attachment.ObjectKey, err)
+	}
+}
+
+// checkAttachmentSize 校验附件大小
+func checkAttachmentSize(size int64) error {
+	if size <= 0 {
+		return res.ErrInvalidParam.Msg("文件不能为空")
+	}
+	if maxSize := config.Get().Attachment.MaxSize; size > maxSize {
+		return res.ErrAttachmentTooLarge.Msgf("附件大小不能超过 %d KB", maxSize>>10)
+	}
+	return nil
+}
+
+// readAttachmentHead 读取文件开头用于识别类型
+func readAttachmentHead(r io.Reader) ([]byte, error) {
+	head := make([]byte, attachmentSniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// detectAttachmentType 按文件内容识别 MIME 类型，不依赖客户端声明的类型
+// 纯文本文件无法从内容区分格式，按扩展名细分为 markdown、csv、json
+func detectAttachmentType(head []byte, fileName string) (string, error) {
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if contentType == "text/plain" {
+		if textType, ok := attachmentTextTypes[strings.ToLower(path.Ext(fileName))]; ok {
+			contentType = textType
+		}
+	}
+	if !slices.Contains(config.Get().Attachment.AllowedTypes, contentType) {
+		return "", res.ErrAttachmentType.Msgf("不支持的附件类型 %s", contentType)
+	}
+	return contentType, nil
+}
+
+// sanitizeFileName 去掉客户端路径与控制字符，并限制文件名长度
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == utf8.RuneError {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	for len(name) > 255 {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if name == "" || name == "." || name == "/" {
+		return "file"
+	}
+	return name
+}
+
+// toAttachmentVO 模型转换为VO
+func toAttachmentVO(attachment *models.Attachment) *vo.AttachmentVO {
+	var attachmentVO vo.AttachmentVO
+	converter.SafeConvert(&attachmentVO, attachment)
+	return &attachmentVO
+}
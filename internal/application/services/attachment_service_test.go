@@ -0,0 +1,34 @@
+package services
+
+import (
+	"path"
+	"proomet/internal/domain/models"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestNewAttachmentKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileName    string
+		contentType string
+		wantExt     string
+	}{
+		{"扩展名由内容类型决定", "x.html", "text/plain", ".txt"},
+		{"扩展名统一为小写", "photo.PNG", "image/png", ".png"},
+		{"文件名没有扩展名", "report", "application/pdf", ".pdf"},
+		{"未知类型不带扩展名", "script.svg", "image/svg+xml", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attachment := newAttachment(models.JwtUser{UserID: 1}, &models.Prompt{Model: gorm.Model{ID: 7}}, tt.fileName, tt.contentType, 1)
+			if ext := path.Ext(attachment.ObjectKey); ext != tt.wantExt {
+				t.Fatalf("对象键 %s 的扩展名为 %q，期望 %q", attachment.ObjectKey, ext, tt.wantExt)
+			}
+			if attachment.FileName != tt.fileName {
+				t.Fatalf("文件名应保持原样，实际为 %s", attachment.FileName)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"proomet/internal/domain/models"
+
+	"gorm.io/gorm"
+)
+
+// recordAudit 写入审计日志
+func recordAudit(db *gorm.DB, action string, userID *uint, target, ip, detail string) error {
+	return db.Create(&models.AuditLog{
+		UserID: userID,
+		Action: action,
+		Target: target,
+		IP:     ip,
+		Detail: detail,
+	}).Error
+}
@@ -1,43 +1,168 @@
 package services
 
 import (
+	"errors"
 	"proomet/internal/domain/models"
+	"proomet/internal/infra/auth"
 	"proomet/internal/infra/database"
 	"proomet/internal/interfaces/dto"
 	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils"
 	"proomet/pkg/utils/jwt"
 	"proomet/pkg/utils/res"
+	"strings"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AuthService struct{}
 
 // LoginWithPwd 登录
-func (s *AuthService) LoginWithPwd(dto *dto.LoginWithPwdDto) (*vo.AuthLoginVO, error) {
+// 账号不存在与密码错误返回相同的错误；同一账号或 IP 连续失败达到阈值后暂时锁定
+func (s *AuthService) LoginWithPwd(dto *dto.LoginWithPwdDto, ip string) (*vo.AuthLoginVO, error) {
 	db := database.GetDB()
 
 	pwd := dto.Password
 	if pwd == "" {
 		return nil, res.ErrInvalidParam.Msg("密码不能为空")
 	}
+
+	accountKey, ipKey := loginFailureKeys(dto.Account, ip)
+	remaining, err := loginLockRemaining(db, accountKey, ipKey)
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("登录失败")
+	}
+	if remaining > 0 {
+		return nil, errLoginLocked(remaining)
+	}
+
 	// 判断email/username 是否存在
 	var user models.User
-	if err := db.Where("email = ?", dto.Account).Or("username = ?", dto.Account).First(&user).Error; err != nil {
-		return nil, res.ErrNotFound.Msg("账号不存在")
+	err = db.Where("LOWER(email) = ?", strings.ToLower(dto.Account)).Or("username = ?", dto.Account).First(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, res.ErrInternalServer.Msg("登录失败")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(pwd)); err != nil {
-		return nil, res.ErrInvalidCredentials.Msg("密码错误")
+	var userID *uint
+	hash := dummyPasswordHash()
+	if err == nil {
+		userID, hash = &user.ID, []byte(user.PasswordHash)
 	}
-	token, err := jwt.GenerateToken(user.ID, user.Username, user.Role)
+	if err := bcrypt.CompareHashAndPassword(hash, []byte(pwd)); err != nil || userID == nil {
+		if err := recordLoginFailure(db, dto.Account, ip, userID); err != nil {
+			utils.Log.Errorf("记录登录失败次数失败: %v", err)
+		}
+		return nil, res.ErrInvalidCredentials.Msg("账号或密码错误")
+	}
+
+	if user.Status == models.UserStatusDisabled {
+		return nil, res.ErrAccountDisabled
+	}
+
+	tokens, err := completeLogin(db, &user)
 	if err != nil {
 		return nil, res.ErrInternalServer.Msg("生成Token失败")
 	}
+	// 启用两步验证的账号在第二步验证通过后才清除失败计数
+	if !tokens.TwoFactorRequired {
+		if err := clearLoginFailures(db, dto.Account); err != nil {
+			utils.Log.Errorf("清除登录失败次数失败: %v", err)
+		}
+	}
+	return tokens, nil
+}
 
-	return &vo.AuthLoginVO{
-		Token: token,
-	}, nil
+// LoginTwoFactor 使用登录挑战与验证码（或恢复码）完成两步验证登录
+// 挑战令牌只能成功使用一次，连续失败达到上限后失效；验证失败与密码错误共用账号与 IP 的锁定计数
+func (s *AuthService) LoginTwoFactor(dto *dto.LoginTwoFactorDto, ip string) (*vo.AuthLoginVO, error) {
+	db := database.GetDB()
+
+	var (
+		tokens *vo.AuthLoginVO
+		user   models.User
+		failed bool
+		reject error
+	)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var challenge models.LoginChallenge
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", hashRefreshToken(dto.ChallengeToken)).
+			First(&challenge).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				reject = res.ErrTwoFactorChallengeInvalid
+				return nil
+			}
+			return err
+		}
+		if time.Now().After(challenge.ExpiresAt) {
+			reject = res.ErrTwoFactorChallengeInvalid
+			return tx.Delete(&challenge).Error
+		}
+		if err := tx.First(&user, challenge.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				reject = res.ErrTwoFactorChallengeInvalid
+				return nil
+			}
+			return err
+		}
+
+		// 账号被锁定后挑战随之失效，锁定结束后需要重新输入密码
+		remaining, err := userLockRemaining(tx, &user, ip)
+		if err != nil {
+			return err
+		}
+		if remaining > 0 {
+			reject = errLoginLocked(remaining)
+			return tx.Delete(&challenge).Error
+		}
+
+		ok, err := verifySecondFactor(tx, challenge.UserID, dto.Code)
+		if errors.Is(err, res.ErrTwoFactorNotEnabled) {
+			// 挑战签发后两步验证被关闭，要求重新登录
+			reject = res.ErrTwoFactorChallengeInvalid
+			return tx.Delete(&challenge).Error
+		}
+		if err != nil {
+			return err
+		}
+		if !ok {
+			failed = true
+			reject = res.ErrTwoFactorCodeInvalid
+			if challenge.Attempts+1 >= loginChallengeMaxAttempts {
+				reject = res.ErrTwoFactorChallengeInvalid.Msg("验证码错误次数过多，请重新登录")
+				return tx.Delete(&challenge).Error
+			}
+			return tx.Model(&challenge).Update("attempts", gorm.Expr("attempts + 1")).Error
+		}
+
+		if err := tx.Delete(&challenge).Error; err != nil {
+			return err
+		}
+		if user.Status == models.UserStatusDisabled {
+			reject = res.ErrAccountDisabled
+			return nil
+		}
+		tokens, err = issueTokens(tx, &user, "")
+		return err
+	})
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("两步验证登录失败")
+	}
+	if failed {
+		if err := recordSecondFactorFailure(db, &user, ip); err != nil {
+			utils.Log.Errorf("记录登录失败次数失败: %v", err)
+		}
+	}
+	if reject != nil {
+		return nil, reject
+	}
+	if err := clearLoginFailures(db, userAccounts(&user)...); err != nil {
+		utils.Log.Errorf("清除登录失败次数失败: %v", err)
+	}
+	return tokens, nil
 }
 
 // Register 用户注册
@@ -49,6 +174,11 @@ func (s *AuthService) Register(dto *dto.RegisterDto) (*vo.AuthRegisterVO, error)
 	if err := db.Where("username = ?", dto.Username).First(&existingUser).Error; err == nil {
 		return nil, res.ErrUsernameTaken
 	}
+	// 检查邮箱是否已被使用
+	email := strings.ToLower(dto.Email)
+	if err := db.Where("LOWER(email) = ?", email).First(&existingUser).Error; err == nil {
+		return nil, res.ErrEmailAlreadyUsed
+	}
 
 	// 密码加密
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(dto.Password), bcrypt.DefaultCost)
@@ -59,24 +189,107 @@ func (s *AuthService) Register(dto *dto.RegisterDto) (*vo.AuthRegisterVO, error)
 	// 创建用户
 	user := models.User{
 		Username:     dto.Username,
+		Email:        email,
 		PasswordHash: string(hashedPassword),
 		Role:         models.RoleMember,
-		Status:       1,
+		Status:       models.UserStatusActive,
 	}
 
 	if err := db.Create(&user).Error; err != nil {
 		return nil, res.ErrInternalServer.Msg("创建用户失败")
 	}
+	// 验证邮件发送失败不影响注册，用户可以稍后重新发送
+	if _, err := sendActionEmail(db, &user, models.ActionVerifyEmail); err != nil {
+		utils.Log.Errorf("发送验证邮件失败，用户: %d，错误: %v", user.ID, err)
+	}
 
 	// 生成Token
-	token, err := jwt.GenerateToken(user.ID, user.Username, user.Role)
+	tokens, err := issueTokens(db, &user, "")
 	if err != nil {
 		return nil, res.ErrInternalServer.Msg("生成Token失败")
 	}
 
 	return &vo.AuthRegisterVO{
-		UserID:   user.ID,
-		Username: user.Username,
-		Token:    token,
+		UserID:      user.ID,
+		Username:    user.Username,
+		AuthLoginVO: *tokens,
 	}, nil
 }
+
+// Refresh 使用刷新令牌换取新的令牌对，旧刷新令牌随即失效
+// 已轮换或已吊销的刷新令牌被再次使用时视为泄露，吊销整个令牌家族
+func (s *AuthService) Refresh(dto *dto.RefreshTokenDto) (*vo.AuthLoginVO, error) {
+	db := database.GetDB()
+
+	var (
+		tokens *vo.AuthLoginVO
+		reject error
+	)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var token models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", hashRefreshToken(dto.RefreshToken)).
+			First(&token).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				reject = res.ErrRefreshTokenInvalid
+				return nil
+			}
+			return err
+		}
+		if token.UsedAt != nil || token.RevokedAt != nil {
+			reject = res.ErrRefreshTokenInvalid.Msg("刷新令牌已失效，请重新登录")
+			return revokeTokenFamily(tx, token.FamilyID)
+		}
+		if time.Now().After(token.ExpiresAt) {
+			reject = res.ErrRefreshTokenInvalid
+			return nil
+		}
+
+		var user models.User
+		if err := tx.First(&user, token.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				reject = res.ErrRefreshTokenInvalid
+				return revokeTokenFamily(tx, token.FamilyID)
+			}
+			return err
+		}
+		if user.Status == models.UserStatusDisabled {
+			reject = res.ErrAccountDisabled
+			return revokeTokenFamily(tx, token.FamilyID)
+		}
+
+		if err := tx.Model(&token).Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		var err error
+		tokens, err = issueTokens(tx, &user, token.FamilyID)
+		return err
+	})
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("刷新令牌失败")
+	}
+	if reject != nil {
+		return nil, reject
+	}
+	return tokens, nil
+}
+
+// Logout 退出登录：吊销当前访问令牌及其所属的刷新令牌家族
+func (s *AuthService) Logout(claims *jwt.Claims) error {
+	if claims == nil || claims.UserID == 0 {
+		return res.ErrUnauthorized
+	}
+	db := database.GetDB()
+
+	if claims.SessionID != "" {
+		if err := revokeTokenFamily(db, claims.SessionID); err != nil {
+			return res.ErrInternalServer.Msg("退出登录失败")
+		}
+	}
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		if err := auth.RevokeToken(claims.ID, claims.ExpiresAt.Time); err != nil {
+			return res.ErrInternalServer.Msg("退出登录失败")
+		}
+	}
+	return nil
+}
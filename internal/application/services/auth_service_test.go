@@ -0,0 +1,154 @@
+package services
+
+import (
+	"proomet/internal/domain/models"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils/jwt"
+	"proomet/pkg/utils/res"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// createLoginUser 创建使用密码登录的用户
+func createLoginUser(t *testing.T, db *gorm.DB, username, password string) *models.User {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := models.User{Username: username, PasswordHash: string(hash), Role: models.RoleMember, Status: models.UserStatusActive}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return &user
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	tests := []struct {
+		name string
+		// run 在首次登录得到的令牌对上执行操作，返回最后一次刷新的结果
+		run     func(t *testing.T, env *refreshEnv) error
+		want    *res.BusinessError
+		revoked bool // 令牌家族是否已全部吊销
+	}{
+		{
+			name: "连续轮换",
+			run: func(t *testing.T, env *refreshEnv) error {
+				next := env.mustRefresh(t, env.login.RefreshToken)
+				_, err := env.refresh(next.RefreshToken)
+				return err
+			},
+		},
+		{
+			name: "重放已轮换的令牌吊销整个家族",
+			run: func(t *testing.T, env *refreshEnv) error {
+				next := env.mustRefresh(t, env.login.RefreshToken)
+				if _, err := env.refresh(env.login.RefreshToken); err == nil {
+					t.Fatal("重放旧令牌应当失败")
+				}
+				// 轮换得到的新令牌也随家族一起失效
+				_, err := env.refresh(next.RefreshToken)
+				return err
+			},
+			want:    res.ErrRefreshTokenInvalid,
+			revoked: true,
+		},
+		{
+			name: "令牌已过期",
+			run: func(t *testing.T, env *refreshEnv) error {
+				env.db.Model(&models.RefreshToken{}).Where("1 = 1").Update("expires_at", time.Now().Add(-time.Minute))
+				_, err := env.refresh(env.login.RefreshToken)
+				return err
+			},
+			want: res.ErrRefreshTokenInvalid,
+		},
+		{
+			name: "未知令牌",
+			run: func(t *testing.T, env *refreshEnv) error {
+				_, err := env.refresh("unknown")
+				return err
+			},
+			want: res.ErrRefreshTokenInvalid,
+		},
+		{
+			name: "账号被禁用",
+			run: func(t *testing.T, env *refreshEnv) error {
+				env.db.Model(env.user).Update("status", models.UserStatusDisabled)
+				_, err := env.refresh(env.login.RefreshToken)
+				return err
+			},
+			want:    res.ErrAccountDisabled,
+			revoked: true,
+		},
+		{
+			name: "退出登录后失效",
+			run: func(t *testing.T, env *refreshEnv) error {
+				claims, err := jwt.ParseToken(env.login.Token)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := (&AuthService{}).Logout(claims); err != nil {
+					t.Fatal(err)
+				}
+				_, err = env.refresh(env.login.RefreshToken)
+				return err
+			},
+			want:    res.ErrRefreshTokenInvalid,
+			revoked: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			user := createLoginUser(t, db, "alice", "correct-password")
+			login, err := (&AuthService{}).LoginWithPwd(&dto.LoginWithPwdDto{Account: "alice", Password: "correct-password"}, "10.0.0.1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			env := &refreshEnv{db: db, user: user, login: login}
+
+			err = tt.run(t, env)
+			if tt.want == nil && err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != nil {
+				assertBusinessError(t, err, tt.want)
+			}
+
+			var active int64
+			db.Model(&models.RefreshToken{}).Where("revoked_at IS NULL").Count(&active)
+			if tt.revoked && active != 0 {
+				t.Fatalf("令牌家族应全部吊销，仍有 %d 个有效令牌", active)
+			}
+		})
+	}
+}
+
+// refreshEnv 刷新令牌测试环境
+type refreshEnv struct {
+	db    *gorm.DB
+	user  *models.User
+	login *vo.AuthLoginVO
+}
+
+// refresh 使用刷新令牌换取新的令牌对
+func (e *refreshEnv) refresh(token string) (*vo.AuthLoginVO, error) {
+	return (&AuthService{}).Refresh(&dto.RefreshTokenDto{RefreshToken: token})
+}
+
+// mustRefresh 刷新必须成功且返回新的刷新令牌
+func (e *refreshEnv) mustRefresh(t *testing.T, token string) *vo.AuthLoginVO {
+	t.Helper()
+	tokens, err := e.refresh(token)
+	if err != nil {
+		t.Fatalf("刷新失败: %v", err)
+	}
+	if tokens.RefreshToken == token {
+		t.Fatal("刷新令牌未轮换")
+	}
+	return tokens
+}
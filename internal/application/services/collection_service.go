@@ -0,0 +1,297 @@
+package services
+
+import (
+	"errors"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils/converter"
+	"proomet/pkg/utils/res"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+type CollectionService struct{}
+
+// Create 创建集合
+func (s *CollectionService) Create(user models.JwtUser, dto *dto.CreateCollectionDto) (*vo.CollectionVO, error) {
+	if user.UserID == 0 {
+		return nil, res.ErrUnauthorized
+	}
+	db := database.GetDB()
+
+	if dto.ParentID != nil {
+		parent, err := findCollection(*dto.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if !canManageCollection(user, parent) {
+			return nil, res.ErrForbidden.Msg("无权操作该集合")
+		}
+	}
+
+	collection := models.Collection{
+		Name:        dto.Name,
+		Description: dto.Description,
+		ParentID:    dto.ParentID,
+		OwnerID:     user.UserID,
+	}
+	if err := db.Create(&collection).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("创建集合失败")
+	}
+	return toCollectionVO(&collection), nil
+}
+
+// List 查询当前用户的全部集合（平铺，通过 parent_id 还原层级）
+func (s *CollectionService) List(user models.JwtUser) ([]vo.CollectionVO, error) {
+	db := database.GetDB()
+
+	var collections []models.Collection
+	if err := db.Where("owner_id = ?", user.UserID).Order("name").Find(&collections).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询集合失败")
+	}
+
+	list := make([]vo.CollectionVO, 0, len(collections))
+	for i := range collections {
+		list = append(list, *toCollectionVO(&collections[i]))
+	}
+	return list, nil
+}
+
+// Get 获取集合详情
+func (s *CollectionService) Get(user models.JwtUser, id uint) (*vo.CollectionVO, error) {
+	collection, err := findCollection(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageCollection(user, collection) {
+		return nil, res.ErrCollectionNotFound
+	}
+	return toCollectionVO(collection), nil
+}
+
+// Update 更新集合名称与描述
+func (s *CollectionService) Update(user models.JwtUser, id uint, dto *dto.UpdateCollectionDto) (*vo.CollectionVO, error) {
+	db := database.GetDB()
+
+	collection, err := findCollection(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageCollection(user, collection) {
+		return nil, res.ErrForbidden.Msg("无权操作该集合")
+	}
+
+	if dto.Name != nil {
+		collection.Name = *dto.Name
+	}
+	if dto.Description != nil {
+		collection.Description = *dto.Description
+	}
+	if err := db.Save(collection).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("更新集合失败")
+	}
+	return toCollectionVO(collection), nil
+}
+
+// Move 移动集合到新的父级，禁止移动到自身或其子孙集合下
+func (s *CollectionService) Move(user models.JwtUser, id uint, dto *dto.MoveCollectionDto) (*vo.CollectionVO, error) {
+	db := database.GetDB()
+
+	collection, err := findCollection(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageCollection(user, collection) {
+		return nil, res.ErrForbidden.Msg("无权操作该集合")
+	}
+
+	if dto.ParentID != nil {
+		parent, err := findCollection(*dto.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if !canManageCollection(user, parent) {
+			return nil, res.ErrForbidden.Msg("无权操作目标集合")
+		}
+		subtree, err := collectionSubtreeIDs(db, collection.ID)
+		if err != nil {
+			return nil, res.ErrInternalServer.Msg("查询集合失败")
+		}
+		if slices.Contains(subtree, parent.ID) {
+			return nil, res.ErrInvalidParam.Msg("不能将集合移动到自身或其子集合下")
+		}
+	}
+
+	collection.ParentID = dto.ParentID
+	if err := db.Model(collection).Update("parent_id", dto.ParentID).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("移动集合失败")
+	}
+	return toCollectionVO(collection), nil
+}
+
+// Delete 删除集合，其子集合与提示词上移到父级集合
+func (s *CollectionService) Delete(user models.JwtUser, id uint) error {
+	db := database.GetDB()
+
+	collection, err := findCollection(id)
+	if err != nil {
+		return err
+	}
+	if !canManageCollection(user, collection) {
+		return res.ErrForbidden.Msg("无权操作该集合")
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Collection{}).
+			Where("parent_id = ?", collection.ID).
+			Update("parent_id", collection.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Prompt{}).
+			Where("collection_id = ?", collection.ID).
+			Update("collection_id", collection.ParentID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(collection).Error
+	})
+	if err != nil {
+		return res.ErrInternalServer.Msg("删除集合失败")
+	}
+	return nil
+}
+
+// Tree 获取集合子树，包含每个节点的提示词数量，可选附带提示词摘要
+func (s *CollectionService) Tree(user models.JwtUser, id uint, dto *dto.CollectionTreeDto) (*vo.CollectionTreeVO, error) {
+	db := database.GetDB()
+
+	root, err := findCollection(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canManageCollection(user, root) {
+		return nil, res.ErrCollectionNotFound
+	}
+
+	ids, err := collectionSubtreeIDs(db, root.ID)
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("查询集合失败")
+	}
+
+	var collections []models.Collection
+	if err := db.Where("id IN ?", ids).Order("name").Find(&collections).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询集合失败")
+	}
+
+	var counts []struct {
+		CollectionID uint
+		Count        int64
+	}
+	// 只统计当前仍可查看的提示词
+	if err := db.Model(&models.Prompt{}).
+		Select("collection_id, COUNT(*) AS count").
+		Where("collection_id IN ?", ids).
+		Scopes(readablePrompts(user)).
+		Group("collection_id").
+		Scan(&counts).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询集合失败")
+	}
+
+	nodes := make(map[uint]*vo.CollectionTreeVO, len(collections))
+	for i := range collections {
+		nodes[collections[i].ID] = &vo.CollectionTreeVO{
+			ID:          collections[i].ID,
+			Name:        collections[i].Name,
+			Description: collections[i].Description,
+			ParentID:    collections[i].ParentID,
+			Children:    []*vo.CollectionTreeVO{},
+		}
+	}
+	for _, count := range counts {
+		if node, ok := nodes[count.CollectionID]; ok {
+			node.PromptCount = count.Count
+		}
+	}
+
+	if dto.WithPrompts {
+		var prompts []models.Prompt
+		if err := db.Select("id", "title", "type", "visibility", "updated_at", "collection_id").
+			Where("collection_id IN ?", ids).
+			Scopes(readablePrompts(user)).
+			Order("title").
+			Find(&prompts).Error; err != nil {
+			return nil, res.ErrInternalServer.Msg("查询提示词失败")
+		}
+		for _, prompt := range prompts {
+			if prompt.CollectionID == nil {
+				continue
+			}
+			node, ok := nodes[*prompt.CollectionID]
+			if !ok {
+				continue
+			}
+			node.Prompts = append(node.Prompts, vo.PromptSummaryVO{
+				ID:         prompt.ID,
+				Title:      prompt.Title,
+				Type:       prompt.Type,
+				Visibility: prompt.Visibility,
+				UpdatedAt:  prompt.UpdatedAt,
+			})
+		}
+	}
+
+	// collections 已按名称排序，按顺序挂载即可保证子节点有序
+	for _, collection := range collections {
+		if collection.ID == root.ID || collection.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*collection.ParentID]; ok {
+			parent.Children = append(parent.Children, nodes[collection.ID])
+		}
+	}
+	return nodes[root.ID], nil
+}
+
+// collectionSubtreeIDs 使用递归 CTE 查询集合自身及全部子孙集合的ID
+func collectionSubtreeIDs(db *gorm.DB, id uint) ([]uint, error) {
+	var ids []uint
+	err := db.Raw(`
+		WITH RECURSIVE subtree AS (
+			SELECT id FROM collections WHERE id = ? AND deleted_at IS NULL
+			UNION
+			SELECT c.id FROM collections c
+			JOIN subtree s ON c.parent_id = s.id
+			WHERE c.deleted_at IS NULL
+		)
+		SELECT id FROM subtree`, id).Scan(&ids).Error
+	return ids, err
+}
+
+// findCollection 根据ID查询集合
+func findCollection(id uint) (*models.Collection, error) {
+	var collection models.Collection
+	if err := database.GetDB().First(&collection, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, res.ErrCollectionNotFound
+		}
+		return nil, res.ErrInternalServer.Msg("查询集合失败")
+	}
+	return &collection, nil
+}
+
+// canManageCollection 是否可以管理集合
+func canManageCollection(user models.JwtUser, collection *models.Collection) bool {
+	if user.Role == models.RoleAdmin {
+		return true
+	}
+	return user.UserID != 0 && collection.OwnerID == user.UserID
+}
+
+// toCollectionVO 模型转换为VO
+func toCollectionVO(collection *models.Collection) *vo.CollectionVO {
+	var collectionVO vo.CollectionVO
+	converter.SafeConvert(&collectionVO, collection)
+	return &collectionVO
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"proomet/config"
+	"proomet/internal/interfaces/vo"
+)
+
+// ConfigService 运行时配置查询
+type ConfigService struct{}
+
+// Current 返回当前生效的配置快照，密码、密钥等敏感项替换为 ******
+func (s *ConfigService) Current() *vo.ConfigVO {
+	snapshot := config.Current()
+	return &vo.ConfigVO{
+		Version:  snapshot.Version,
+		LoadedAt: snapshot.LoadedAt,
+		Config:   config.Redacted(snapshot.Config),
+	}
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"proomet/internal/infra/auth"
+	"proomet/internal/infra/database"
+	"proomet/internal/infra/ofs"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils"
+	"sync"
+	"time"
+)
+
+// healthCheckTimeout 单个依赖检查的超时时间
+const healthCheckTimeout = 2 * time.Second
+
+// HealthService 存活与就绪检查
+type HealthService struct{}
+
+// Liveness 进程能够处理请求即为存活，不检查外部依赖，避免依赖故障时进程被反复重启
+func (s *HealthService) Liveness() *vo.HealthVO {
+	return &vo.HealthVO{Status: vo.HealthStatusOK}
+}
+
+// Readiness 并发检查数据库、对象存储与权限策略，任一组件不可用时返回 degraded
+func (s *HealthService) Readiness(ctx context.Context) *vo.HealthVO {
+	checks := map[string]func(ctx context.Context) error{
+		"database": database.Ping,
+		"storage":  ofs.Ping,
+		"casbin": func(context.Context) error {
+			return auth.CheckPolicy()
+		},
+	}
+
+	result := &vo.HealthVO{
+		Status:     vo.HealthStatusOK,
+		Components: make(map[string]vo.ComponentHealthVO, len(checks)),
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			component := runHealthCheck(ctx, name, check)
+			mu.Lock()
+			defer mu.Unlock()
+			result.Components[name] = component
+			if component.Status != vo.HealthStatusOK {
+				result.Status = vo.HealthStatusDegraded
+			}
+		}()
+	}
+	wg.Wait()
+	return result
+}
+
+// runHealthCheck 执行单个检查并记录耗时，超时视为不可用；/readyz 无需鉴权，错误详情只写日志不返回
+func runHealthCheck(ctx context.Context, name string, check func(ctx context.Context) error) vo.ComponentHealthVO {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	component := vo.ComponentHealthVO{
+		Status:    vo.HealthStatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		component.Status = vo.HealthStatusDown
+		utils.Log.Errorf("就绪检查失败，组件: %s，错误: %v", name, err)
+	}
+	return component
+}
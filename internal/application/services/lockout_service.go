@@ -0,0 +1,159 @@
+package services
+
+import (
+	"fmt"
+	"proomet/config"
+	"proomet/internal/domain/models"
+	"proomet/pkg/utils/res"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dummyPasswordHash 账号不存在时用于比对的密码摘要，使两种失败情况的耗时一致
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("proomet-dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+// loginFailureKeys 登录失败计数键，账号统一转为小写，不存在的账号同样计数
+func loginFailureKeys(account, ip string) (string, string) {
+	return "account:" + strings.ToLower(strings.TrimSpace(account)), "ip:" + ip
+}
+
+// loginLockRemaining 返回账号或 IP 剩余的锁定时长，未锁定时返回 0
+func loginLockRemaining(db *gorm.DB, keys ...string) (time.Duration, error) {
+	var failures []models.LoginFailure
+	if err := db.Where("key IN ? AND locked_until > ?", keys, time.Now()).Find(&failures).Error; err != nil {
+		return 0, err
+	}
+	var remaining time.Duration
+	for _, failure := range failures {
+		remaining = max(remaining, time.Until(*failure.LockedUntil))
+	}
+	return remaining, nil
+}
+
+// recordLoginFailure 记录一次登录失败，达到阈值时锁定并写入审计日志
+func recordLoginFailure(db *gorm.DB, account, ip string, userID *uint) error {
+	cfg := config.Get().Lockout
+	accountKey, ipKey := loginFailureKeys(account, ip)
+	if err := countLoginFailure(db, accountKey, cfg.AccountMaxFailures, ip, userID); err != nil {
+		return err
+	}
+	return countLoginFailure(db, ipKey, cfg.IPMaxFailures, ip, nil)
+}
+
+// recordSecondFactorFailure 记录一次两步验证失败，与密码错误共用失败计数
+// 同时计入用户名与邮箱，无论之后使用哪个账号登录都会被锁定
+func recordSecondFactorFailure(db *gorm.DB, user *models.User, ip string) error {
+	cfg := config.Get().Lockout
+	for _, account := range userAccounts(user) {
+		accountKey, _ := loginFailureKeys(account, ip)
+		if err := countLoginFailure(db, accountKey, cfg.AccountMaxFailures, ip, &user.ID); err != nil {
+			return err
+		}
+	}
+	_, ipKey := loginFailureKeys("", ip)
+	return countLoginFailure(db, ipKey, cfg.IPMaxFailures, ip, nil)
+}
+
+// userLockRemaining 返回用户任一账号或 IP 剩余的锁定时长
+func userLockRemaining(db *gorm.DB, user *models.User, ip string) (time.Duration, error) {
+	_, ipKey := loginFailureKeys("", ip)
+	keys := []string{ipKey}
+	for _, account := range userAccounts(user) {
+		accountKey, _ := loginFailureKeys(account, ip)
+		keys = append(keys, accountKey)
+	}
+	return loginLockRemaining(db, keys...)
+}
+
+// userAccounts 用户可用于登录的账号
+func userAccounts(user *models.User) []string {
+	accounts := []string{user.Username}
+	if user.Email != "" {
+		accounts = append(accounts, user.Email)
+	}
+	return accounts
+}
+
+// clearLoginFailures 登录成功后清除账号的失败计数；IP 计数不清除，避免攻击者用自己的账号重置计数
+func clearLoginFailures(db *gorm.DB, accounts ...string) error {
+	keys := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		accountKey, _ := loginFailureKeys(account, "")
+		keys = append(keys, accountKey)
+	}
+	return db.Where("key IN ?", keys).Delete(&models.LoginFailure{}).Error
+}
+
+// countLoginFailure 失败计数加一，threshold 小于等于 0 时不限制
+func countLoginFailure(db *gorm.DB, key string, threshold int, ip string, userID *uint) error {
+	if threshold <= 0 {
+		return nil
+	}
+	cfg := config.Get().Lockout
+	window := time.Duration(cfg.Window) * time.Second
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.LoginFailure{Key: key, WindowStart: now}).Error; err != nil {
+			return err
+		}
+		var failure models.LoginFailure
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&failure).Error; err != nil {
+			return err
+		}
+
+		// 上次锁定结束后一个窗口内没有再被锁定，锁定时长重新从基础时长开始
+		if failure.LockedUntil != nil && now.After(failure.LockedUntil.Add(window)) {
+			failure.Lockouts = 0
+			failure.LockedUntil = nil
+		}
+		if now.Sub(failure.WindowStart) > window {
+			failure.Failures = 0
+			failure.WindowStart = now
+		}
+		failure.Failures++
+
+		locked := failure.Failures >= threshold
+		if locked {
+			failure.Lockouts++
+			lockedUntil := now.Add(lockoutDuration(cfg, failure.Lockouts))
+			failure.LockedUntil = &lockedUntil
+			failure.Failures = 0
+			failure.WindowStart = now
+		}
+		if err := tx.Save(&failure).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+		detail := fmt.Sprintf("连续失败 %d 次，第 %d 次锁定，锁定至 %s", threshold, failure.Lockouts, failure.LockedUntil.Format(time.RFC3339))
+		return recordAudit(tx, models.AuditLoginLockout, userID, key, ip, detail)
+	})
+}
+
+// lockoutDuration 第 n 次锁定的时长，从基础时长开始每次翻倍，不超过最长时长
+func lockoutDuration(cfg config.LockoutConfig, n int) time.Duration {
+	base := time.Duration(max(cfg.BaseDuration, 1)) * time.Second
+	limit := time.Duration(max(cfg.MaxDuration, cfg.BaseDuration, 1)) * time.Second
+	duration := base
+	for i := 1; i < n && duration < limit; i++ {
+		duration *= 2
+	}
+	return min(duration, limit)
+}
+
+// errLoginLocked 锁定提示，剩余时间向上取整到秒
+func errLoginLocked(remaining time.Duration) error {
+	seconds := int64((remaining + time.Second - 1) / time.Second)
+	return res.ErrLoginLocked.Msgf("登录失败次数过多，请在 %d 秒后重试", seconds)
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"proomet/config"
+	"proomet/internal/domain/models"
+	"proomet/internal/interfaces/dto"
+	"proomet/pkg/utils/res"
+	"testing"
+	"time"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	cfg := config.LockoutConfig{BaseDuration: 60, MaxDuration: 3600}
+	tests := []struct {
+		name string
+		cfg  config.LockoutConfig
+		n    int
+		want time.Duration
+	}{
+		{"首次锁定", cfg, 1, time.Minute},
+		{"第二次翻倍", cfg, 2, 2 * time.Minute},
+		{"第三次翻倍", cfg, 3, 4 * time.Minute},
+		{"不超过最长时长", cfg, 7, time.Hour},
+		{"多次锁定后保持最长时长", cfg, 100, time.Hour},
+		{"基础时长为 0 时按 1 秒计算", config.LockoutConfig{}, 3, time.Second},
+		{"最长时长小于基础时长", config.LockoutConfig{BaseDuration: 60, MaxDuration: 10}, 3, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lockoutDuration(tt.cfg, tt.n); got != tt.want {
+				t.Fatalf("lockoutDuration(%d) = %s，期望 %s", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+// loginStep 一次登录尝试，expire 为 true 时先让当前的锁定提前结束
+type loginStep struct {
+	account, password, ip string
+	expire                bool
+	want                  *res.BusinessError // nil 表示登录成功
+}
+
+func TestLoginLockout(t *testing.T) {
+	const ip = "10.0.0.1"
+	wrong := func(account string) loginStep {
+		return loginStep{account: account, password: "wrong", ip: ip, want: res.ErrInvalidCredentials}
+	}
+	locked := loginStep{account: "alice", password: "correct-password", ip: ip, want: res.ErrLoginLocked}
+	ok := loginStep{account: "alice", password: "correct-password", ip: ip}
+
+	tests := []struct {
+		name  string
+		steps []loginStep
+		// lockedFor 最后账号 alice 剩余的锁定时长，0 表示未锁定
+		lockedFor time.Duration
+	}{
+		{
+			name:      "连续失败达到阈值后锁定，正确密码也被拒绝",
+			steps:     []loginStep{wrong("alice"), wrong("alice"), wrong("alice"), locked},
+			lockedFor: time.Minute,
+		},
+		{
+			name: "锁定结束后再次锁定时长翻倍",
+			steps: []loginStep{
+				wrong("alice"), wrong("alice"), wrong("alice"),
+				{account: "alice", password: "wrong", ip: ip, expire: true, want: res.ErrInvalidCredentials},
+				wrong("alice"), wrong("alice"), locked,
+			},
+			lockedFor: 2 * time.Minute,
+		},
+		{
+			name:  "登录成功后清除账号计数",
+			steps: []loginStep{wrong("alice"), wrong("alice"), ok, wrong("alice"), wrong("alice"), ok},
+		},
+		{
+			name:  "账号名不区分大小写",
+			steps: []loginStep{wrong("alice"), wrong("ALICE"), wrong("Alice"), locked},
+			// 计数键统一为小写
+			lockedFor: time.Minute,
+		},
+		{
+			name:  "不存在的账号同样计数",
+			steps: []loginStep{wrong("ghost"), wrong("ghost"), wrong("ghost"), {account: "ghost", password: "any", ip: ip, want: res.ErrLoginLocked}, ok},
+		},
+		{
+			name: "同一 IP 失败过多时锁定该 IP 的全部登录",
+			steps: []loginStep{
+				wrong("bob"), wrong("carol"), wrong("dave"), wrong("erin"),
+				wrong("frank"), wrong("grace"), wrong("heidi"), wrong("ivan"), locked,
+				{account: "alice", password: "correct-password", ip: "10.0.0.2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			cfg := *config.Get()
+			cfg.Lockout = config.LockoutConfig{AccountMaxFailures: 3, IPMaxFailures: 8, Window: 900, BaseDuration: 60, MaxDuration: 3600}
+			config.Set(&cfg)
+			createLoginUser(t, db, "alice", "correct-password")
+
+			service := &AuthService{}
+			for i, step := range tt.steps {
+				if step.expire {
+					db.Model(&models.LoginFailure{}).Where("locked_until IS NOT NULL").Update("locked_until", time.Now().Add(-time.Second))
+				}
+				_, err := service.LoginWithPwd(&dto.LoginWithPwdDto{Account: step.account, Password: step.password}, step.ip)
+				if step.want == nil {
+					if err != nil {
+						t.Fatalf("第 %d 步期望登录成功，实际为 %v", i+1, err)
+					}
+					continue
+				}
+				if !isBusinessError(err, step.want) {
+					t.Fatalf("第 %d 步期望错误码 %d，实际为 %v", i+1, step.want.Code, err)
+				}
+			}
+
+			accountKey, _ := loginFailureKeys("alice", "")
+			remaining, err := loginLockRemaining(db, accountKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if remaining > tt.lockedFor || remaining < tt.lockedFor-5*time.Second {
+				t.Fatalf("账号剩余锁定时长为 %s，期望约 %s", remaining, tt.lockedFor)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"proomet/internal/infra/oidc"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils"
+	"proomet/pkg/utils/res"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// oidcStateTTL 授权请求有效期，用户需要在此时间内完成提供方登录
+const oidcStateTTL = 10 * time.Minute
+
+type OIDCService struct{}
+
+// Providers 已启用的提供方
+func (s *OIDCService) Providers() []string {
+	return oidc.ProviderNames()
+}
+
+// Authorize 生成 state、nonce 与 PKCE 参数并返回提供方授权地址
+func (s *OIDCService) Authorize(ctx context.Context, providerName string) (*vo.OIDCAuthorizeVO, error) {
+	provider, ok := oidc.GetProvider(providerName)
+	if !ok {
+		return nil, res.ErrNotFound.Msg("身份提供方不存在")
+	}
+
+	state, err := oidc.RandomString(24)
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("生成授权参数失败")
+	}
+	nonce, err := oidc.RandomString(24)
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("生成授权参数失败")
+	}
+	verifier, err := oidc.RandomString(48)
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("生成授权参数失败")
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallengeS256(verifier))
+	if err != nil {
+		utils.Log.Errorf("OIDC 提供方 %s 不可用: %v", providerName, err)
+		return nil, res.ErrOIDCLoginFailed.Msg("身份提供方暂不可用")
+	}
+
+	db := database.GetDB()
+	// 顺带清理过期的授权请求
+	if err := db.Where("expires_at < ?", time.Now()).Delete(&models.OIDCState{}).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("保存授权状态失败")
+	}
+	record := models.OIDCState{
+		State:        state,
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	}
+	if err := db.Create(&record).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("保存授权状态失败")
+	}
+
+	return &vo.OIDCAuthorizeVO{AuthorizationURL: authURL, State: state}, nil
+}
+
+// Callback 处理授权回调：消费 state、交换授权码并校验 ID Token，随后关联或创建用户并签发 proomet 令牌
+// 已启用两步验证的账号同样需要完成 /auth/sign/2fa
+func (s *OIDCService) Callback(ctx context.Context, providerName string, dto *dto.OIDCCallbackDto) (*vo.AuthLoginVO, error) {
+	provider, ok := oidc.GetProvider(providerName)
+	if !ok {
+		return nil, res.ErrNotFound.Msg("身份提供方不存在")
+	}
+	if dto.Error != "" {
+		return nil, res.ErrOIDCLoginFailed.Msgf("身份提供方拒绝授权: %s", utils.DefaultString(dto.ErrorDescription, dto.Error))
+	}
+
+	db := database.GetDB()
+	// 使用 DELETE ... RETURNING 一次性消费 state，防止重放
+	var state models.OIDCState
+	result := db.Clauses(clause.Returning{}).
+		Where("state = ? AND provider = ?", dto.State, providerName).
+		Delete(&state)
+	if result.Error != nil {
+		return nil, res.ErrInternalServer.Msg("查询授权状态失败")
+	}
+	if result.RowsAffected == 0 || time.Now().After(state.ExpiresAt) {
+		return nil, res.ErrOIDCLoginFailed.Msg("授权请求无效或已过期，请重新登录")
+	}
+
+	claims, err := provider.Exchange(ctx, dto.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		utils.Log.Warnf("OIDC 提供方 %s 登录失败: %v", providerName, err)
+		return nil, res.ErrOIDCLoginFailed
+	}
+
+	var tokens *vo.AuthLoginVO
+	err = db.Transaction(func(tx *gorm.DB) error {
+		user, err := resolveOIDCUser(tx, provider, claims)
+		if err != nil {
+			return err
+		}
+		if user.Status == models.UserStatusDisabled {
+			return res.ErrAccountDisabled
+		}
+		tokens, err = completeLogin(tx, user)
+		return err
+	})
+	if err != nil {
+		var businessErr *res.BusinessError
+		if errors.As(err, &businessErr) {
+			return nil, businessErr
+		}
+		return nil, res.ErrInternalServer.Msg("第三方登录失败")
+	}
+	return tokens, nil
+}
+
+// resolveOIDCUser 按提供方主体查找已关联的用户；未关联时按已验证的邮箱关联现有用户，或按配置自动创建用户
+// 只有提供方与本地账号双方都验证过邮箱才会自动关联；模拟提供方可以任意指定邮箱，从不关联已有账号
+func resolveOIDCUser(tx *gorm.DB, provider *oidc.Provider, claims *oidc.IDTokenClaims) (*models.User, error) {
+	var identity models.UserIdentity
+	err := tx.Where("provider = ? AND subject = ?", provider.Name, claims.Subject).First(&identity).Error
+	if err == nil {
+		var user models.User
+		if err := tx.First(&user, identity.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, res.ErrOIDCLoginFailed.Msg("关联的用户不存在")
+			}
+			return nil, err
+		}
+		if claims.Email != "" && identity.Email != claims.Email {
+			if err := tx.Model(&identity).Update("email", claims.Email).Error; err != nil {
+				return nil, err
+			}
+		}
+		return &user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	email := strings.ToLower(claims.Email)
+	var user models.User
+	switch {
+	case email != "" && claims.EmailVerified && !provider.Mock() &&
+		tx.Where("LOWER(email) = ? AND email_verified_at IS NOT NULL", email).First(&user).Error == nil:
+		// 双方邮箱均已验证且一致，直接关联
+	case provider.AutoProvision():
+		provisioned, err := provisionOIDCUser(tx, claims, email)
+		if err != nil {
+			return nil, err
+		}
+		user = *provisioned
+	default:
+		return nil, res.ErrOIDCLoginFailed.Msg("该身份尚未关联 proomet 账号")
+	}
+
+	identity = models.UserIdentity{
+		UserID:   user.ID,
+		Provider: provider.Name,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+	if err := tx.Create(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// provisionOIDCUser 为首次登录的外部身份创建用户，外部用户没有本地密码
+func provisionOIDCUser(tx *gorm.DB, claims *oidc.IDTokenClaims, email string) (*models.User, error) {
+	if email != "" {
+		var count int64
+		if err := tx.Model(&models.User{}).Where("LOWER(email) = ?", email).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		// 未能关联已有用户时（任一方邮箱未验证或使用模拟提供方）也不能创建同邮箱的新用户
+		if count > 0 {
+			return nil, res.ErrOIDCLoginFailed.Msg("该邮箱已被其他账号使用")
+		}
+	}
+
+	username, err := availableUsername(tx, utils.DefaultString(claims.PreferredUsername, strings.Split(email, "@")[0]))
+	if err != nil {
+		return nil, err
+	}
+	user := models.User{
+		Username: username,
+		Nickname: utils.DefaultString(claims.Name, username),
+		Email:    email,
+		Role:     models.RoleMember,
+		Status:   models.UserStatusActive,
+	}
+	if email != "" && claims.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := tx.Create(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// availableUsername 将外部用户名整理为合法的本地用户名，重名时追加随机后缀
+func availableUsername(tx *gorm.DB, candidate string) (string, error) {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(candidate) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			sb.WriteRune(r)
+		}
+	}
+	base := strings.Trim(sb.String(), "_-")
+	if len(base) < 3 {
+		base = "user" + base
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	username := base
+	for range 5 {
+		var count int64
+		if err := tx.Model(&models.User{}).Unscoped().Where("username = ?", username).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return username, nil
+		}
+		suffix, err := oidc.RandomString(3)
+		if err != nil {
+			return "", err
+		}
+		username = fmt.Sprintf("%s-%s", base, strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(suffix)))
+	}
+	return "", res.ErrOIDCLoginFailed.Msg("无法生成可用的用户名")
+}
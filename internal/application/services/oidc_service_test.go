@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"proomet/config"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/oidc"
+	"proomet/internal/interfaces/dto"
+	"proomet/pkg/utils/res"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// oidcTestEnv 测试用的两个提供方：进程内模拟提供方 mock，以及通过 HTTP 访问、按真实提供方处理的 idp
+type oidcTestEnv struct {
+	db       *gorm.DB
+	handlers map[string]http.Handler
+}
+
+func setupOIDC(t *testing.T) *oidcTestEnv {
+	t.Helper()
+	db := setupTestDB(t)
+
+	var idp *oidc.MockProvider
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idp.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	var err error
+	if idp, err = oidc.NewMockProvider(server.URL+"/idp", "proomet", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	oidc.InitOIDC(config.OIDCConfig{Providers: map[string]config.OIDCProviderConfig{
+		"mock": {
+			Issuer:        "http://localhost:7071/oidc/mock",
+			ClientID:      "proomet",
+			ClientSecret:  "secret",
+			RedirectURL:   "http://localhost:7071/auth/oidc/mock/callback",
+			AutoProvision: true,
+			Mock:          true,
+		},
+		"idp": {
+			Issuer:        server.URL + "/idp",
+			ClientID:      "proomet",
+			ClientSecret:  "secret",
+			RedirectURL:   "http://localhost:7071/auth/oidc/idp/callback",
+			AutoProvision: true,
+		},
+	}}, "test")
+	if len(oidc.MockProviders()) != 1 {
+		t.Fatal("模拟提供方未启用")
+	}
+	return &oidcTestEnv{db: db, handlers: map[string]http.Handler{"mock": oidc.MockProviders()[0], "idp": idp}}
+}
+
+// authorize 发起授权并以 email 的身份在提供方完成登录，返回回调参数
+func (e *oidcTestEnv) authorize(t *testing.T, provider, email string) *dto.OIDCCallbackDto {
+	t.Helper()
+	authorization, err := (&OIDCService{}).Authorize(context.Background(), provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	target := authorization.AuthorizationURL + "&login_hint=" + url.QueryEscape(email)
+	e.handlers[provider].ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	if recorder.Code != http.StatusFound || err != nil {
+		t.Fatalf("授权端点返回 %d: %s", recorder.Code, recorder.Body.String())
+	}
+	return &dto.OIDCCallbackDto{Code: location.Query().Get("code"), State: location.Query().Get("state")}
+}
+
+// createUser 创建本地用户，verified 表示邮箱是否已验证
+func (e *oidcTestEnv) createUser(t *testing.T, username, email string, verified bool) *models.User {
+	t.Helper()
+	user := models.User{Username: username, PasswordHash: "x", Email: email, Role: models.RoleMember, Status: models.UserStatusActive}
+	if verified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	if err := e.db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return &user
+}
+
+// identityUser 提供方身份关联的用户ID，未关联时返回 0
+func (e *oidcTestEnv) identityUser(t *testing.T, provider, email string) uint {
+	t.Helper()
+	var identity models.UserIdentity
+	err := e.db.Where("provider = ? AND email = ?", provider, email).First(&identity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return identity.UserID
+}
+
+func TestOIDCCallbackResolveUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		provider   string
+		local      *bool // 同邮箱的本地用户，nil 表示不存在；值表示其邮箱是否已验证
+		wantErr    bool
+		wantLinked bool // 是否关联到本地用户
+	}{
+		{name: "自动创建账号", provider: "idp"},
+		{name: "模拟提供方自动创建账号", provider: "mock"},
+		{name: "已验证邮箱关联已有账号", provider: "idp", local: ptr(true), wantLinked: true},
+		{name: "本地邮箱未验证时不关联", provider: "idp", local: ptr(false), wantErr: true},
+		{name: "模拟提供方不关联已有账号", provider: "mock", local: ptr(true), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupOIDC(t)
+			const email = "alice@example.com"
+			var local *models.User
+			if tt.local != nil {
+				local = env.createUser(t, "alice", email, *tt.local)
+			}
+
+			tokens, err := (&OIDCService{}).Callback(context.Background(), tt.provider, env.authorize(t, tt.provider, email))
+			userID := env.identityUser(t, tt.provider, email)
+			if tt.wantErr {
+				assertBusinessError(t, err, res.ErrOIDCLoginFailed)
+				if userID != 0 {
+					t.Fatalf("不应创建身份关联，实际关联到用户 %d", userID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tokens.Token == "" || tokens.RefreshToken == "" {
+				t.Fatalf("未签发令牌: %+v", tokens)
+			}
+			if tt.wantLinked && userID != local.ID {
+				t.Fatalf("期望关联到用户 %d，实际为 %d", local.ID, userID)
+			}
+			if !tt.wantLinked {
+				var user models.User
+				if err := env.db.First(&user, userID).Error; err != nil {
+					t.Fatalf("未创建用户: %v", err)
+				}
+				if user.Email != email || user.EmailVerifiedAt == nil {
+					t.Fatalf("创建的用户信息错误: %+v", user)
+				}
+			}
+
+			// 再次登录使用已有的身份关联
+			if _, err := (&OIDCService{}).Callback(context.Background(), tt.provider, env.authorize(t, tt.provider, email)); err != nil {
+				t.Fatalf("再次登录失败: %v", err)
+			}
+			var count int64
+			env.db.Model(&models.User{}).Count(&count)
+			if want := int64(1); count != want {
+				t.Fatalf("期望 %d 个用户，实际为 %d", want, count)
+			}
+		})
+	}
+}
+
+func TestOIDCCallbackRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(env *oidcTestEnv, callback *dto.OIDCCallbackDto) // 在回调前修改请求或授权状态
+		replay bool
+	}{
+		{name: "state 重放", replay: true},
+		{name: "state 不存在", tamper: func(env *oidcTestEnv, callback *dto.OIDCCallbackDto) {
+			callback.State = "unknown"
+		}},
+		{name: "state 已过期", tamper: func(env *oidcTestEnv, callback *dto.OIDCCallbackDto) {
+			env.db.Model(&models.OIDCState{}).Where("state = ?", callback.State).Update("expires_at", time.Now().Add(-time.Minute))
+		}},
+		{name: "nonce 不匹配", tamper: func(env *oidcTestEnv, callback *dto.OIDCCallbackDto) {
+			env.db.Model(&models.OIDCState{}).Where("state = ?", callback.State).Update("nonce", "another-nonce")
+		}},
+		{name: "PKCE 不匹配", tamper: func(env *oidcTestEnv, callback *dto.OIDCCallbackDto) {
+			env.db.Model(&models.OIDCState{}).Where("state = ?", callback.State).Update("code_verifier", "another-verifier")
+		}},
+		{name: "授权码错误", tamper: func(env *oidcTestEnv, callback *dto.OIDCCallbackDto) {
+			callback.Code = "unknown"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupOIDC(t)
+			service := &OIDCService{}
+			callback := env.authorize(t, "idp", "bob@example.com")
+			if tt.tamper != nil {
+				tt.tamper(env, callback)
+			}
+			if tt.replay {
+				if _, err := service.Callback(context.Background(), "idp", callback); err != nil {
+					t.Fatalf("首次回调失败: %v", err)
+				}
+			}
+			_, err := service.Callback(context.Background(), "idp", callback)
+			assertBusinessError(t, err, res.ErrOIDCLoginFailed)
+		})
+	}
+}
+
+// assertBusinessError 校验返回了指定错误码的业务错误
+func assertBusinessError(t *testing.T, err error, want *res.BusinessError) {
+	t.Helper()
+	if !isBusinessError(err, want) {
+		t.Fatalf("期望错误码 %d，实际为 %v", want.Code, err)
+	}
+}
+
+// isBusinessError 是否为指定错误码的业务错误
+func isBusinessError(err error, want *res.BusinessError) bool {
+	var businessErr *res.BusinessError
+	return errors.As(err, &businessErr) && businessErr.Code == want.Code
+}
+
+// ptr 返回值的指针
+func ptr[T any](v T) *T {
+	return &v
+}
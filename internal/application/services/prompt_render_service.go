@@ -0,0 +1,158 @@
+package services
+
+import (
+	"proomet/internal/domain/models"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/validators"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils/converter"
+	"proomet/pkg/utils/render"
+	"proomet/pkg/utils/res"
+	"strings"
+)
+
+type PromptRenderService struct{}
+
+// renderedPrompt 渲染后的提示词内容
+type renderedPrompt struct {
+	promptID   uint
+	version    int
+	promptType string
+	text       string
+	messages   []models.PromptMessage
+	values     map[string]string
+}
+
+// Render 使用传入的变量值渲染提示词，可指定历史版本
+func (s *PromptRenderService) Render(user models.JwtUser, promptID uint, dto *dto.RenderPromptDto) (*vo.RenderPromptVO, error) {
+	rendered, err := s.render(user, promptID, dto.Version, dto.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []vo.PromptMessageVO
+	if rendered.messages != nil {
+		converter.SafeConvertSlice(&messages, &rendered.messages)
+	}
+
+	return &vo.RenderPromptVO{
+		PromptID:  rendered.promptID,
+		Version:   rendered.version,
+		Type:      rendered.promptType,
+		Text:      rendered.text,
+		Messages:  messages,
+		Variables: rendered.values,
+	}, nil
+}
+
+// Export 渲染提示词并转换为 OpenAI / Anthropic 的消息格式，纯文本提示词作为单条 user 消息导出
+func (s *PromptRenderService) Export(user models.JwtUser, promptID uint, dto *dto.ExportPromptDto) (any, error) {
+	rendered, err := s.render(user, promptID, dto.Version, dto.Variables)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := rendered.messages
+	if rendered.promptType != models.PromptTypeChat {
+		messages = []models.PromptMessage{{Role: models.MessageRoleUser, Content: rendered.text}}
+	}
+
+	if dto.Format == "anthropic" {
+		return toAnthropicExport(messages), nil
+	}
+	return toOpenAIExport(messages), nil
+}
+
+// render 校验变量并渲染提示词正文及对话消息
+func (s *PromptRenderService) render(user models.JwtUser, promptID uint, version int, variables map[string]any) (*renderedPrompt, error) {
+	prompt, err := findPrompt(promptID)
+	if err != nil {
+		return nil, err
+	}
+	if !canReadPrompt(user, prompt) {
+		return nil, res.ErrPromptNotFound
+	}
+
+	rendered := &renderedPrompt{
+		promptID:   prompt.ID,
+		version:    prompt.CurrentVersion,
+		promptType: prompt.Type,
+	}
+	body, messages, schema := prompt.Body, prompt.Messages, prompt.Variables
+	if version != 0 && version != prompt.CurrentVersion {
+		promptVersion, err := findPromptVersion(promptID, version)
+		if err != nil {
+			return nil, err
+		}
+		rendered.version = promptVersion.Version
+		rendered.promptType = promptVersion.Type
+		body, messages, schema = promptVersion.Body, promptVersion.Messages, promptVersion.Variables
+	}
+
+	// 对话类提示词的正文由全部消息拼接而成，因此从正文即可收集到所有占位符
+	values, errs := validators.ValidateVariableValues(schema, render.Placeholders(body), variables)
+	if len(errs) > 0 {
+		return nil, res.ErrInvalidParam.Msg("变量校验失败: " + strings.Join(errs, "; "))
+	}
+	rendered.values = values
+
+	if rendered.promptType == models.PromptTypeChat {
+		rendered.messages = make([]models.PromptMessage, 0, len(messages))
+		for _, message := range messages {
+			message.Content = render.Render(message.Content, values)
+			rendered.messages = append(rendered.messages, message)
+		}
+		rendered.text = models.FlattenMessages(rendered.messages)
+	} else {
+		rendered.text = render.Render(body, values)
+	}
+	return rendered, nil
+}
+
+// toOpenAIExport 转换为 OpenAI Chat Completions 消息格式
+func toOpenAIExport(messages []models.PromptMessage) *vo.OpenAIExportVO {
+	export := &vo.OpenAIExportVO{Messages: make([]vo.OpenAIMessageVO, 0, len(messages))}
+	for _, message := range messages {
+		item := vo.OpenAIMessageVO{Role: message.Role, Content: message.Content}
+		// tool 消息通过 tool_call_id 关联调用，其余角色使用 name 区分参与者
+		if message.Role == models.MessageRoleTool {
+			item.ToolCallID = message.Name
+		} else {
+			item.Name = message.Name
+		}
+		export.Messages = append(export.Messages, item)
+	}
+	return export
+}
+
+// toAnthropicExport 转换为 Anthropic Messages API 格式：
+// system 消息合并到顶层 system 字段，tool 消息转为 user 角色的 tool_result 内容块，相邻同角色消息合并
+func toAnthropicExport(messages []models.PromptMessage) *vo.AnthropicExportVO {
+	export := &vo.AnthropicExportVO{Messages: make([]vo.AnthropicMessageVO, 0, len(messages))}
+	var systemParts []string
+
+	for _, message := range messages {
+		role := message.Role
+		block := vo.AnthropicContentBlockVO{Type: "text", Text: message.Content}
+		switch message.Role {
+		case models.MessageRoleSystem:
+			systemParts = append(systemParts, message.Content)
+			continue
+		case models.MessageRoleTool:
+			role = models.MessageRoleUser
+			block = vo.AnthropicContentBlockVO{Type: "tool_result", ToolUseID: message.Name, Content: message.Content}
+		}
+
+		if last := len(export.Messages) - 1; last >= 0 && export.Messages[last].Role == role {
+			export.Messages[last].Content = append(export.Messages[last].Content, block)
+			continue
+		}
+		export.Messages = append(export.Messages, vo.AnthropicMessageVO{
+			Role:    role,
+			Content: []vo.AnthropicContentBlockVO{block},
+		})
+	}
+
+	export.System = strings.Join(systemParts, "\n\n")
+	return export
+}
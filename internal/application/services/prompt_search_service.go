@@ -0,0 +1,117 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils"
+	"proomet/pkg/utils/res"
+)
+
+type PromptSearchService struct{}
+
+// searchCursor 游标内容：上一页最后一条记录的相关度与ID
+type searchCursor struct {
+	Rank float64 `json:"r"`
+	ID   uint    `json:"id"`
+}
+
+// searchHeadlineOptions ts_headline 高亮参数
+const searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter= ... "
+
+// Search 基于 tsvector 的全文检索，按相关度排序并使用游标分页
+func (s *PromptSearchService) Search(user models.JwtUser, dto *dto.SearchPromptDto) (*vo.CursorPageVO[vo.PromptSearchHitVO], error) {
+	db := database.GetDB()
+	limit := utils.DefaultInt(dto.Limit, 20)
+
+	// 内层查询计算相关度，外层基于相关度做游标过滤，高亮只对当前页计算
+	ranked := db.Model(&models.Prompt{}).
+		Select(`prompts.id, prompts.title, prompts.description, prompts.body, prompts.type,
+			prompts.owner_id, prompts.collection_id, prompts.visibility, prompts.updated_at,
+			ts_rank_cd(prompts.search_vector, q)::float8 AS rank, q`).
+		Joins("CROSS JOIN websearch_to_tsquery(?::regconfig, ?) AS q", database.SearchConfig, dto.Q).
+		Where("prompts.search_vector @@ q").
+		Scopes(readablePrompts(user))
+
+	if names := splitTagNames(dto.Tags); len(names) > 0 {
+		ranked = ranked.Where("prompts.id IN (?)", promptIDsByTags(db, names).
+			Group("prompt_tags.prompt_id").
+			Having("COUNT(DISTINCT prompt_tags.tag_id) = ?", len(names)))
+	}
+	if dto.CollectionID != 0 {
+		collectionIDs := []uint{dto.CollectionID}
+		if dto.Recursive {
+			var err error
+			if collectionIDs, err = collectionSubtreeIDs(db, dto.CollectionID); err != nil {
+				return nil, res.ErrInternalServer.Msg("查询集合失败")
+			}
+		}
+		ranked = ranked.Where("prompts.collection_id IN ?", collectionIDs)
+	}
+	if dto.OwnerID != 0 {
+		ranked = ranked.Where("prompts.owner_id = ?", dto.OwnerID)
+	}
+
+	query := db.Table("(?) AS ranked", ranked).
+		Select(`ranked.id, ranked.title, ranked.description, ranked.type, ranked.owner_id,
+			ranked.collection_id, ranked.visibility, ranked.updated_at, ranked.rank,
+			ts_headline(?::regconfig, `+escapeHTMLSQL("ranked.title")+`, ranked.q, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight,
+			ts_headline(?::regconfig, `+escapeHTMLSQL("ranked.body")+`, ranked.q, ?) AS snippet`,
+			database.SearchConfig, database.SearchConfig, searchHeadlineOptions)
+
+	if dto.Cursor != "" {
+		cursor, err := decodeSearchCursor(dto.Cursor)
+		if err != nil {
+			return nil, res.ErrInvalidParam.Msg("游标格式错误")
+		}
+		query = query.Where("ranked.rank < ? OR (ranked.rank = ? AND ranked.id < ?)", cursor.Rank, cursor.Rank, cursor.ID)
+	}
+
+	var rows []vo.PromptSearchHitVO
+	if err := query.Order("ranked.rank DESC, ranked.id DESC").Limit(limit + 1).Scan(&rows).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("检索提示词失败")
+	}
+
+	page := &vo.CursorPageVO[vo.PromptSearchHitVO]{
+		List: make([]vo.PromptSearchHitVO, 0, limit),
+	}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.HasMore = true
+	}
+	page.List = append(page.List, rows...)
+	if page.HasMore {
+		last := rows[len(rows)-1]
+		page.NextCursor = encodeSearchCursor(searchCursor{Rank: last.Rank, ID: last.ID})
+	}
+	return page, nil
+}
+
+// escapeHTMLSQL 生成对列做 HTML 转义的 SQL 表达式，保证高亮结果中只有 <mark> 是未转义的标签
+// 默认解析器会把 &amp; 等实体识别为独立 token，转义不影响匹配
+func escapeHTMLSQL(column string) string {
+	return "replace(replace(replace(replace(replace(" + column +
+		`, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+}
+
+// encodeSearchCursor 编码游标
+func encodeSearchCursor(cursor searchCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeSearchCursor 解码游标
+func decodeSearchCursor(value string) (*searchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	var cursor searchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,431 @@
+package services
+
+import (
+	"errors"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/validators"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils"
+	"proomet/pkg/utils/converter"
+	"proomet/pkg/utils/res"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromptService struct{}
+
+// Create 创建提示词
+func (s *PromptService) Create(user models.JwtUser, dto *dto.CreatePromptDto) (*vo.PromptVO, error) {
+	if user.UserID == 0 {
+		return nil, res.ErrUnauthorized
+	}
+	db := database.GetDB()
+
+	variables, err := toPromptVariables(dto.Variables)
+	if err != nil {
+		return nil, err
+	}
+	if dto.CollectionID != nil {
+		if err := checkTargetCollection(user, *dto.CollectionID, dto.WorkspaceID); err != nil {
+			return nil, err
+		}
+	}
+	if user.WorkspaceID != 0 && (dto.WorkspaceID == nil || *dto.WorkspaceID != user.WorkspaceID) {
+		return nil, res.ErrForbidden.Msg("工作区 API Key 只能在所属工作区内创建提示词")
+	}
+	if dto.WorkspaceID != nil {
+		if _, _, err := findWorkspaceFor(user, *dto.WorkspaceID, models.WorkspaceRoleEditor); err != nil {
+			return nil, err
+		}
+	}
+
+	prompt := models.Prompt{
+		Title:        dto.Title,
+		Type:         utils.DefaultString(dto.Type, models.PromptTypeText),
+		Messages:     toPromptMessages(dto.Messages),
+		Body:         dto.Body,
+		Description:  dto.Description,
+		Variables:    variables,
+		WorkspaceID:  dto.WorkspaceID,
+		CollectionID: dto.CollectionID,
+		OwnerID:      user.UserID,
+		Visibility:   utils.DefaultString(dto.Visibility, models.VisibilityPrivate),
+	}
+	if err := normalizePromptContent(&prompt); err != nil {
+		return nil, err
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		tags, err := resolveTags(tx, dto.Tags)
+		if err != nil {
+			return err
+		}
+		prompt.Tags = tags
+		if err := tx.Create(&prompt).Error; err != nil {
+			return err
+		}
+		return createPromptVersion(tx, &prompt, user.UserID, utils.DefaultString(dto.Message, "初始版本"))
+	})
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("创建提示词失败")
+	}
+
+	return toPromptVO(&prompt), nil
+}
+
+// Get 获取提示词详情
+func (s *PromptService) Get(user models.JwtUser, id uint) (*vo.PromptVO, error) {
+	prompt, err := findPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canReadPrompt(user, prompt) {
+		return nil, res.ErrPromptNotFound
+	}
+	return toPromptVO(prompt), nil
+}
+
+// Update 更新提示词
+func (s *PromptService) Update(user models.JwtUser, id uint, dto *dto.UpdatePromptDto) (*vo.PromptVO, error) {
+	db := database.GetDB()
+
+	prompt, err := findPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canWritePrompt(user, prompt) {
+		return nil, res.ErrForbidden.Msg("无权修改该提示词")
+	}
+
+	previousHash := prompt.ContentHash()
+	if dto.Title != nil {
+		prompt.Title = *dto.Title
+	}
+	if dto.Type != nil {
+		prompt.Type = *dto.Type
+	}
+	if dto.Body != nil {
+		prompt.Body = *dto.Body
+	}
+	if dto.Messages != nil {
+		prompt.Messages = toPromptMessages(dto.Messages)
+	}
+	if dto.Description != nil {
+		prompt.Description = *dto.Description
+	}
+	if dto.Variables != nil {
+		variables, err := toPromptVariables(dto.Variables)
+		if err != nil {
+			return nil, err
+		}
+		prompt.Variables = variables
+	}
+	if dto.Visibility != nil {
+		prompt.Visibility = *dto.Visibility
+	}
+	if err := normalizePromptContent(prompt); err != nil {
+		return nil, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// 内容发生变化时才生成新版本
+		if prompt.ContentHash() != previousHash {
+			if err := createPromptVersion(tx, prompt, user.UserID, dto.Message); err != nil {
+				return err
+			}
+		}
+		return tx.Omit(clause.Associations).Save(prompt).Error
+	})
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("更新提示词失败")
+	}
+
+	return toPromptVO(prompt), nil
+}
+
+// Delete 删除提示词
+func (s *PromptService) Delete(user models.JwtUser, id uint) error {
+	db := database.GetDB()
+
+	prompt, err := findPrompt(id)
+	if err != nil {
+		return err
+	}
+	if !canWritePrompt(user, prompt) {
+		return res.ErrForbidden.Msg("无权删除该提示词")
+	}
+
+	if err := db.Delete(prompt).Error; err != nil {
+		return res.ErrInternalServer.Msg("删除提示词失败")
+	}
+	return nil
+}
+
+// List 分页查询提示词
+func (s *PromptService) List(user models.JwtUser, dto *dto.ListPromptDto) (*vo.PageVO[vo.PromptVO], error) {
+	db := database.GetDB()
+
+	page := utils.DefaultInt(dto.Page, 1)
+	pageSize := utils.DefaultInt(dto.PageSize, 20)
+
+	var err error
+	query := db.Model(&models.Prompt{})
+	if dto.WorkspaceID != 0 {
+		// 工作区成员可以查看工作区内的全部提示词
+		if _, _, err := findWorkspaceFor(user, dto.WorkspaceID, models.WorkspaceRoleViewer); err != nil {
+			return nil, err
+		}
+		query = query.Where("workspace_id = ?", dto.WorkspaceID)
+	}
+	switch dto.Scope {
+	case "mine":
+		// 仍需满足可见范围，工作区 API Key 只能看到该工作区内自己的提示词
+		query = query.Scopes(readablePrompts(user)).Where("prompts.owner_id = ?", user.UserID)
+	case "public":
+		query = query.Where("visibility = ?", models.VisibilityPublic)
+	default:
+		query = query.Scopes(readablePrompts(user))
+	}
+	if dto.Keyword != "" {
+		like := "%" + dto.Keyword + "%"
+		query = query.Where("title ILIKE ? OR description ILIKE ?", like, like)
+	}
+	if names := splitTagNames(dto.Tags); len(names) > 0 {
+		// 必须同时包含全部标签
+		query = query.Where("prompts.id IN (?)", promptIDsByTags(db, names).
+			Group("prompt_tags.prompt_id").
+			Having("COUNT(DISTINCT prompt_tags.tag_id) = ?", len(names)))
+	}
+	if names := splitTagNames(dto.AnyTags); len(names) > 0 {
+		query = query.Where("prompts.id IN (?)", promptIDsByTags(db, names))
+	}
+	if dto.CollectionID != 0 {
+		collectionIDs := []uint{dto.CollectionID}
+		if dto.Recursive {
+			if collectionIDs, err = collectionSubtreeIDs(db, dto.CollectionID); err != nil {
+				return nil, res.ErrInternalServer.Msg("查询集合失败")
+			}
+		}
+		query = query.Where("collection_id IN ?", collectionIDs)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询提示词失败")
+	}
+
+	var prompts []models.Prompt
+	if err := query.Preload("Tags").Order("updated_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&prompts).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询提示词失败")
+	}
+
+	list := make([]vo.PromptVO, 0, len(prompts))
+	for i := range prompts {
+		list = append(list, *toPromptVO(&prompts[i]))
+	}
+
+	return &vo.PageVO[vo.PromptVO]{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// Move 移动提示词到集合
+func (s *PromptService) Move(user models.JwtUser, id uint, dto *dto.MovePromptDto) (*vo.PromptVO, error) {
+	db := database.GetDB()
+
+	prompt, err := findPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canWritePrompt(user, prompt) {
+		return nil, res.ErrForbidden.Msg("无权修改该提示词")
+	}
+	if dto.CollectionID != nil {
+		if err := checkTargetCollection(user, *dto.CollectionID, prompt.WorkspaceID); err != nil {
+			return nil, err
+		}
+	}
+
+	prompt.CollectionID = dto.CollectionID
+	if err := db.Model(prompt).Update("collection_id", dto.CollectionID).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("移动提示词失败")
+	}
+	return toPromptVO(prompt), nil
+}
+
+// SetTags 设置提示词标签（整体替换）
+func (s *PromptService) SetTags(user models.JwtUser, id uint, dto *dto.SetPromptTagsDto) (*vo.PromptVO, error) {
+	db := database.GetDB()
+
+	prompt, err := findPrompt(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canWritePrompt(user, prompt) {
+		return nil, res.ErrForbidden.Msg("无权修改该提示词")
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		tags, err := resolveTags(tx, dto.Tags)
+		if err != nil {
+			return err
+		}
+		prompt.Tags = tags
+		return tx.Model(prompt).Association("Tags").Replace(tags)
+	})
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("设置标签失败")
+	}
+	return toPromptVO(prompt), nil
+}
+
+// readablePrompts 限定为当前用户可查看的提示词：管理员可以看到全部，
+// 其他用户可以看到自己的个人提示词、公开的提示词以及所在工作区的提示词；工作区身份只能看到该工作区的提示词
+func readablePrompts(user models.JwtUser) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if user.Role == models.RoleAdmin {
+			return db
+		}
+		memberships := db.Session(&gorm.Session{NewDB: true}).
+			Model(&models.WorkspaceMember{}).
+			Select("workspace_id").
+			Where("user_id = ?", user.UserID)
+		if user.WorkspaceID != 0 {
+			return db.Where("prompts.visibility = ? OR prompts.workspace_id IN (?)",
+				models.VisibilityPublic, memberships.Where("workspace_id = ?", user.WorkspaceID))
+		}
+		return db.Where(
+			"(prompts.workspace_id IS NULL AND prompts.owner_id = ?) OR prompts.visibility = ? OR prompts.workspace_id IN (?)",
+			user.UserID, models.VisibilityPublic, memberships,
+		)
+	}
+}
+
+// promptIDsByTags 构造按标签名筛选提示词ID的子查询
+func promptIDsByTags(db *gorm.DB, names []string) *gorm.DB {
+	return db.Table("prompt_tags").
+		Select("prompt_tags.prompt_id").
+		Joins("JOIN tags ON tags.id = prompt_tags.tag_id").
+		Where("tags.name IN ?", names)
+}
+
+// checkTargetCollection 校验目标集合存在且当前用户有权使用
+// 集合属于个人，只能存放个人提示词，避免工作区提示词在成员退出后仍出现在其集合中
+func checkTargetCollection(user models.JwtUser, collectionID uint, workspaceID *uint) error {
+	if workspaceID != nil {
+		return res.ErrInvalidParam.Msg("工作区提示词不能放入个人集合")
+	}
+	collection, err := findCollection(collectionID)
+	if err != nil {
+		return err
+	}
+	if !canManageCollection(user, collection) {
+		return res.ErrForbidden.Msg("无权操作目标集合")
+	}
+	return nil
+}
+
+// findPrompt 根据ID查询提示词（含标签）
+func findPrompt(id uint) (*models.Prompt, error) {
+	var prompt models.Prompt
+	if err := database.GetDB().Preload("Tags").First(&prompt, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, res.ErrPromptNotFound
+		}
+		return nil, res.ErrInternalServer.Msg("查询提示词失败")
+	}
+	return &prompt, nil
+}
+
+// canReadPrompt 是否可以查看提示词，工作区内的提示词对全部成员可见
+func canReadPrompt(user models.JwtUser, prompt *models.Prompt) bool {
+	if prompt.Visibility == models.VisibilityPublic || canWritePrompt(user, prompt) {
+		return true
+	}
+	return inIdentityWorkspace(user, prompt) && prompt.WorkspaceID != nil &&
+		promptWorkspaceRoleAtLeast(user, prompt, models.WorkspaceRoleViewer)
+}
+
+// canWritePrompt 是否可以修改提示词，工作区内的提示词需要编辑者及以上角色
+func canWritePrompt(user models.JwtUser, prompt *models.Prompt) bool {
+	if !inIdentityWorkspace(user, prompt) {
+		return false
+	}
+	if user.Role == models.RoleAdmin {
+		return true
+	}
+	if prompt.WorkspaceID != nil {
+		return promptWorkspaceRoleAtLeast(user, prompt, models.WorkspaceRoleEditor)
+	}
+	return user.UserID != 0 && prompt.OwnerID == user.UserID
+}
+
+// inIdentityWorkspace 身份限定在工作区内时（工作区 API Key），提示词必须属于该工作区
+func inIdentityWorkspace(user models.JwtUser, prompt *models.Prompt) bool {
+	if user.WorkspaceID == 0 {
+		return true
+	}
+	return prompt.WorkspaceID != nil && *prompt.WorkspaceID == user.WorkspaceID
+}
+
+// promptWorkspaceRoleAtLeast 当前用户在提示词所属工作区内的角色是否不低于 required
+func promptWorkspaceRoleAtLeast(user models.JwtUser, prompt *models.Prompt, required string) bool {
+	role, err := workspaceRoleOf(*prompt.WorkspaceID, user.UserID)
+	return err == nil && models.WorkspaceRoleAtLeast(role, required)
+}
+
+// normalizePromptContent 按提示词类型整理内容：对话类的正文由消息生成，纯文本类不保留消息
+func normalizePromptContent(prompt *models.Prompt) error {
+	switch prompt.Type {
+	case models.PromptTypeChat:
+		if len(prompt.Messages) == 0 {
+			return res.ErrInvalidParam.Msg("对话类提示词至少需要一条消息")
+		}
+		prompt.Body = models.FlattenMessages(prompt.Messages)
+	default:
+		prompt.Type = models.PromptTypeText
+		prompt.Messages = nil
+		if prompt.Body == "" {
+			return res.ErrInvalidParam.Msg("正文不能为空")
+		}
+	}
+	return nil
+}
+
+// toPromptMessages 转换对话消息
+func toPromptMessages(dtos []dto.PromptMessageDto) []models.PromptMessage {
+	if dtos == nil {
+		return nil
+	}
+	messages := make([]models.PromptMessage, 0, len(dtos))
+	converter.SafeConvertSlice(&messages, &dtos)
+	return messages
+}
+
+// toPromptVariables 转换并校验变量定义
+func toPromptVariables(dtos []dto.PromptVariableDto) ([]models.PromptVariable, error) {
+	variables := make([]models.PromptVariable, 0, len(dtos))
+	if err := converter.Convert(&variables, &dtos); err != nil {
+		return nil, res.ErrInvalidParam.Msg("变量定义格式错误")
+	}
+	if errs := validators.ValidateVariableSchema(variables); len(errs) > 0 {
+		return nil, res.ErrInvalidParam.Msg(strings.Join(errs, "; "))
+	}
+	return variables, nil
+}
+
+// toPromptVO 模型转换为VO
+func toPromptVO(prompt *models.Prompt) *vo.PromptVO {
+	var promptVO vo.PromptVO
+	converter.SafeConvert(&promptVO, prompt)
+	return &promptVO
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"maps"
+	"proomet/internal/domain/models"
+	"proomet/internal/interfaces/dto"
+	"proomet/pkg/utils/res"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// setupPromptDB 在测试数据库中创建提示词、集合与工作区相关的表
+func setupPromptDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(
+		&models.Prompt{}, &models.Tag{}, &models.Collection{},
+		&models.Workspace{}, &models.WorkspaceMember{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// createPrompt 创建提示词，workspaceID 为 0 时为个人提示词
+func createPrompt(t *testing.T, db *gorm.DB, title string, ownerID, workspaceID uint, visibility string) *models.Prompt {
+	t.Helper()
+	prompt := models.Prompt{Title: title, Body: title, OwnerID: ownerID, Visibility: visibility}
+	if workspaceID != 0 {
+		prompt.WorkspaceID = &workspaceID
+	}
+	if err := db.Create(&prompt).Error; err != nil {
+		t.Fatal(err)
+	}
+	return &prompt
+}
+
+// addWorkspaceMember 创建工作区成员
+func addWorkspaceMember(t *testing.T, db *gorm.DB, workspaceID, userID uint) {
+	t.Helper()
+	member := models.WorkspaceMember{WorkspaceID: workspaceID, UserID: userID, Role: models.WorkspaceRoleEditor}
+	if err := db.Create(&member).Error; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPromptListMine(t *testing.T) {
+	db := setupPromptDB(t)
+	const alice, bob = 1, 2
+	addWorkspaceMember(t, db, 10, alice)
+	addWorkspaceMember(t, db, 20, alice)
+	createPrompt(t, db, "个人", alice, 0, models.VisibilityPrivate)
+	createPrompt(t, db, "工作区10", alice, 10, models.VisibilityPrivate)
+	createPrompt(t, db, "工作区20", alice, 20, models.VisibilityPrivate)
+	// 已退出的工作区中自己创建的提示词
+	createPrompt(t, db, "工作区30", alice, 30, models.VisibilityPrivate)
+	createPrompt(t, db, "他人", bob, 10, models.VisibilityPrivate)
+
+	tests := []struct {
+		name string
+		user models.JwtUser
+		want []string
+	}{
+		{
+			name: "个人身份",
+			user: models.JwtUser{UserID: alice, Role: models.RoleMember},
+			want: []string{"个人", "工作区10", "工作区20"},
+		},
+		{
+			name: "工作区 API Key 只能看到该工作区",
+			user: models.JwtUser{UserID: alice, Role: models.RoleMember, WorkspaceID: 10},
+			want: []string{"工作区10"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := (&PromptService{}).List(tt.user, &dto.ListPromptDto{Scope: "mine"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var titles []string
+			for _, prompt := range page.List {
+				titles = append(titles, prompt.Title)
+			}
+			slices.Sort(titles)
+			slices.Sort(tt.want)
+			if !slices.Equal(titles, tt.want) {
+				t.Fatalf("查询结果为 %v，期望 %v", titles, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagListVisibility(t *testing.T) {
+	db := setupPromptDB(t)
+	const alice, bob = 1, 2
+	addWorkspaceMember(t, db, 10, alice)
+	tagged := func(prompt *models.Prompt, names ...string) {
+		tags, err := resolveTags(db, names)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Model(prompt).Association("Tags").Append(tags); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tagged(createPrompt(t, db, "alice 个人", alice, 0, models.VisibilityPrivate), "mine", "shared")
+	tagged(createPrompt(t, db, "bob 个人", bob, 0, models.VisibilityPrivate), "secret", "shared")
+	tagged(createPrompt(t, db, "bob 公开", bob, 0, models.VisibilityPublic), "open")
+	tagged(createPrompt(t, db, "工作区10", bob, 10, models.VisibilityPrivate), "team")
+	tagged(createPrompt(t, db, "工作区20", bob, 20, models.VisibilityPrivate), "other-team")
+
+	tests := []struct {
+		name string
+		user models.JwtUser
+		want map[string]int64
+	}{
+		{
+			name: "成员只能看到可见提示词的标签",
+			user: models.JwtUser{UserID: alice, Role: models.RoleMember},
+			want: map[string]int64{"mine": 1, "shared": 1, "open": 1, "team": 1},
+		},
+		{
+			name: "访客只能看到公开提示词的标签",
+			user: models.JwtUser{UserID: 3, Role: models.RoleGuest},
+			want: map[string]int64{"open": 1},
+		},
+		{
+			name: "管理员可以看到全部",
+			user: models.JwtUser{UserID: 4, Role: models.RoleAdmin},
+			want: map[string]int64{"mine": 1, "shared": 2, "secret": 1, "open": 1, "team": 1, "other-team": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := (&TagService{}).List(tt.user, &dto.ListTagDto{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make(map[string]int64, len(list))
+			for _, tag := range list {
+				got[tag.Name] = tag.PromptCount
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Fatalf("标签为 %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromptCollectionWorkspace(t *testing.T) {
+	db := setupPromptDB(t)
+	const alice = 1
+	user := models.JwtUser{UserID: alice, Role: models.RoleMember}
+	addWorkspaceMember(t, db, 10, alice)
+	collection := models.Collection{Name: "个人集合", OwnerID: alice}
+	if err := db.Create(&collection).Error; err != nil {
+		t.Fatal(err)
+	}
+	personal := createPrompt(t, db, "个人", alice, 0, models.VisibilityPrivate)
+	workspace := createPrompt(t, db, "工作区", alice, 10, models.VisibilityPrivate)
+
+	tests := []struct {
+		name string
+		run  func() error
+		want *res.BusinessError
+	}{
+		{
+			name: "个人提示词移入个人集合",
+			run: func() error {
+				_, err := (&PromptService{}).Move(user, personal.ID, &dto.MovePromptDto{CollectionID: &collection.ID})
+				return err
+			},
+		},
+		{
+			name: "工作区提示词不能移入个人集合",
+			run: func() error {
+				_, err := (&PromptService{}).Move(user, workspace.ID, &dto.MovePromptDto{CollectionID: &collection.ID})
+				return err
+			},
+			want: res.ErrInvalidParam,
+		},
+		{
+			name: "创建工作区提示词时不能指定个人集合",
+			run: func() error {
+				workspaceID := uint(10)
+				_, err := (&PromptService{}).Create(user, &dto.CreatePromptDto{
+					Title: "新提示词", Body: "正文", WorkspaceID: &workspaceID, CollectionID: &collection.ID,
+				})
+				return err
+			},
+			want: res.ErrInvalidParam,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if tt.want == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			assertBusinessError(t, err, tt.want)
+		})
+	}
+}
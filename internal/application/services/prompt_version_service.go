@@ -0,0 +1,238 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils"
+	"proomet/pkg/utils/converter"
+	"proomet/pkg/utils/diff"
+	"proomet/pkg/utils/res"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromptVersionService struct{}
+
+// List 分页查询提示词的版本历史
+func (s *PromptVersionService) List(user models.JwtUser, promptID uint, dto *dto.ListPromptVersionDto) (*vo.PageVO[vo.PromptVersionVO], error) {
+	db := database.GetDB()
+
+	prompt, err := findPrompt(promptID)
+	if err != nil {
+		return nil, err
+	}
+	if !canReadPrompt(user, prompt) {
+		return nil, res.ErrPromptNotFound
+	}
+
+	page := utils.DefaultInt(dto.Page, 1)
+	pageSize := utils.DefaultInt(dto.PageSize, 20)
+
+	query := db.Model(&models.PromptVersion{}).Where("prompt_id = ?", promptID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询版本失败")
+	}
+
+	var versions []models.PromptVersion
+	if err := query.Order("version DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&versions).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询版本失败")
+	}
+
+	list := make([]vo.PromptVersionVO, 0, len(versions))
+	for i := range versions {
+		list = append(list, *toPromptVersionVO(&versions[i]))
+	}
+
+	return &vo.PageVO[vo.PromptVersionVO]{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// Get 获取指定版本
+func (s *PromptVersionService) Get(user models.JwtUser, promptID uint, version int) (*vo.PromptVersionVO, error) {
+	prompt, err := findPrompt(promptID)
+	if err != nil {
+		return nil, err
+	}
+	if !canReadPrompt(user, prompt) {
+		return nil, res.ErrPromptNotFound
+	}
+
+	promptVersion, err := findPromptVersion(promptID, version)
+	if err != nil {
+		return nil, err
+	}
+	return toPromptVersionVO(promptVersion), nil
+}
+
+// maxDiffCost 单个字段对比允许的最大编辑距离（新增与删除的 token 数之和），超出时拒绝对比
+const maxDiffCost = 20000
+
+// Diff 对比两个版本之间的差异
+func (s *PromptVersionService) Diff(user models.JwtUser, promptID uint, dto *dto.DiffPromptVersionDto) (*vo.PromptDiffVO, error) {
+	prompt, err := findPrompt(promptID)
+	if err != nil {
+		return nil, err
+	}
+	if !canReadPrompt(user, prompt) {
+		return nil, res.ErrPromptNotFound
+	}
+
+	from, err := findPromptVersion(promptID, dto.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := findPromptVersion(promptID, dto.To)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := utils.DefaultString(dto.Mode, "line")
+	split := diff.SplitLines
+	if mode == "word" {
+		split = diff.SplitWords
+	}
+
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"title", from.Title, to.Title},
+		{"description", from.Description, to.Description},
+		{"body", from.Body, to.Body},
+		{"variables", marshalVariables(from.Variables), marshalVariables(to.Variables)},
+	}
+
+	changes := make([]vo.PromptFieldDiffVO, 0, len(fields))
+	for _, field := range fields {
+		ops, err := diff.DiffLimit(split(field.from), split(field.to), maxDiffCost)
+		if errors.Is(err, diff.ErrTooComplex) {
+			return nil, res.ErrPromptDiffTooLarge
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !diff.Changed(ops) {
+			continue
+		}
+		insertions, deletions := diff.Stats(ops)
+		changes = append(changes, vo.PromptFieldDiffVO{
+			Field:      field.name,
+			Ops:        ops,
+			Insertions: insertions,
+			Deletions:  deletions,
+		})
+	}
+
+	return &vo.PromptDiffVO{
+		PromptID:    promptID,
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Mode:        mode,
+		Changes:     changes,
+	}, nil
+}
+
+// Rollback 以指定的历史版本内容创建一个新版本
+func (s *PromptVersionService) Rollback(user models.JwtUser, promptID uint, version int, dto *dto.RollbackPromptDto) (*vo.PromptVO, error) {
+	db := database.GetDB()
+
+	prompt, err := findPrompt(promptID)
+	if err != nil {
+		return nil, err
+	}
+	if !canWritePrompt(user, prompt) {
+		return nil, res.ErrForbidden.Msg("无权修改该提示词")
+	}
+
+	target, err := findPromptVersion(promptID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt.Title = target.Title
+	prompt.Type = target.Type
+	prompt.Messages = target.Messages
+	prompt.Body = target.Body
+	prompt.Description = target.Description
+	prompt.Variables = target.Variables
+
+	message := utils.DefaultString(dto.Message, fmt.Sprintf("回滚到版本 %d", target.Version))
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := createPromptVersion(tx, prompt, user.UserID, message); err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Save(prompt).Error
+	})
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("回滚提示词失败")
+	}
+
+	return toPromptVO(prompt), nil
+}
+
+// createPromptVersion 在事务内为提示词追加一个新版本，并同步更新当前版本号
+func createPromptVersion(tx *gorm.DB, prompt *models.Prompt, authorID uint, message string) error {
+	// 锁定提示词行，保证并发更新时版本号连续且不冲突
+	var locked models.Prompt
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "current_version").
+		First(&locked, prompt.ID).Error; err != nil {
+		return err
+	}
+
+	version := models.NewPromptVersion(prompt, locked.CurrentVersion+1, authorID, message)
+	if err := tx.Create(version).Error; err != nil {
+		return err
+	}
+
+	prompt.CurrentVersion = version.Version
+	return tx.Model(&models.Prompt{}).
+		Where("id = ?", prompt.ID).
+		Update("current_version", version.Version).Error
+}
+
+// findPromptVersion 查询提示词的指定版本
+func findPromptVersion(promptID uint, version int) (*models.PromptVersion, error) {
+	var promptVersion models.PromptVersion
+	err := database.GetDB().
+		Where("prompt_id = ? AND version = ?", promptID, version).
+		First(&promptVersion).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, res.ErrPromptVersionNotFound
+		}
+		return nil, res.ErrInternalServer.Msg("查询版本失败")
+	}
+	return &promptVersion, nil
+}
+
+// marshalVariables 将变量定义格式化为多行 JSON，便于逐行对比
+func marshalVariables(variables []models.PromptVariable) string {
+	if len(variables) == 0 {
+		return ""
+	}
+	data, _ := json.MarshalIndent(variables, "", "  ")
+	return string(data) + "\n"
+}
+
+// toPromptVersionVO 模型转换为VO
+func toPromptVersionVO(version *models.PromptVersion) *vo.PromptVersionVO {
+	var versionVO vo.PromptVersionVO
+	converter.SafeConvert(&versionVO, version)
+	return &versionVO
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"errors"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/auth"
+	"proomet/internal/infra/database"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils"
+	"proomet/pkg/utils/res"
+
+	"gorm.io/gorm"
+)
+
+// RbacService Casbin 策略管理，修改通过适配器持久化并立即在内存中生效
+type RbacService struct{}
+
+// ListPolicies 查询权限策略
+func (s *RbacService) ListPolicies(dto *dto.ListPolicyDto) ([]vo.PolicyVO, error) {
+	rules, err := auth.GetEnforcer().GetFilteredPolicy(0, dto.Sub, dto.Dom, dto.Obj)
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("查询策略失败")
+	}
+	list := make([]vo.PolicyVO, 0, len(rules))
+	for _, rule := range rules {
+		if policy := toPolicyVO(rule); policy != nil {
+			list = append(list, *policy)
+		}
+	}
+	return list, nil
+}
+
+// AddPolicy 添加权限策略
+func (s *RbacService) AddPolicy(dto *dto.PolicyDto) (*vo.PolicyVO, error) {
+	policy := vo.PolicyVO{Sub: dto.Sub, Dom: utils.DefaultString(dto.Dom, auth.GlobalDomain), Obj: dto.Obj, Act: dto.Act}
+	added, err := auth.GetEnforcer().AddPolicy(policy.Sub, policy.Dom, policy.Obj, policy.Act)
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("添加策略失败")
+	}
+	if !added {
+		return nil, res.ErrPolicyExists
+	}
+	return &policy, nil
+}
+
+// RemovePolicy 删除权限策略
+func (s *RbacService) RemovePolicy(dto *dto.PolicyDto) error {
+	removed, err := auth.GetEnforcer().RemovePolicy(dto.Sub, utils.DefaultString(dto.Dom, auth.GlobalDomain), dto.Obj, dto.Act)
+	if err != nil {
+		return res.ErrInternalServer.Msg("删除策略失败")
+	}
+	if !removed {
+		return res.ErrPolicyNotFound
+	}
+	return nil
+}
+
+// ListGroupings 查询角色授予
+func (s *RbacService) ListGroupings(dto *dto.ListGroupingDto) ([]vo.GroupingVO, error) {
+	return listGroupings(dto.Sub, dto.Role, dto.Dom)
+}
+
+// AddGrouping 添加角色授予
+func (s *RbacService) AddGrouping(dto *dto.GroupingDto) (*vo.GroupingVO, error) {
+	return addGrouping(dto.Sub, dto.Role, dto.Dom)
+}
+
+// RemoveGrouping 删除角色授予
+func (s *RbacService) RemoveGrouping(dto *dto.GroupingDto) error {
+	return removeGrouping(dto.Sub, dto.Role, dto.Dom)
+}
+
+// GetUserRoles 查询用户的全局角色及角色授予
+func (s *RbacService) GetUserRoles(userID uint) (*vo.UserRolesVO, error) {
+	user, err := findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	subject := auth.UserSubject(user.ID)
+	grants, err := listGroupings(subject, "", "")
+	if err != nil {
+		return nil, err
+	}
+	return &vo.UserRolesVO{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     user.Role,
+		Subject:  subject,
+		Grants:   grants,
+	}, nil
+}
+
+// GrantUserRole 在指定域内为用户授予角色，立即生效
+func (s *RbacService) GrantUserRole(userID uint, dto *dto.UserRoleGrantDto) (*vo.GroupingVO, error) {
+	if err := checkUserRoleGrant(dto); err != nil {
+		return nil, err
+	}
+	if _, err := findUser(userID); err != nil {
+		return nil, err
+	}
+	return addGrouping(auth.UserSubject(userID), dto.Role, dto.Dom)
+}
+
+// RevokeUserRole 撤销用户在指定域内的角色
+func (s *RbacService) RevokeUserRole(userID uint, dto *dto.UserRoleGrantDto) error {
+	if err := checkUserRoleGrant(dto); err != nil {
+		return err
+	}
+	if _, err := findUser(userID); err != nil {
+		return err
+	}
+	return removeGrouping(auth.UserSubject(userID), dto.Role, dto.Dom)
+}
+
+// SetUserRole 设置用户的全局角色，与用户管理接口一样不能修改自己的角色
+func (s *RbacService) SetUserRole(user models.JwtUser, userID uint, dto *dto.SetUserRoleDto) error {
+	account, err := findUser(userID)
+	if err != nil {
+		return err
+	}
+	if account.Role == dto.Role {
+		return nil
+	}
+	if err := checkChangeRole(user, account); err != nil {
+		return err
+	}
+	if err := database.GetDB().Model(account).Update("role", dto.Role).Error; err != nil {
+		return res.ErrInternalServer.Msg("设置用户角色失败")
+	}
+	return nil
+}
+
+// Enforce 权限决策试运行，不产生任何副作用
+func (s *RbacService) Enforce(dto *dto.EnforceDto) (*vo.EnforceVO, error) {
+	dom := utils.DefaultString(dto.Dom, auth.PersonalDomain)
+
+	var (
+		allowed bool
+		subject string
+		explain []string
+		err     error
+	)
+	if dto.UserID != 0 {
+		user, findErr := findUser(dto.UserID)
+		if findErr != nil {
+			return nil, findErr
+		}
+		jwtUser := models.JwtUser{UserID: user.ID, Username: user.Username, Role: user.Role}
+		allowed, subject, explain, err = auth.EnforceEx(jwtUser, dom, dto.Obj, dto.Act)
+	} else {
+		subject = dto.Sub
+		allowed, explain, err = auth.GetEnforcer().EnforceEx(dto.Sub, dom, dto.Obj, dto.Act)
+	}
+	if err != nil {
+		return nil, res.ErrInvalidParam.Msg("权限决策失败: " + err.Error())
+	}
+
+	return &vo.EnforceVO{
+		Allowed: allowed,
+		Subject: subject,
+		Dom:     dom,
+		Matched: toPolicyVO(explain),
+	}, nil
+}
+
+// listGroupings 按条件查询角色授予，条件为空时不过滤
+func listGroupings(sub, role, dom string) ([]vo.GroupingVO, error) {
+	rules, err := auth.GetEnforcer().GetFilteredGroupingPolicy(0, sub, role, dom)
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("查询角色授予失败")
+	}
+	list := make([]vo.GroupingVO, 0, len(rules))
+	for _, rule := range rules {
+		if len(rule) == 3 {
+			list = append(list, vo.GroupingVO{Sub: rule[0], Role: rule[1], Dom: rule[2]})
+		}
+	}
+	return list, nil
+}
+
+// addGrouping 添加角色授予，域为空时使用全局域
+func addGrouping(sub, role, dom string) (*vo.GroupingVO, error) {
+	grouping := vo.GroupingVO{Sub: sub, Role: role, Dom: utils.DefaultString(dom, auth.GlobalDomain)}
+	added, err := auth.GetEnforcer().AddGroupingPolicy(grouping.Sub, grouping.Role, grouping.Dom)
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("添加角色授予失败")
+	}
+	if !added {
+		return nil, res.ErrPolicyExists.Msg("角色授予已存在")
+	}
+	return &grouping, nil
+}
+
+// removeGrouping 删除角色授予，域为空时使用全局域
+func removeGrouping(sub, role, dom string) error {
+	removed, err := auth.GetEnforcer().RemoveGroupingPolicy(sub, role, utils.DefaultString(dom, auth.GlobalDomain))
+	if err != nil {
+		return res.ErrInternalServer.Msg("删除角色授予失败")
+	}
+	if !removed {
+		return res.ErrPolicyNotFound.Msg("角色授予不存在")
+	}
+	return nil
+}
+
+// checkUserRoleGrant 工作区内的角色由工作区成员接口维护，直接授予会与成员关系不一致
+func checkUserRoleGrant(dto *dto.UserRoleGrantDto) error {
+	if auth.IsWorkspaceScoped(dto.Dom) || auth.IsWorkspaceScoped(dto.Role) {
+		return res.ErrInvalidParam.Msg("工作区角色请通过工作区成员接口管理")
+	}
+	return nil
+}
+
+// findUser 根据ID查询用户
+func findUser(id uint) (*models.User, error) {
+	var user models.User
+	if err := database.GetDB().First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, res.ErrUserNotFound
+		}
+		return nil, res.ErrInternalServer.Msg("查询用户失败")
+	}
+	return &user, nil
+}
+
+// toPolicyVO 转换策略规则，字段数不匹配时返回 nil
+func toPolicyVO(rule []string) *vo.PolicyVO {
+	if len(rule) != 4 {
+		return nil
+	}
+	return &vo.PolicyVO{Sub: rule[0], Dom: rule[1], Obj: rule[2], Act: rule[3]}
+}
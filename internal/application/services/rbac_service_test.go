@@ -0,0 +1,70 @@
+package services
+
+import (
+	"proomet/internal/domain/models"
+	"proomet/internal/interfaces/dto"
+	"proomet/pkg/utils/res"
+	"testing"
+)
+
+func TestSetUserRole(t *testing.T) {
+	tests := []struct {
+		name   string
+		self   bool
+		actor  string
+		target string
+		role   string
+		want   *res.BusinessError
+	}{
+		{name: "管理员修改其他用户", actor: models.RoleAdmin, target: models.RoleMember, role: models.RoleAdmin},
+		{name: "管理员降级其他管理员", actor: models.RoleAdmin, target: models.RoleAdmin, role: models.RoleMember},
+		{name: "不能修改自己的角色", self: true, actor: models.RoleAdmin, target: models.RoleAdmin, role: models.RoleMember, want: res.ErrForbidden},
+		{name: "角色未变化", self: true, actor: models.RoleAdmin, target: models.RoleAdmin, role: models.RoleAdmin},
+		{name: "非管理员不能修改角色", actor: models.RoleMember, target: models.RoleMember, role: models.RoleAdmin, want: res.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := setupTestDB(t)
+			actor := createLoginUser(t, db, "actor", "password")
+			target := actor
+			if !tt.self {
+				target = createLoginUser(t, db, "target", "password")
+			}
+			db.Model(actor).Update("role", tt.actor)
+			db.Model(target).Update("role", tt.target)
+
+			user := models.JwtUser{UserID: actor.ID, Username: actor.Username, Role: tt.actor}
+			err := (&RbacService{}).SetUserRole(user, target.ID, &dto.SetUserRoleDto{Role: tt.role})
+			want := tt.role
+			if tt.want != nil {
+				assertBusinessError(t, err, tt.want)
+				want = tt.target
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			var saved models.User
+			db.First(&saved, target.ID)
+			if saved.Role != want {
+				t.Fatalf("角色为 %s，期望 %s", saved.Role, want)
+			}
+		})
+	}
+}
+
+func TestUserRoleGrantRejectsWorkspace(t *testing.T) {
+	tests := []struct {
+		name string
+		dto  dto.UserRoleGrantDto
+	}{
+		{"工作区域", dto.UserRoleGrantDto{Role: "editor", Dom: "workspace:1"}},
+		{"工作区角色", dto.UserRoleGrantDto{Role: "workspace:editor", Dom: "*"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTestDB(t)
+			_, err := (&RbacService{}).GrantUserRole(1, &tt.dto)
+			assertBusinessError(t, err, res.ErrInvalidParam)
+			assertBusinessError(t, (&RbacService{}).RevokeUserRole(1, &tt.dto), res.ErrInvalidParam)
+		})
+	}
+}
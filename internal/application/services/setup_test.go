@@ -0,0 +1,52 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"proomet/config"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"proomet/pkg/utils"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// setupTestDB 使用内存 SQLite 替换全局数据库并建表，测试结束后关闭
+// 只覆盖不依赖 PostgreSQL 特性（全文检索、行锁等）的逻辑
+func setupTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	utils.Log = &utils.Logger{Logger: log}
+	config.Set(&config.Config{
+		Server: config.ServerConfig{Environment: "test"},
+		JWT:    config.JWTConfig{Secret: "test-secret", Expired: 3600, RefreshExpired: 86400},
+	})
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", name)), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(
+		&models.User{}, &models.UserIdentity{}, &models.OIDCState{},
+		&models.RefreshToken{}, &models.RevokedToken{}, &models.UserTOTP{}, &models.RecoveryCode{}, &models.LoginChallenge{},
+		&models.LoginFailure{}, &models.AuditLog{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	database.DB = db
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"errors"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils/res"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagService struct{}
+
+// List 查询当前用户可见的提示词使用的标签及提示词数量，不可见提示词上的标签不会出现
+func (s *TagService) List(user models.JwtUser, dto *dto.ListTagDto) ([]vo.TagVO, error) {
+	db := database.GetDB()
+
+	query := db.Model(&models.Tag{}).
+		Select("tags.id, tags.name, COUNT(prompt_tags.prompt_id) AS prompt_count").
+		Joins("JOIN prompt_tags ON prompt_tags.tag_id = tags.id").
+		Joins("JOIN prompts ON prompts.id = prompt_tags.prompt_id AND prompts.deleted_at IS NULL").
+		Scopes(readablePrompts(user)).
+		Group("tags.id, tags.name").
+		Order("prompt_count DESC, tags.name")
+	if dto.Keyword != "" {
+		query = query.Where("tags.name ILIKE ?", "%"+dto.Keyword+"%")
+	}
+
+	list := make([]vo.TagVO, 0)
+	if err := query.Scan(&list).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询标签失败")
+	}
+	return list, nil
+}
+
+// Delete 删除标签并解除与提示词的关联，仅管理员可操作
+func (s *TagService) Delete(user models.JwtUser, id uint) error {
+	if user.Role != models.RoleAdmin {
+		return res.ErrForbidden.Msg("仅管理员可以删除标签")
+	}
+	db := database.GetDB()
+
+	var tag models.Tag
+	if err := db.First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return res.ErrTagNotFound
+		}
+		return res.ErrInternalServer.Msg("查询标签失败")
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM prompt_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&tag).Error
+	})
+	if err != nil {
+		return res.ErrInternalServer.Msg("删除标签失败")
+	}
+	return nil
+}
+
+// resolveTags 按名称查找标签，不存在的自动创建
+func resolveTags(tx *gorm.DB, names []string) ([]models.Tag, error) {
+	names = normalizeTagNames(names)
+	if len(names) == 0 {
+		return []models.Tag{}, nil
+	}
+
+	candidates := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		candidates = append(candidates, models.Tag{Name: name})
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	var tags []models.Tag
+	if err := tx.Where("name IN ?", names).Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// normalizeTagNames 去除空白并去重，保持原有顺序
+func normalizeTagNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
+}
+
+// splitTagNames 解析逗号分隔的标签查询参数
+func splitTagNames(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return normalizeTagNames(strings.Split(value, ","))
+}
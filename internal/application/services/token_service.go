@@ -0,0 +1,70 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"proomet/internal/domain/models"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils/jwt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// issueTokens 签发访问令牌与刷新令牌，familyID 为空时开启新的登录会话
+func issueTokens(db *gorm.DB, user *models.User, familyID string) (*vo.AuthLoginVO, error) {
+	if familyID == "" {
+		familyID = uuid.NewString()
+	}
+
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	record := models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: time.Now().Add(jwt.RefreshTokenTTL()),
+	}
+	if err := db.Create(&record).Error; err != nil {
+		return nil, err
+	}
+
+	accessToken, err := jwt.GenerateToken(user.ID, user.Username, user.Role, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vo.AuthLoginVO{
+		Token:            accessToken,
+		RefreshToken:     refreshToken,
+		ExpiresIn:        int64(jwt.AccessTokenTTL().Seconds()),
+		RefreshExpiresIn: int64(jwt.RefreshTokenTTL().Seconds()),
+	}, nil
+}
+
+// revokeTokenFamily 吊销令牌家族中尚未吊销的全部刷新令牌
+func revokeTokenFamily(db *gorm.DB, familyID string) error {
+	return db.Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// generateRefreshToken 生成随机的不透明刷新令牌
+func generateRefreshToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashRefreshToken 计算刷新令牌摘要，数据库中只保存摘要
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
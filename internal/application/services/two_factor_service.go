@@ -0,0 +1,307 @@
+package services
+
+import (
+	"encoding/base64"
+	"errors"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/auth"
+	"proomet/internal/infra/database"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils/jwt"
+	"proomet/pkg/utils/res"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// loginChallengeTTL 两步验证登录挑战有效期
+	loginChallengeTTL = 5 * time.Minute
+	// loginChallengeMaxAttempts 登录挑战允许的最大失败次数，超过后需要重新输入密码
+	loginChallengeMaxAttempts = 5
+)
+
+type TwoFactorService struct{}
+
+// Status 查询两步验证状态
+func (s *TwoFactorService) Status(claims *jwt.Claims) (*vo.TwoFactorStatusVO, error) {
+	if claims == nil || claims.UserID == 0 {
+		return nil, res.ErrUnauthorized
+	}
+	db := database.GetDB()
+
+	status := &vo.TwoFactorStatusVO{}
+	var totp models.UserTOTP
+	if err := db.Where("user_id = ? AND enabled_at IS NOT NULL", claims.UserID).First(&totp).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return status, nil
+		}
+		return nil, res.ErrInternalServer.Msg("查询两步验证状态失败")
+	}
+	status.Enabled = true
+	status.EnabledAt = totp.EnabledAt
+	if err := db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", claims.UserID).
+		Count(&status.RecoveryCodesRemaining).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询两步验证状态失败")
+	}
+	return status, nil
+}
+
+// Enroll 生成新的 TOTP 密钥，需要调用 Enable 校验验证码后才会生效；重复调用会替换未启用的密钥
+func (s *TwoFactorService) Enroll(claims *jwt.Claims) (*vo.TwoFactorEnrollVO, error) {
+	if claims == nil || claims.UserID == 0 {
+		return nil, res.ErrUnauthorized
+	}
+	db := database.GetDB()
+
+	user, err := findUser(claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing models.UserTOTP
+	err = db.Where("user_id = ?", user.ID).First(&existing).Error
+	if err == nil && existing.Enabled() {
+		return nil, res.ErrTwoFactorAlreadyEnabled
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, res.ErrInternalServer.Msg("查询两步验证状态失败")
+	}
+
+	account := user.Username
+	if user.Email != "" {
+		account = user.Email
+	}
+	enrollment, err := auth.GenerateTOTP(account)
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("生成两步验证密钥失败")
+	}
+
+	// 只替换未启用的密钥，并发启用时不会覆盖已生效的密钥
+	totp := models.UserTOTP{UserID: user.ID, Secret: enrollment.Secret}
+	result := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]any{"secret": enrollment.Secret, "last_used_step": 0, "updated_at": time.Now()}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "user_totps.enabled_at IS NULL"}}},
+	}).Create(&totp)
+	if result.Error != nil {
+		return nil, res.ErrInternalServer.Msg("保存两步验证密钥失败")
+	}
+	if result.RowsAffected == 0 {
+		return nil, res.ErrTwoFactorAlreadyEnabled
+	}
+
+	return &vo.TwoFactorEnrollVO{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.URI,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(enrollment.QRCode),
+	}, nil
+}
+
+// Enable 校验身份验证器中的验证码并启用两步验证，返回恢复码
+func (s *TwoFactorService) Enable(claims *jwt.Claims, dto *dto.TwoFactorCodeDto) (*vo.RecoveryCodesVO, error) {
+	if claims == nil || claims.UserID == 0 {
+		return nil, res.ErrUnauthorized
+	}
+	db := database.GetDB()
+
+	var (
+		codes  []string
+		reject error
+	)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var totp models.UserTOTP
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", claims.UserID).
+			First(&totp).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				reject = res.ErrTwoFactorNotEnabled.Msg("请先生成两步验证密钥")
+				return nil
+			}
+			return err
+		}
+		if totp.Enabled() {
+			reject = res.ErrTwoFactorAlreadyEnabled
+			return nil
+		}
+
+		step, ok := auth.ValidateTOTP(totp.Secret, dto.Code, totp.LastUsedStep)
+		if !ok {
+			reject = res.ErrTwoFactorCodeInvalid
+			return nil
+		}
+		if err := tx.Model(&totp).Updates(map[string]any{
+			"enabled_at":     time.Now(),
+			"last_used_step": step,
+		}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = replaceRecoveryCodes(tx, claims.UserID)
+		return err
+	})
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("启用两步验证失败")
+	}
+	if reject != nil {
+		return nil, reject
+	}
+	return &vo.RecoveryCodesVO{RecoveryCodes: codes}, nil
+}
+
+// Disable 校验验证码或恢复码后关闭两步验证
+func (s *TwoFactorService) Disable(claims *jwt.Claims, dto *dto.TwoFactorCodeDto) error {
+	if claims == nil || claims.UserID == 0 {
+		return res.ErrUnauthorized
+	}
+	db := database.GetDB()
+
+	var reject error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		ok, err := verifySecondFactor(tx, claims.UserID, dto.Code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			reject = res.ErrTwoFactorCodeInvalid
+			return nil
+		}
+		if err := tx.Where("user_id = ?", claims.UserID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", claims.UserID).Delete(&models.UserTOTP{}).Error
+	})
+	if errors.Is(err, res.ErrTwoFactorNotEnabled) {
+		return res.ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return res.ErrInternalServer.Msg("关闭两步验证失败")
+	}
+	return reject
+}
+
+// RegenerateRecoveryCodes 校验验证码后重新生成恢复码，旧恢复码全部失效
+func (s *TwoFactorService) RegenerateRecoveryCodes(claims *jwt.Claims, dto *dto.TwoFactorCodeDto) (*vo.RecoveryCodesVO, error) {
+	if claims == nil || claims.UserID == 0 {
+		return nil, res.ErrUnauthorized
+	}
+	db := database.GetDB()
+
+	var (
+		codes  []string
+		reject error
+	)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		ok, err := verifySecondFactor(tx, claims.UserID, dto.Code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			reject = res.ErrTwoFactorCodeInvalid
+			return nil
+		}
+		codes, err = replaceRecoveryCodes(tx, claims.UserID)
+		return err
+	})
+	if errors.Is(err, res.ErrTwoFactorNotEnabled) {
+		return nil, res.ErrTwoFactorNotEnabled
+	}
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("生成恢复码失败")
+	}
+	if reject != nil {
+		return nil, reject
+	}
+	return &vo.RecoveryCodesVO{RecoveryCodes: codes}, nil
+}
+
+// completeLogin 第一步认证通过后完成登录：启用两步验证的账号签发登录挑战，否则直接签发令牌
+func completeLogin(db *gorm.DB, user *models.User) (*vo.AuthLoginVO, error) {
+	var count int64
+	if err := db.Model(&models.UserTOTP{}).
+		Where("user_id = ? AND enabled_at IS NOT NULL", user.ID).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return issueTokens(db, user, "")
+	}
+
+	// 同一用户只保留最新的登录挑战，避免并行使用多个挑战绕过失败次数限制；顺带清理过期的登录挑战
+	if err := db.Where("user_id = ? OR expires_at < ?", user.ID, time.Now()).Delete(&models.LoginChallenge{}).Error; err != nil {
+		return nil, err
+	}
+	token, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	challenge := models.LoginChallenge{
+		UserID:    user.ID,
+		TokenHash: hashRefreshToken(token),
+		ExpiresAt: time.Now().Add(loginChallengeTTL),
+	}
+	if err := db.Create(&challenge).Error; err != nil {
+		return nil, err
+	}
+	return &vo.AuthLoginVO{
+		TwoFactorRequired:  true,
+		ChallengeToken:     token,
+		ChallengeExpiresIn: int64(loginChallengeTTL.Seconds()),
+	}, nil
+}
+
+// verifySecondFactor 校验 TOTP 验证码或恢复码，通过后记录时间步或标记恢复码已使用
+// 未启用两步验证时返回 res.ErrTwoFactorNotEnabled
+func verifySecondFactor(tx *gorm.DB, userID uint, code string) (bool, error) {
+	var totp models.UserTOTP
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND enabled_at IS NOT NULL", userID).
+		First(&totp).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, res.ErrTwoFactorNotEnabled
+		}
+		return false, err
+	}
+
+	if step, ok := auth.ValidateTOTP(totp.Secret, code, totp.LastUsedStep); ok {
+		return true, tx.Model(&totp).Update("last_used_step", step).Error
+	}
+
+	normalized := auth.NormalizeRecoveryCode(code)
+	if normalized == "" {
+		return false, nil
+	}
+	result := tx.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashRefreshToken(normalized)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// replaceRecoveryCodes 生成新的恢复码并替换旧恢复码，数据库中只保存摘要
+func replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	codes, err := auth.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+	records := make([]models.RecoveryCode, 0, len(codes))
+	for _, code := range codes {
+		records = append(records, models.RecoveryCode{
+			UserID:   userID,
+			CodeHash: hashRefreshToken(auth.NormalizeRecoveryCode(code)),
+		})
+	}
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"proomet/config"
+	"proomet/internal/domain/models"
+	"proomet/internal/interfaces/dto"
+	"proomet/pkg/utils/res"
+	"testing"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"gorm.io/gorm"
+)
+
+// twoFactorEnv 两步验证登录测试环境，用户 alice 已启用两步验证
+type twoFactorEnv struct {
+	db       *gorm.DB
+	user     *models.User
+	secret   string
+	recovery []string
+}
+
+// setupTwoFactor 创建已启用两步验证的用户与恢复码
+func setupTwoFactor(t *testing.T) *twoFactorEnv {
+	t.Helper()
+	db := setupTestDB(t)
+	user := createLoginUser(t, db, "alice", "correct-password")
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "proomet", AccountName: user.Username})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := db.Create(&models.UserTOTP{UserID: user.ID, Secret: key.Secret(), EnabledAt: &now}).Error; err != nil {
+		t.Fatal(err)
+	}
+	recovery, err := replaceRecoveryCodes(db, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &twoFactorEnv{db: db, user: user, secret: key.Secret(), recovery: recovery}
+}
+
+// login 密码登录，返回登录挑战令牌
+func (e *twoFactorEnv) login(t *testing.T) string {
+	t.Helper()
+	tokens, err := (&AuthService{}).LoginWithPwd(&dto.LoginWithPwdDto{Account: "alice", Password: "correct-password"}, "10.0.0.1")
+	if err != nil {
+		t.Fatalf("密码登录失败: %v", err)
+	}
+	if !tokens.TwoFactorRequired || tokens.ChallengeToken == "" {
+		t.Fatal("启用两步验证的账号应返回登录挑战")
+	}
+	return tokens.ChallengeToken
+}
+
+// verify 使用登录挑战与验证码完成登录
+func (e *twoFactorEnv) verify(challenge, code string) error {
+	_, err := (&AuthService{}).LoginTwoFactor(&dto.LoginTwoFactorDto{ChallengeToken: challenge, Code: code}, "10.0.0.1")
+	return err
+}
+
+// passwordLogin 使用正确的密码登录，返回登录结果
+func (e *twoFactorEnv) passwordLogin() error {
+	_, err := (&AuthService{}).LoginWithPwd(&dto.LoginWithPwdDto{Account: "alice", Password: "correct-password"}, "10.0.0.1")
+	return err
+}
+
+// enableLockout 启用登录锁定，账号连续失败 3 次后锁定
+func (e *twoFactorEnv) enableLockout() {
+	cfg := *config.Get()
+	cfg.Lockout = config.LockoutConfig{AccountMaxFailures: 3, IPMaxFailures: 20, Window: 900, BaseDuration: 60, MaxDuration: 3600}
+	config.Set(&cfg)
+}
+
+// code 当前有效的验证码
+func (e *twoFactorEnv) code(t *testing.T) string {
+	t.Helper()
+	code, err := totp.GenerateCode(e.secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// wrongCode 与当前验证码不同的验证码
+func (e *twoFactorEnv) wrongCode(t *testing.T) string {
+	if e.code(t) == "000000" {
+		return "111111"
+	}
+	return "000000"
+}
+
+// attempts 挑战记录的失败次数，挑战不存在时返回 -1
+func (e *twoFactorEnv) attempts(t *testing.T, challenge string) int {
+	t.Helper()
+	var record models.LoginChallenge
+	if err := e.db.Where("token_hash = ?", hashRefreshToken(challenge)).Limit(1).Find(&record).Error; err != nil {
+		t.Fatal(err)
+	}
+	if record.ID == 0 {
+		return -1
+	}
+	return record.Attempts
+}
+
+func TestLoginTwoFactor(t *testing.T) {
+	tests := []struct {
+		name string
+		// run 执行两步验证登录，返回最后一次验证的结果
+		run  func(t *testing.T, env *twoFactorEnv) error
+		want *res.BusinessError // nil 表示登录成功
+	}{
+		{
+			name: "验证码正确",
+			run: func(t *testing.T, env *twoFactorEnv) error {
+				return env.verify(env.login(t), env.code(t))
+			},
+		},
+		{
+			name: "验证码错误时累计失败次数",
+			run: func(t *testing.T, env *twoFactorEnv) error {
+				challenge := env.login(t)
+				err := env.verify(challenge, env.wrongCode(t))
+				if got := env.attempts(t, challenge); got != 1 {
+					t.Fatalf("失败次数为 %d，期望 1", got)
+				}
+				return err
+			},
+			want: res.ErrTwoFactorCodeInvalid,
+		},
+		{
+			name: "连续失败 5 次后挑战失效",
+			run: func(t *testing.T, env *twoFactorEnv) error {
+				challenge := env.login(t)
+				for i := 1; i < loginChallengeMaxAttempts; i++ {
+					assertBusinessError(t, env.verify(challenge, env.wrongCode(t)), res.ErrTwoFactorCodeInvalid)
+				}
+				assertBusinessError(t, env.verify(challenge, env.wrongCode(t)), res.ErrTwoFactorChallengeInvalid)
+				if got := env.attempts(t, challenge); got != -1 {
+					t.Fatal("挑战应已删除")
+				}
+				// 之后即使验证码正确也无法使用该挑战
+				return env.verify(challenge, env.code(t))
+			},
+			want: res.ErrTwoFactorChallengeInvalid,
+		},
+		{
+			name: "恢复码只能使用一次",
+			run: func(t *testing.T, env *twoFactorEnv) error {
+				if err := env.verify(env.login(t), env.recovery[0]); err != nil {
+					t.Fatalf("首次使用恢复码失败: %v", err)
+				}
+				return env.verify(env.login(t), env.recovery[0])
+			},
+			want: res.ErrTwoFactorCodeInvalid,
+		},
+		{
+			name: "挑战过期",
+			run: func(t *testing.T, env *twoFactorEnv) error {
+				challenge := env.login(t)
+				env.db.Model(&models.LoginChallenge{}).Where("1 = 1").Update("expires_at", time.Now().Add(-time.Second))
+				return env.verify(challenge, env.code(t))
+			},
+			want: res.ErrTwoFactorChallengeInvalid,
+		},
+		{
+			name: "挑战成功使用后失效",
+			run: func(t *testing.T, env *twoFactorEnv) error {
+				challenge := env.login(t)
+				if err := env.verify(challenge, env.recovery[0]); err != nil {
+					t.Fatal(err)
+				}
+				return env.verify(challenge, env.recovery[1])
+			},
+			want: res.ErrTwoFactorChallengeInvalid,
+		},
+		{
+			name: "重新登录后旧挑战失效",
+			run: func(t *testing.T, env *twoFactorEnv) error {
+				old := env.login(t)
+				env.login(t)
+				return env.verify(old, env.code(t))
+			},
+			want: res.ErrTwoFactorChallengeInvalid,
+		},
+		{
+			name: "验证失败计入账号锁定",
+			run: func(t *testing.T, env *twoFactorEnv) error {
+				env.enableLockout()
+				challenge := env.login(t)
+				for range 3 {
+					assertBusinessError(t, env.verify(challenge, env.wrongCode(t)), res.ErrTwoFactorCodeInvalid)
+				}
+				assertBusinessError(t, env.passwordLogin(), res.ErrLoginLocked)
+				return env.verify(challenge, env.code(t))
+			},
+			want: res.ErrLoginLocked,
+		},
+		{
+			name: "密码正确不清除验证失败次数",
+			run: func(t *testing.T, env *twoFactorEnv) error {
+				env.enableLockout()
+				challenge := env.login(t)
+				for range 2 {
+					assertBusinessError(t, env.verify(challenge, env.wrongCode(t)), res.ErrTwoFactorCodeInvalid)
+				}
+				assertBusinessError(t, env.verify(env.login(t), env.wrongCode(t)), res.ErrTwoFactorCodeInvalid)
+				return env.passwordLogin()
+			},
+			want: res.ErrLoginLocked,
+		},
+		{
+			name: "验证通过后清除失败次数",
+			run: func(t *testing.T, env *twoFactorEnv) error {
+				env.enableLockout()
+				challenge := env.login(t)
+				for range 2 {
+					assertBusinessError(t, env.verify(challenge, env.wrongCode(t)), res.ErrTwoFactorCodeInvalid)
+				}
+				if err := env.verify(challenge, env.code(t)); err != nil {
+					t.Fatal(err)
+				}
+				challenge = env.login(t)
+				for range 2 {
+					assertBusinessError(t, env.verify(challenge, env.wrongCode(t)), res.ErrTwoFactorCodeInvalid)
+				}
+				return env.passwordLogin()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := setupTwoFactor(t)
+			err := tt.run(t, env)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("期望登录成功，实际为 %v", err)
+				}
+				return
+			}
+			assertBusinessError(t, err, tt.want)
+		})
+	}
+}
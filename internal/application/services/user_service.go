@@ -0,0 +1,300 @@
+package services
+
+import (
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/auth"
+	"proomet/internal/infra/database"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils"
+	"proomet/pkg/utils/converter"
+	"proomet/pkg/utils/jwt"
+	"proomet/pkg/utils/res"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type UserService struct{}
+
+// Me 获取当前用户信息
+func (s *UserService) Me(user models.JwtUser) (*vo.UserVO, error) {
+	if user.UserID == 0 {
+		return nil, res.ErrUnauthorized
+	}
+	account, err := findUser(user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return toUserVO(account), nil
+}
+
+// UpdateMe 更新个人资料，修改邮箱后需要重新验证并发送验证邮件
+func (s *UserService) UpdateMe(user models.JwtUser, dto *dto.UpdateProfileDto) (*vo.UserVO, error) {
+	if user.UserID == 0 {
+		return nil, res.ErrUnauthorized
+	}
+	account, err := findUser(user.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	emailChanged, err := applyProfile(account, dto.Nickname, dto.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.GetDB().Save(account).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("更新用户失败")
+	}
+	if emailChanged && account.Email != "" {
+		if _, err := sendActionEmail(database.GetDB(), account, models.ActionVerifyEmail); err != nil {
+			utils.Log.Errorf("发送验证邮件失败，用户: %d，错误: %v", account.ID, err)
+		}
+	}
+	return toUserVO(account), nil
+}
+
+// ChangePassword 修改密码，成功后吊销当前会话以外的全部刷新令牌
+func (s *UserService) ChangePassword(claims *jwt.Claims, dto *dto.ChangePasswordDto) error {
+	if claims == nil || claims.UserID == 0 {
+		return res.ErrUnauthorized
+	}
+	account, err := findUser(claims.UserID)
+	if err != nil {
+		return err
+	}
+	if account.PasswordHash != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(dto.OldPassword)); err != nil {
+			return res.ErrInvalidPassword.Msg("旧密码错误")
+		}
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(dto.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return res.ErrInternalServer.Msg("密码加密失败")
+	}
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(account).Update("password_hash", string(hashedPassword)).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND family_id <> ? AND revoked_at IS NULL", account.ID, claims.SessionID).
+			Update("revoked_at", time.Now()).Error
+	})
+	if err != nil {
+		return res.ErrInternalServer.Msg("修改密码失败")
+	}
+	return nil
+}
+
+// List 分页查询用户
+func (s *UserService) List(dto *dto.ListUserDto) (*vo.PageVO[vo.UserVO], error) {
+	db := database.GetDB()
+
+	page := utils.DefaultInt(dto.Page, 1)
+	pageSize := utils.DefaultInt(dto.PageSize, 20)
+
+	query := db.Model(&models.User{})
+	if dto.Keyword != "" {
+		like := "%" + dto.Keyword + "%"
+		query = query.Where("username ILIKE ? OR nickname ILIKE ? OR email ILIKE ?", like, like, like)
+	}
+	if dto.Role != "" {
+		query = query.Where("role = ?", dto.Role)
+	}
+	if dto.Status != 0 {
+		query = query.Where("status = ?", dto.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询用户失败")
+	}
+
+	var users []models.User
+	if err := query.Order("id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&users).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询用户失败")
+	}
+
+	list := make([]vo.UserVO, 0, len(users))
+	for i := range users {
+		list = append(list, *toUserVO(&users[i]))
+	}
+
+	return &vo.PageVO[vo.UserVO]{
+		List:     list,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// Get 获取用户详情
+func (s *UserService) Get(id uint) (*vo.UserVO, error) {
+	account, err := findUser(id)
+	if err != nil {
+		return nil, err
+	}
+	return toUserVO(account), nil
+}
+
+// Update 更新用户信息，只有全局管理员可以修改角色，且不能修改自己的角色
+func (s *UserService) Update(user models.JwtUser, id uint, dto *dto.UpdateUserDto) (*vo.UserVO, error) {
+	account, err := findUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if account.Role == models.RoleAdmin && user.Role != models.RoleAdmin {
+		return nil, res.ErrForbidden.Msg("仅管理员可以操作管理员账号")
+	}
+
+	if _, err := applyProfile(account, dto.Nickname, dto.Email); err != nil {
+		return nil, err
+	}
+	if dto.Role != nil && *dto.Role != account.Role {
+		if err := checkChangeRole(user, account); err != nil {
+			return nil, err
+		}
+		account.Role = *dto.Role
+	}
+
+	if err := database.GetDB().Save(account).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("更新用户失败")
+	}
+	return toUserVO(account), nil
+}
+
+// Disable 禁用用户并吊销其全部刷新令牌，已签发的访问令牌与 API Key 随即失效
+func (s *UserService) Disable(user models.JwtUser, id uint) error {
+	return s.setStatus(user, id, models.UserStatusDisabled)
+}
+
+// Enable 启用用户
+func (s *UserService) Enable(user models.JwtUser, id uint) error {
+	return s.setStatus(user, id, models.UserStatusActive)
+}
+
+// Delete 删除用户，同时移除其登录会话、API Key、第三方身份、工作区成员关系与权限授予
+func (s *UserService) Delete(user models.JwtUser, id uint) error {
+	account, err := findUser(id)
+	if err != nil {
+		return err
+	}
+	if err := checkManageUser(user, account); err != nil {
+		return err
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", account.ID).
+			Update("revoked_at", time.Now()).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", account.ID).Delete(&models.APIKey{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", account.ID).Delete(&models.UserIdentity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", account.ID).Delete(&models.WorkspaceMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(account).Error; err != nil {
+			return err
+		}
+		return auth.RemoveUser(account.ID)
+	})
+	if err != nil {
+		return res.ErrInternalServer.Msg("删除用户失败")
+	}
+	return nil
+}
+
+// setStatus 修改用户状态，禁用时吊销全部刷新令牌
+func (s *UserService) setStatus(user models.JwtUser, id uint, status int) error {
+	account, err := findUser(id)
+	if err != nil {
+		return err
+	}
+	if err := checkManageUser(user, account); err != nil {
+		return err
+	}
+	if account.Status == status {
+		return nil
+	}
+
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(account).Update("status", status).Error; err != nil {
+			return err
+		}
+		if status != models.UserStatusDisabled {
+			return nil
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", account.ID).
+			Update("revoked_at", time.Now()).Error
+	})
+	if err != nil {
+		return res.ErrInternalServer.Msg("更新用户状态失败")
+	}
+	return nil
+}
+
+// checkManageUser 禁用、删除用户的前置校验：不能操作自己，只有全局管理员可以操作其他管理员
+func checkManageUser(user models.JwtUser, account *models.User) error {
+	if account.ID == user.UserID {
+		return res.ErrForbidden.Msg("不能对自己执行该操作")
+	}
+	if account.Role == models.RoleAdmin && user.Role != models.RoleAdmin {
+		return res.ErrForbidden.Msg("仅管理员可以操作管理员账号")
+	}
+	return nil
+}
+
+// checkChangeRole 修改全局角色的前置校验：只有管理员可以修改角色，且不能修改自己的角色，
+// 执行操作的管理员始终保留，不会出现没有管理员的情况
+func checkChangeRole(user models.JwtUser, account *models.User) error {
+	if user.Role != models.RoleAdmin {
+		return res.ErrForbidden.Msg("仅管理员可以修改用户角色")
+	}
+	if account.ID == user.UserID {
+		return res.ErrForbidden.Msg("不能修改自己的角色")
+	}
+	return nil
+}
+
+// applyProfile 修改昵称与邮箱，邮箱变更时校验唯一性并重置验证状态，返回邮箱是否变更
+func applyProfile(account *models.User, nickname, email *string) (bool, error) {
+	if nickname != nil {
+		account.Nickname = *nickname
+	}
+	if email == nil || strings.EqualFold(*email, account.Email) {
+		return false, nil
+	}
+
+	normalized := strings.ToLower(*email)
+	var count int64
+	if err := database.GetDB().Model(&models.User{}).
+		Where("LOWER(email) = ? AND id <> ?", normalized, account.ID).
+		Count(&count).Error; err != nil {
+		return false, res.ErrInternalServer.Msg("查询用户失败")
+	}
+	if count > 0 {
+		return false, res.ErrEmailAlreadyUsed
+	}
+	account.Email = normalized
+	account.EmailVerifiedAt = nil
+	return true, nil
+}
+
+// toUserVO 模型转换为VO
+func toUserVO(user *models.User) *vo.UserVO {
+	var userVO vo.UserVO
+	converter.SafeConvert(&userVO, user)
+	return &userVO
+}
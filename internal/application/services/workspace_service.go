@@ -0,0 +1,381 @@
+package services
+
+import (
+	"errors"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/auth"
+	"proomet/internal/infra/database"
+	"proomet/internal/interfaces/dto"
+	"proomet/internal/interfaces/vo"
+	"proomet/pkg/utils"
+	"proomet/pkg/utils/converter"
+	"proomet/pkg/utils/res"
+
+	"gorm.io/gorm"
+)
+
+type WorkspaceService struct{}
+
+// Create 创建工作区，创建者成为所有者
+func (s *WorkspaceService) Create(user models.JwtUser, dto *dto.CreateWorkspaceDto) (*vo.WorkspaceVO, error) {
+	if user.UserID == 0 {
+		return nil, res.ErrUnauthorized
+	}
+	db := database.GetDB()
+
+	var count int64
+	if err := db.Model(&models.Workspace{}).Unscoped().Where("slug = ?", dto.Slug).Count(&count).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询工作区失败")
+	}
+	if count > 0 {
+		return nil, res.ErrWorkspaceSlugTaken
+	}
+
+	workspace := models.Workspace{
+		Name:        dto.Name,
+		Slug:        dto.Slug,
+		Description: dto.Description,
+		OwnerID:     user.UserID,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&workspace).Error; err != nil {
+			return err
+		}
+		member := models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: user.UserID, Role: models.WorkspaceRoleOwner}
+		if err := tx.Create(&member).Error; err != nil {
+			return err
+		}
+		// Casbin 的写入不参与事务：授予放在事务最后，授予失败时回滚工作区，提交失败时再撤销授予
+		return auth.AssignWorkspaceRole(user.UserID, workspace.ID, models.WorkspaceRoleOwner)
+	})
+	if err != nil {
+		if workspace.ID != 0 {
+			undoGrants(func() error { return auth.RemoveWorkspaceDomain(workspace.ID) })
+		}
+		return nil, res.ErrInternalServer.Msg("创建工作区失败")
+	}
+	return toWorkspaceVO(&workspace, models.WorkspaceRoleOwner), nil
+}
+
+// List 查询当前用户加入的工作区
+func (s *WorkspaceService) List(user models.JwtUser) ([]vo.WorkspaceVO, error) {
+	db := database.GetDB()
+
+	var rows []struct {
+		models.Workspace
+		MemberRole string
+	}
+	if err := db.Model(&models.Workspace{}).
+		Select("workspaces.*, workspace_members.role AS member_role").
+		Joins("JOIN workspace_members ON workspace_members.workspace_id = workspaces.id").
+		Where("workspace_members.user_id = ?", user.UserID).
+		Order("workspaces.name").
+		Scan(&rows).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询工作区失败")
+	}
+
+	list := make([]vo.WorkspaceVO, 0, len(rows))
+	for i := range rows {
+		list = append(list, *toWorkspaceVO(&rows[i].Workspace, rows[i].MemberRole))
+	}
+	return list, nil
+}
+
+// Get 获取工作区详情，仅成员可见
+func (s *WorkspaceService) Get(user models.JwtUser, id uint) (*vo.WorkspaceVO, error) {
+	workspace, role, err := findWorkspaceFor(user, id, models.WorkspaceRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+	return toWorkspaceVO(workspace, role), nil
+}
+
+// Update 更新工作区名称与描述，需要管理员及以上角色
+func (s *WorkspaceService) Update(user models.JwtUser, id uint, dto *dto.UpdateWorkspaceDto) (*vo.WorkspaceVO, error) {
+	workspace, role, err := findWorkspaceFor(user, id, models.WorkspaceRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if dto.Name != nil {
+		workspace.Name = *dto.Name
+	}
+	if dto.Description != nil {
+		workspace.Description = *dto.Description
+	}
+	if err := database.GetDB().Save(workspace).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("更新工作区失败")
+	}
+	return toWorkspaceVO(workspace, role), nil
+}
+
+// Delete 删除工作区，仅所有者可操作；工作区内的提示词保留并归还给各自的作者
+func (s *WorkspaceService) Delete(user models.JwtUser, id uint) error {
+	workspace, _, err := findWorkspaceFor(user, id, models.WorkspaceRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	restore, err := auth.SnapshotWorkspaceDomain(workspace.ID)
+	if err != nil {
+		return res.ErrInternalServer.Msg("删除工作区失败")
+	}
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Prompt{}).
+			Where("workspace_id = ?", workspace.ID).
+			Update("workspace_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("workspace_id = ?", workspace.ID).Delete(&models.WorkspaceMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(workspace).Error; err != nil {
+			return err
+		}
+		return auth.RemoveWorkspaceDomain(workspace.ID)
+	})
+	if err != nil {
+		undoGrants(restore)
+		return res.ErrInternalServer.Msg("删除工作区失败")
+	}
+	return nil
+}
+
+// ListMembers 查询工作区成员
+func (s *WorkspaceService) ListMembers(user models.JwtUser, id uint) ([]vo.WorkspaceMemberVO, error) {
+	workspace, _, err := findWorkspaceFor(user, id, models.WorkspaceRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]vo.WorkspaceMemberVO, 0)
+	if err := database.GetDB().Model(&models.WorkspaceMember{}).
+		Select("workspace_members.user_id, users.username, users.nickname, workspace_members.role, workspace_members.created_at AS joined_at").
+		Joins("JOIN users ON users.id = workspace_members.user_id AND users.deleted_at IS NULL").
+		Where("workspace_members.workspace_id = ?", workspace.ID).
+		Order("workspace_members.created_at").
+		Scan(&list).Error; err != nil {
+		return nil, res.ErrInternalServer.Msg("查询工作区成员失败")
+	}
+	return list, nil
+}
+
+// AddMember 添加工作区成员，可通过用户ID或用户名指定
+func (s *WorkspaceService) AddMember(user models.JwtUser, id uint, dto *dto.AddWorkspaceMemberDto) (*vo.WorkspaceMemberVO, error) {
+	workspace, role, err := findWorkspaceFor(user, id, models.WorkspaceRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAssignableRole(user, role, dto.Role); err != nil {
+		return nil, err
+	}
+	db := database.GetDB()
+
+	var target models.User
+	query := db.Model(&models.User{})
+	if dto.UserID != 0 {
+		query = query.Where("id = ?", dto.UserID)
+	} else {
+		query = query.Where("username = ?", dto.Username)
+	}
+	if err := query.First(&target).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, res.ErrUserNotFound
+		}
+		return nil, res.ErrInternalServer.Msg("查询用户失败")
+	}
+
+	existing, err := workspaceRoleOf(workspace.ID, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != "" {
+		return nil, res.ErrWorkspaceMemberExist
+	}
+
+	restore, err := auth.SnapshotWorkspaceRoles(target.ID, workspace.ID)
+	if err != nil {
+		return nil, res.ErrInternalServer.Msg("添加工作区成员失败")
+	}
+	member := models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: target.ID, Role: dto.Role}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&member).Error; err != nil {
+			return err
+		}
+		return auth.AssignWorkspaceRole(target.ID, workspace.ID, dto.Role)
+	})
+	if err != nil {
+		undoGrants(restore)
+		return nil, res.ErrInternalServer.Msg("添加工作区成员失败")
+	}
+
+	return &vo.WorkspaceMemberVO{
+		UserID:   target.ID,
+		Username: target.Username,
+		Nickname: target.Nickname,
+		Role:     member.Role,
+		JoinedAt: member.CreatedAt,
+	}, nil
+}
+
+// UpdateMember 修改工作区成员角色，所有者的角色不可修改
+func (s *WorkspaceService) UpdateMember(user models.JwtUser, id, userID uint, dto *dto.UpdateWorkspaceMemberDto) error {
+	workspace, role, err := findWorkspaceFor(user, id, models.WorkspaceRoleAdmin)
+	if err != nil {
+		return err
+	}
+	member, err := findWorkspaceMember(workspace.ID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role == models.WorkspaceRoleOwner {
+		return res.ErrForbidden.Msg("不能修改所有者的角色")
+	}
+	if err := checkAssignableRole(user, role, member.Role); err != nil {
+		return err
+	}
+	if err := checkAssignableRole(user, role, dto.Role); err != nil {
+		return err
+	}
+
+	restore, err := auth.SnapshotWorkspaceRoles(userID, workspace.ID)
+	if err != nil {
+		return res.ErrInternalServer.Msg("修改成员角色失败")
+	}
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(member).Update("role", dto.Role).Error; err != nil {
+			return err
+		}
+		return auth.AssignWorkspaceRole(userID, workspace.ID, dto.Role)
+	})
+	if err != nil {
+		undoGrants(restore)
+		return res.ErrInternalServer.Msg("修改成员角色失败")
+	}
+	return nil
+}
+
+// RemoveMember 移除工作区成员，成员也可以主动退出；所有者不可移除
+func (s *WorkspaceService) RemoveMember(user models.JwtUser, id, userID uint) error {
+	required := models.WorkspaceRoleAdmin
+	if userID == user.UserID {
+		required = models.WorkspaceRoleViewer
+	}
+	workspace, role, err := findWorkspaceFor(user, id, required)
+	if err != nil {
+		return err
+	}
+	member, err := findWorkspaceMember(workspace.ID, userID)
+	if err != nil {
+		return err
+	}
+	if member.Role == models.WorkspaceRoleOwner {
+		return res.ErrForbidden.Msg("不能移除工作区所有者")
+	}
+	if userID != user.UserID {
+		if err := checkAssignableRole(user, role, member.Role); err != nil {
+			return err
+		}
+	}
+
+	restore, err := auth.SnapshotWorkspaceRoles(userID, workspace.ID)
+	if err != nil {
+		return res.ErrInternalServer.Msg("移除工作区成员失败")
+	}
+	err = database.GetDB().Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(member).Error; err != nil {
+			return err
+		}
+		return auth.RevokeWorkspaceRoles(userID, workspace.ID)
+	})
+	if err != nil {
+		undoGrants(restore)
+		return res.ErrInternalServer.Msg("移除工作区成员失败")
+	}
+	return nil
+}
+
+// undoGrants 数据库事务失败后撤销已写入的 Casbin 授予（gorm-adapter 不参与事务，不会随事务回滚）
+func undoGrants(restore func() error) {
+	if err := restore(); err != nil {
+		utils.Log.Errorf("撤销工作区授权失败: %v", err)
+	}
+}
+
+// findWorkspaceFor 查询工作区并校验当前用户的角色不低于 required，返回用户在工作区内的角色
+// 全局管理员视为工作区所有者；非成员统一返回工作区不存在，避免泄露工作区信息
+func findWorkspaceFor(user models.JwtUser, id uint, required string) (*models.Workspace, string, error) {
+	var workspace models.Workspace
+	if err := database.GetDB().First(&workspace, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", res.ErrWorkspaceNotFound
+		}
+		return nil, "", res.ErrInternalServer.Msg("查询工作区失败")
+	}
+
+	role, err := workspaceRoleOf(workspace.ID, user.UserID)
+	if err != nil {
+		return nil, "", err
+	}
+	if user.Role == models.RoleAdmin {
+		role = models.WorkspaceRoleOwner
+	}
+	if role == "" {
+		return nil, "", res.ErrWorkspaceNotFound
+	}
+	if !models.WorkspaceRoleAtLeast(role, required) {
+		return nil, "", res.ErrForbidden.Msg("工作区权限不足")
+	}
+	return &workspace, role, nil
+}
+
+// findWorkspaceMember 查询工作区成员
+func findWorkspaceMember(workspaceID, userID uint) (*models.WorkspaceMember, error) {
+	var member models.WorkspaceMember
+	if err := database.GetDB().
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, res.ErrNotWorkspaceMember
+		}
+		return nil, res.ErrInternalServer.Msg("查询工作区成员失败")
+	}
+	return &member, nil
+}
+
+// workspaceRoleOf 查询用户在工作区内的角色，非成员返回空字符串
+func workspaceRoleOf(workspaceID, userID uint) (string, error) {
+	if userID == 0 {
+		return "", nil
+	}
+	var roles []string
+	if err := database.GetDB().Model(&models.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		Limit(1).
+		Pluck("role", &roles).Error; err != nil {
+		return "", res.ErrInternalServer.Msg("查询工作区成员失败")
+	}
+	if len(roles) == 0 {
+		return "", nil
+	}
+	return roles[0], nil
+}
+
+// checkAssignableRole 校验操作者能否授予或变更目标角色：管理员只能管理编辑者与只读成员，所有者不受限制
+func checkAssignableRole(user models.JwtUser, operatorRole, targetRole string) error {
+	if user.Role == models.RoleAdmin || operatorRole == models.WorkspaceRoleOwner {
+		return nil
+	}
+	if models.WorkspaceRoleAtLeast(targetRole, models.WorkspaceRoleAdmin) {
+		return res.ErrForbidden.Msg("只有所有者可以管理管理员")
+	}
+	return nil
+}
+
+// toWorkspaceVO 模型转换为VO
+func toWorkspaceVO(workspace *models.Workspace, role string) *vo.WorkspaceVO {
+	var workspaceVO vo.WorkspaceVO
+	converter.SafeConvert(&workspaceVO, workspace)
+	workspaceVO.Role = role
+	return &workspaceVO
+}
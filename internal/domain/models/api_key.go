@@ -0,0 +1,62 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// API Key 权限范围常量，写权限包含同一资源的读权限
+const (
+	ScopePromptsRead      = "prompts:read"
+	ScopePromptsWrite     = "prompts:write"
+	ScopeCollectionsRead  = "collections:read"
+	ScopeCollectionsWrite = "collections:write"
+	ScopeTagsRead         = "tags:read"
+	ScopeWorkspacesRead   = "workspaces:read"
+)
+
+// APIKeyScopes 个人 API Key 可用的权限范围
+var APIKeyScopes = []string{
+	ScopePromptsRead, ScopePromptsWrite,
+	ScopeCollectionsRead, ScopeCollectionsWrite,
+	ScopeTagsRead, ScopeWorkspacesRead,
+}
+
+// WorkspaceAPIKeyScopes 工作区 API Key 可用的权限范围
+var WorkspaceAPIKeyScopes = []string{ScopePromptsRead, ScopePromptsWrite, ScopeTagsRead}
+
+// APIKeyPrefix API Key 的固定前缀，便于识别与密钥扫描
+const APIKeyPrefix = "pk_"
+
+// APIKey 用于程序化访问的密钥，仅保存摘要；Prefix 为密钥开头的可见部分，便于用户辨认
+// WorkspaceID 非空时为工作区密钥，只能访问该工作区内的资源
+type APIKey struct {
+	gorm.Model
+	Name        string     `gorm:"type:varchar(64);not null;comment:名称" json:"name"`
+	UserID      uint       `gorm:"not null;index;comment:创建者ID" json:"user_id"`
+	WorkspaceID *uint      `gorm:"index;comment:所属工作区ID" json:"workspace_id"`
+	Prefix      string     `gorm:"type:varchar(16);not null;index;comment:可见前缀" json:"prefix"`
+	KeyHash     string     `gorm:"type:char(64);uniqueIndex;not null;comment:密钥摘要" json:"-"`
+	Scopes      []string   `gorm:"type:jsonb;serializer:json;comment:权限范围" json:"scopes"`
+	ExpiresAt   *time.Time `gorm:"comment:过期时间" json:"expires_at"`
+	LastUsedAt  *time.Time `gorm:"comment:最近使用时间" json:"last_used_at"`
+}
+
+// Expired 是否已过期
+func (k *APIKey) Expired() bool {
+	return k.ExpiresAt != nil && time.Now().After(*k.ExpiresAt)
+}
+
+// HasScope 是否拥有指定权限范围，写权限包含读权限
+func (k *APIKey) HasScope(required string) bool {
+	if slices.Contains(k.Scopes, required) {
+		return true
+	}
+	if resource, found := strings.CutSuffix(required, ":read"); found {
+		return slices.Contains(k.Scopes, resource+":write")
+	}
+	return false
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// 附件状态常量
+const (
+	AttachmentStatusPending = "pending" // 已签发预签名上传 URL，等待客户端上传完成
+	AttachmentStatusReady   = "ready"   // 上传完成并通过校验
+)
+
+// Attachment 提示词附件（参考图片、示例文档等），文件内容存储在对象存储中
+type Attachment struct {
+	ID          uint       `gorm:"primarykey" json:"id"`
+	PromptID    uint       `gorm:"not null;index;comment:提示词ID" json:"prompt_id"`
+	UploaderID  uint       `gorm:"not null;index;comment:上传者ID" json:"uploader_id"`
+	FileName    string     `gorm:"type:varchar(255);not null;comment:文件名" json:"file_name"`
+	ContentType string     `gorm:"type:varchar(128);not null;comment:MIME类型" json:"content_type"`
+	Size        int64      `gorm:"not null;comment:文件大小(字节)" json:"size"`
+	Bucket      string     `gorm:"type:varchar(64);not null;comment:存储桶" json:"bucket"`
+	ObjectKey   string     `gorm:"type:varchar(512);not null;uniqueIndex;comment:对象键" json:"object_key"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'ready';index;comment:状态(pending, ready)" json:"status"`
+	ExpiresAt   *time.Time `gorm:"comment:待上传记录的过期时间" json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// 审计事件类型
+const (
+	AuditLoginLockout = "auth.login_lockout" // 登录失败次数过多被锁定
+)
+
+// AuditLog 审计日志，UserID 为空表示事件与具体用户无关（如针对不存在账号或 IP 的锁定）
+type AuditLog struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    *uint     `gorm:"index;comment:相关用户ID" json:"user_id"`
+	Action    string    `gorm:"type:varchar(64);not null;index;comment:事件类型" json:"action"`
+	Target    string    `gorm:"type:varchar(255);comment:事件对象" json:"target"`
+	IP        string    `gorm:"type:varchar(64);comment:来源IP" json:"ip"`
+	Detail    string    `gorm:"type:text;comment:详情" json:"detail"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
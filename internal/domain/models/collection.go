@@ -0,0 +1,14 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Collection 提示词集合（文件夹），通过 ParentID 组成树形结构
+type Collection struct {
+	gorm.Model
+	Name        string `gorm:"type:varchar(64);not null;comment:名称" json:"name"`
+	Description string `gorm:"type:varchar(255);comment:描述" json:"description"`
+	ParentID    *uint  `gorm:"index;comment:父级集合ID" json:"parent_id"`
+	OwnerID     uint   `gorm:"not null;index;comment:所有者ID" json:"owner_id"`
+}
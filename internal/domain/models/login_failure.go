@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// LoginFailure 登录失败计数，Key 为 account:<账号> 或 ip:<地址>
+// Lockouts 为连续锁定次数，用于计算指数增长的锁定时长
+type LoginFailure struct {
+	Key         string     `gorm:"type:varchar(160);primarykey;comment:计数键" json:"key"`
+	Failures    int        `gorm:"not null;default:0;comment:窗口内失败次数" json:"failures"`
+	Lockouts    int        `gorm:"not null;default:0;comment:连续锁定次数" json:"lockouts"`
+	WindowStart time.Time  `gorm:"not null;comment:统计窗口开始时间" json:"window_start"`
+	LockedUntil *time.Time `gorm:"comment:锁定截止时间" json:"locked_until"`
+	UpdatedAt   time.Time  `gorm:"index" json:"updated_at"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 提示词类型常量
+const (
+	PromptTypeText = "text" // 纯文本
+	PromptTypeChat = "chat" // 多轮对话消息
+)
+
+// 对话消息角色常量
+const (
+	MessageRoleSystem    = "system"    // 系统指令
+	MessageRoleUser      = "user"      // 用户
+	MessageRoleAssistant = "assistant" // 助手
+	MessageRoleTool      = "tool"      // 工具结果
+)
+
+// 可见性常量
+const (
+	VisibilityPrivate = "private" // 仅所有者可见
+	VisibilityPublic  = "public"  // 所有人可见
+)
+
+// 变量类型常量
+const (
+	VariableTypeString  = "string"  // 字符串
+	VariableTypeNumber  = "number"  // 数字
+	VariableTypeInteger = "integer" // 整数
+	VariableTypeBoolean = "boolean" // 布尔
+)
+
+// Prompt 提示词模型
+type Prompt struct {
+	gorm.Model
+	Title          string           `gorm:"type:varchar(128);not null;index;comment:标题" json:"title"`
+	Type           string           `gorm:"type:varchar(20);not null;default:'text';index;comment:类型(text, chat)" json:"type"`
+	Messages       []PromptMessage  `gorm:"type:jsonb;serializer:json;comment:对话消息" json:"messages"`
+	Body           string           `gorm:"type:text;not null;comment:正文" json:"body"`
+	Description    string           `gorm:"type:varchar(512);comment:描述" json:"description"`
+	Variables      []PromptVariable `gorm:"type:jsonb;serializer:json;comment:变量定义" json:"variables"`
+	WorkspaceID    *uint            `gorm:"index;comment:所属工作区ID(为空表示个人提示词)" json:"workspace_id"`
+	CollectionID   *uint            `gorm:"index;comment:所属集合ID" json:"collection_id"`
+	Tags           []Tag            `gorm:"many2many:prompt_tags;" json:"tags"`
+	OwnerID        uint             `gorm:"not null;index;comment:所有者ID" json:"owner_id"`
+	Visibility     string           `gorm:"type:varchar(20);default:'private';index;comment:可见性(private, public)" json:"visibility"`
+	CurrentVersion int              `gorm:"not null;default:0;comment:当前版本号" json:"current_version"`
+}
+
+// PromptVariable 模板变量定义，对应正文中的 {{name}} 占位符
+type PromptVariable struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Default     any    `json:"default,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// PromptMessage 对话消息
+type PromptMessage struct {
+	Role    string `json:"role"`
+	Name    string `json:"name,omitempty"`
+	Content string `json:"content"`
+}
+
+// FlattenMessages 将对话消息拼接为纯文本，作为对话类提示词的正文用于展示、对比与检索
+func FlattenMessages(messages []PromptMessage) string {
+	parts := make([]string, 0, len(messages))
+	for _, message := range messages {
+		header := "[" + message.Role
+		if message.Name != "" {
+			header += ":" + message.Name
+		}
+		parts = append(parts, header+"]\n"+message.Content)
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrPromptVersionImmutable 版本记录不可修改
+var ErrPromptVersionImmutable = errors.New("提示词版本不可修改")
+
+// PromptVersion 提示词版本，创建后不可修改
+type PromptVersion struct {
+	ID          uint             `gorm:"primarykey" json:"id"`
+	PromptID    uint             `gorm:"not null;uniqueIndex:idx_prompt_version;comment:提示词ID" json:"prompt_id"`
+	Version     int              `gorm:"not null;uniqueIndex:idx_prompt_version;comment:版本号" json:"version"`
+	Title       string           `gorm:"type:varchar(128);not null;comment:标题" json:"title"`
+	Type        string           `gorm:"type:varchar(20);not null;default:'text';comment:类型(text, chat)" json:"type"`
+	Messages    []PromptMessage  `gorm:"type:jsonb;serializer:json;comment:对话消息" json:"messages"`
+	Body        string           `gorm:"type:text;not null;comment:正文" json:"body"`
+	Description string           `gorm:"type:varchar(512);comment:描述" json:"description"`
+	Variables   []PromptVariable `gorm:"type:jsonb;serializer:json;comment:变量定义" json:"variables"`
+	AuthorID    uint             `gorm:"not null;index;comment:作者ID" json:"author_id"`
+	Message     string           `gorm:"type:varchar(255);comment:变更说明" json:"message"`
+	ContentHash string           `gorm:"type:char(64);not null;index;comment:内容哈希(SHA-256)" json:"content_hash"`
+	CreatedAt   time.Time        `json:"created_at"`
+}
+
+// NewPromptVersion 根据提示词当前内容生成版本快照
+func NewPromptVersion(prompt *Prompt, version int, authorID uint, message string) *PromptVersion {
+	return &PromptVersion{
+		PromptID:    prompt.ID,
+		Version:     version,
+		Title:       prompt.Title,
+		Type:        prompt.Type,
+		Messages:    prompt.Messages,
+		Body:        prompt.Body,
+		Description: prompt.Description,
+		Variables:   prompt.Variables,
+		AuthorID:    authorID,
+		Message:     message,
+		ContentHash: prompt.ContentHash(),
+	}
+}
+
+// ContentHash 计算提示词内容哈希，用于判断内容是否发生变化
+func (p *Prompt) ContentHash() string {
+	h := sha256.New()
+	for _, part := range []string{p.Title, p.Type, p.Description, p.Body} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	messages, _ := json.Marshal(p.Messages)
+	h.Write(messages)
+	variables, _ := json.Marshal(p.Variables)
+	h.Write(variables)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// BeforeUpdate 禁止修改版本记录
+func (v *PromptVersion) BeforeUpdate(tx *gorm.DB) error {
+	return ErrPromptVersionImmutable
+}
+
+// BeforeDelete 禁止删除版本记录
+func (v *PromptVersion) BeforeDelete(tx *gorm.DB) error {
+	return ErrPromptVersionImmutable
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// SigningKey 访问令牌的非对称签名密钥（PEM 编码）
+// 新密钥在 ActivatesAt 之后才用于签发，此前已经通过 JWKS 发布，保证各实例与外部验证方有时间同步公钥；
+// 被新密钥替换后设置 ExpiresAt，到期前仍用于验证
+type SigningKey struct {
+	KID         string     `gorm:"column:kid;type:varchar(64);primarykey;comment:密钥ID" json:"kid"`
+	Algorithm   string     `gorm:"type:varchar(16);not null;index;comment:签名算法" json:"algorithm"`
+	PrivateKey  string     `gorm:"type:text;not null;comment:私钥(PKCS8 PEM)" json:"-"`
+	PublicKey   string     `gorm:"type:text;not null;comment:公钥(PKIX PEM)" json:"public_key"`
+	ActivatesAt time.Time  `gorm:"not null;comment:启用时间" json:"activates_at"`
+	ExpiresAt   *time.Time `gorm:"index;comment:停止验证时间" json:"expires_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
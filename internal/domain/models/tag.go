@@ -0,0 +1,12 @@
+package models
+
+import (
+	"time"
+)
+
+// Tag 标签，与提示词多对多关联（关联表 prompt_tags），名称全局唯一
+type Tag struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"type:varchar(32);not null;uniqueIndex;comment:名称" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// RefreshToken 刷新令牌，仅保存令牌的 SHA-256 摘要
+// 同一次登录经过轮换产生的令牌属于同一个家族（FamilyID），检测到旧令牌被重复使用时整个家族一起吊销
+type RefreshToken struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	FamilyID  string     `gorm:"type:varchar(36);not null;index;comment:令牌家族(登录会话)ID" json:"family_id"`
+	TokenHash string     `gorm:"type:char(64);uniqueIndex;not null;comment:令牌摘要" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null;comment:过期时间" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"comment:轮换时间" json:"used_at"`
+	RevokedAt *time.Time `gorm:"comment:吊销时间" json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RevokedToken 已吊销的访问令牌(jti)，记录保留到令牌原本的过期时间
+type RevokedToken struct {
+	JTI       string    `gorm:"type:varchar(36);primarykey;comment:令牌ID" json:"jti"`
+	ExpiresAt time.Time `gorm:"not null;index;comment:令牌过期时间" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 操作令牌类型
+const (
+	ActionVerifyEmail   = "verify_email"   // 邮箱验证
+	ActionResetPassword = "reset_password" // 重置密码
+)
+
+// ActionToken 已签发的一次性操作令牌，令牌本身是签名的 JWT，这里只记录 jti 用于保证只能使用一次
+// 同一用户同一类型的令牌重新签发时，旧令牌随即失效
+type ActionToken struct {
+	JTI       string     `gorm:"type:varchar(36);primarykey;comment:令牌ID" json:"jti"`
+	UserID    uint       `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	Purpose   string     `gorm:"type:varchar(32);not null;comment:操作类型" json:"purpose"`
+	ExpiresAt time.Time  `gorm:"not null;index;comment:过期时间" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"comment:使用时间" json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// UserTOTP 用户的 TOTP 身份验证器，EnabledAt 为空表示尚未完成绑定
+// LastUsedStep 记录最近一次通过校验的时间步，防止验证码被重放
+type UserTOTP struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	UserID       uint       `gorm:"uniqueIndex;not null;comment:用户ID" json:"user_id"`
+	Secret       string     `gorm:"type:varchar(64);not null;comment:TOTP密钥" json:"-"`
+	EnabledAt    *time.Time `gorm:"comment:启用时间" json:"enabled_at"`
+	LastUsedStep int64      `gorm:"not null;default:0;comment:最近使用的时间步" json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Enabled 是否已启用
+func (t *UserTOTP) Enabled() bool {
+	return t.EnabledAt != nil
+}
+
+// RecoveryCode 两步验证恢复码，仅保存摘要，每个恢复码只能使用一次
+type RecoveryCode struct {
+	ID        uint       `gorm:"primarykey" json:"id"`
+	UserID    uint       `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	CodeHash  string     `gorm:"type:char(64);not null;comment:恢复码摘要" json:"-"`
+	UsedAt    *time.Time `gorm:"comment:使用时间" json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// LoginChallenge 两步验证登录挑战，密码校验通过后签发，完成第二步验证后换取令牌
+type LoginChallenge struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	TokenHash string    `gorm:"type:char(64);uniqueIndex;not null;comment:挑战令牌摘要" json:"-"`
+	Attempts  int       `gorm:"not null;default:0;comment:失败次数" json:"attempts"`
+	ExpiresAt time.Time `gorm:"not null;index;comment:过期时间" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -13,6 +15,12 @@ const (
 	RoleGuest  = "guest"  // 访客
 )
 
+// 用户状态常量
+const (
+	UserStatusActive   = 1 // 正常
+	UserStatusDisabled = 2 // 禁用
+)
+
 // User 用户模型
 type User struct {
 	gorm.Model
@@ -22,10 +30,14 @@ type User struct {
 	Email        string `gorm:"type:varchar(128);index;comment:邮箱" json:"email"`
 	Role         string `gorm:"type:varchar(20);default:'member';index;comment:角色标识" json:"role"`
 	Status       int    `gorm:"type:smallint;default:1;comment:状态(1:正常, 2:禁用)" json:"status"`
+
+	EmailVerifiedAt *time.Time `gorm:"comment:邮箱验证时间" json:"email_verified_at"`
 }
 
+// JwtUser 当前请求的身份，WorkspaceID 非 0 时身份仅限于该工作区（工作区 API Key）
 type JwtUser struct {
-	UserID   uint   `json:"id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID      uint   `json:"id"`
+	Username    string `json:"username"`
+	Role        string `json:"role"`
+	WorkspaceID uint   `json:"workspace_id,omitempty"`
 }
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// UserIdentity 用户在外部身份提供方（OIDC）中的身份，同一提供方的同一主体只能关联一个用户
+type UserIdentity struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	UserID    uint      `gorm:"not null;index;comment:用户ID" json:"user_id"`
+	Provider  string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_identity_subject;comment:提供方" json:"provider"`
+	Subject   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_identity_subject;comment:提供方中的用户标识(sub)" json:"subject"`
+	Email     string    `gorm:"type:varchar(128);comment:提供方返回的邮箱" json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OIDCState OIDC 授权请求的临时状态，回调时一次性消费
+type OIDCState struct {
+	State        string    `gorm:"type:varchar(64);primarykey;comment:state 参数" json:"state"`
+	Provider     string    `gorm:"type:varchar(32);not null;comment:提供方" json:"provider"`
+	Nonce        string    `gorm:"type:varchar(64);not null;comment:ID Token nonce" json:"-"`
+	CodeVerifier string    `gorm:"type:varchar(128);not null;comment:PKCE code_verifier" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index;comment:过期时间" json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 指定表名，避免按默认规则生成 o_id_c_states
+func (OIDCState) TableName() string {
+	return "oidc_states"
+}
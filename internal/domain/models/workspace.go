@@ -0,0 +1,53 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 工作区角色常量
+const (
+	WorkspaceRoleOwner  = "owner"  // 所有者
+	WorkspaceRoleAdmin  = "admin"  // 管理员
+	WorkspaceRoleEditor = "editor" // 编辑者
+	WorkspaceRoleViewer = "viewer" // 只读成员
+)
+
+// workspaceRoleLevels 工作区角色权限等级，数值越大权限越高
+var workspaceRoleLevels = map[string]int{
+	WorkspaceRoleViewer: 1,
+	WorkspaceRoleEditor: 2,
+	WorkspaceRoleAdmin:  3,
+	WorkspaceRoleOwner:  4,
+}
+
+// Workspace 工作区（团队）
+type Workspace struct {
+	gorm.Model
+	Name        string `gorm:"type:varchar(64);not null;comment:名称" json:"name"`
+	Slug        string `gorm:"type:varchar(64);uniqueIndex;not null;comment:唯一标识" json:"slug"`
+	Description string `gorm:"type:varchar(255);comment:描述" json:"description"`
+	OwnerID     uint   `gorm:"not null;index;comment:创建者ID" json:"owner_id"`
+}
+
+// WorkspaceMember 工作区成员及其在该工作区内的角色
+type WorkspaceMember struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	WorkspaceID uint      `gorm:"not null;uniqueIndex:idx_workspace_member;comment:工作区ID" json:"workspace_id"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_workspace_member;index;comment:用户ID" json:"user_id"`
+	Role        string    `gorm:"type:varchar(20);not null;default:'viewer';comment:角色(owner, admin, editor, viewer)" json:"role"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WorkspaceRoleAtLeast 判断工作区角色是否不低于指定角色
+func WorkspaceRoleAtLeast(role, required string) bool {
+	return workspaceRoleLevels[role] >= workspaceRoleLevels[required] && workspaceRoleLevels[role] > 0
+}
+
+// IsWorkspaceRole 判断是否为合法的工作区角色
+func IsWorkspaceRole(role string) bool {
+	return slices.Contains([]string{WorkspaceRoleOwner, WorkspaceRoleAdmin, WorkspaceRoleEditor, WorkspaceRoleViewer}, role)
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// apiKeyAlphabet 密钥字符集（base62），避免出现需要转义的字符
+const apiKeyAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// apiKeyPrefixLen 可见前缀长度（含 pk_）
+const apiKeyPrefixLen = 11
+
+// apiKeyTouchInterval 最近使用时间的最小更新间隔，避免每次请求都写库
+const apiKeyTouchInterval = time.Minute
+
+// ErrAPIKeyInvalid API Key 不存在、已删除或已过期
+var ErrAPIKeyInvalid = errors.New("invalid api key")
+
+// GenerateAPIKey 生成新的 API Key，返回完整密钥及其可见前缀
+func GenerateAPIKey() (key, prefix string, err error) {
+	buf := make([]byte, 40)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	var sb strings.Builder
+	sb.WriteString(models.APIKeyPrefix)
+	for _, b := range buf {
+		// 256 = 62*4+8，取模偏差可以忽略
+		sb.WriteByte(apiKeyAlphabet[int(b)%len(apiKeyAlphabet)])
+	}
+	key = sb.String()
+	return key, key[:apiKeyPrefixLen], nil
+}
+
+// HashAPIKey 计算 API Key 摘要
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsAPIKey 判断凭证是否为 API Key 格式
+func IsAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, models.APIKeyPrefix)
+}
+
+// AuthenticateAPIKey 校验 API Key 并返回密钥及其所属用户
+func AuthenticateAPIKey(key string) (*models.APIKey, *models.User, error) {
+	db := database.GetDB()
+
+	var apiKey models.APIKey
+	if err := db.Where("key_hash = ?", HashAPIKey(key)).First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAPIKeyInvalid
+		}
+		return nil, nil, err
+	}
+	if apiKey.Expired() {
+		return nil, nil, ErrAPIKeyInvalid
+	}
+
+	var user models.User
+	if err := db.First(&user, apiKey.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrAPIKeyInvalid
+		}
+		return nil, nil, err
+	}
+
+	if now := time.Now(); apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyTouchInterval {
+		apiKey.LastUsedAt = &now
+		db.Model(&apiKey).UpdateColumn("last_used_at", now)
+	}
+	return &apiKey, &user, nil
+}
+
+// APIKeyIdentity 将 API Key 转换为请求身份
+// 工作区密钥的身份被限定在该工作区内，且不继承创建者的全局管理员权限
+func APIKeyIdentity(apiKey *models.APIKey, user *models.User) models.JwtUser {
+	identity := models.JwtUser{UserID: user.ID, Username: user.Username, Role: user.Role}
+	if apiKey.WorkspaceID != nil {
+		identity.WorkspaceID = *apiKey.WorkspaceID
+		if identity.Role == models.RoleAdmin {
+			identity.Role = models.RoleMember
+		}
+	}
+	return identity
+}
+
+// RequiredScope 计算请求所需的 API Key 权限范围：资源取路径的第一段，
+// GET/HEAD 以及渲染、导出类的 POST 请求只需读权限，其余需要写权限；不支持的资源返回空字符串
+func RequiredScope(method, path string) string {
+	path = strings.Trim(path, "/")
+	resource, _, _ := strings.Cut(path, "/")
+	switch resource {
+	case "prompts", "collections", "tags", "workspaces":
+	default:
+		return ""
+	}
+
+	action := "write"
+	if method == http.MethodGet || method == http.MethodHead ||
+		strings.HasSuffix(path, "/render") || strings.HasSuffix(path, "/export") {
+		action = "read"
+	}
+	return resource + ":" + action
+}
@@ -1,14 +1,20 @@
 package auth
 
 import (
+	"errors"
+	"fmt"
+	"proomet/internal/domain/models"
 	"proomet/pkg/utils"
+	"strings"
 
 	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/util"
 	gormadapter "github.com/casbin/gorm-adapter/v3"
 	"gorm.io/gorm"
 )
 
-var Enforcer *casbin.Enforcer
+// Enforcer 使用并发安全的 SyncedEnforcer，策略可在运行时通过管理接口修改
+var Enforcer *casbin.SyncedEnforcer
 
 func InitCasbin(db *gorm.DB) {
 	// 1. 初始化适配器（让 Casbin 使用现有的 Gorm 实例）
@@ -18,18 +24,157 @@ func InitCasbin(db *gorm.DB) {
 	}
 
 	// 2. 加载模型配置
-	Enforcer, err = casbin.NewEnforcer("config/rbac_model.conf", adapter)
+	Enforcer, err = casbin.NewSyncedEnforcer("config/rbac_model.conf", adapter)
 	if err != nil {
 		utils.Log.Fatalf("Casbin 初始化失败 %s", err)
 	}
 
-	// 3. 加载策略
+	// 3. 角色授予的域支持通配：域为 * 的授予（如角色继承）在所有工作区内生效
+	Enforcer.AddNamedDomainMatchingFunc("g", "KeyMatch", util.KeyMatch)
+
+	// 4. 加载策略
 	if err := Enforcer.LoadPolicy(); err != nil {
 		utils.Log.Fatalf("Casbin 加载策略失败 %s", err)
 	}
 	utils.Log.Info("Casbin 初始化成功")
 }
 
-func GetEnforcer() *casbin.Enforcer {
+func GetEnforcer() *casbin.SyncedEnforcer {
 	return Enforcer
 }
+
+// CheckPolicy 检查 Casbin 已初始化且加载了策略，策略为空时所有需要授权的请求都会被拒绝
+func CheckPolicy() error {
+	if Enforcer == nil {
+		return errors.New("Casbin 未初始化")
+	}
+	policies, err := Enforcer.GetPolicy()
+	if err != nil {
+		return err
+	}
+	if len(policies) == 0 {
+		return errors.New("没有加载任何权限策略，请执行 migrate up 写入默认策略")
+	}
+	return nil
+}
+
+// GlobalDomain 全局域：域为 * 的策略与角色授予在所有域生效
+const GlobalDomain = "*"
+
+// PersonalDomain 个人域：未指定工作区的请求使用该域，只在该域生效的策略不会作用到工作区内的请求
+const PersonalDomain = "personal"
+
+// UserSubject 用户在 Casbin 中的主体标识
+func UserSubject(userID uint) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// WorkspaceDomain 工作区在 Casbin 中的域标识
+func WorkspaceDomain(workspaceID uint) string {
+	return fmt.Sprintf("workspace:%d", workspaceID)
+}
+
+// WorkspaceRole 工作区角色在 Casbin 中的角色名，加前缀以避免与全局角色重名
+func WorkspaceRole(role string) string {
+	return "workspace:" + role
+}
+
+// IsWorkspaceScoped 是否为工作区域或工作区角色，这类授予由工作区成员关系维护
+func IsWorkspaceScoped(name string) bool {
+	return strings.HasPrefix(name, "workspace:")
+}
+
+// Enforce 在指定域内校验用户权限：先以用户主体校验（覆盖工作区角色及单独授权），再以全局角色校验
+func Enforce(user models.JwtUser, dom, obj, act string) (bool, error) {
+	ok, _, _, err := EnforceEx(user, dom, obj, act)
+	return ok, err
+}
+
+// EnforceEx 同 Enforce，额外返回命中的主体及策略，用于调试权限
+func EnforceEx(user models.JwtUser, dom, obj, act string) (bool, string, []string, error) {
+	if user.UserID != 0 {
+		sub := UserSubject(user.UserID)
+		ok, explain, err := Enforcer.EnforceEx(sub, dom, obj, act)
+		if err != nil || ok {
+			return ok, sub, explain, err
+		}
+	}
+	ok, explain, err := Enforcer.EnforceEx(user.Role, dom, obj, act)
+	return ok, user.Role, explain, err
+}
+
+// AssignWorkspaceRole 设置用户在工作区内的角色（覆盖原有角色）
+func AssignWorkspaceRole(userID, workspaceID uint, role string) error {
+	if err := RevokeWorkspaceRoles(userID, workspaceID); err != nil {
+		return err
+	}
+	_, err := Enforcer.AddGroupingPolicy(UserSubject(userID), WorkspaceRole(role), WorkspaceDomain(workspaceID))
+	return err
+}
+
+// RevokeWorkspaceRoles 移除用户在工作区内的全部角色
+func RevokeWorkspaceRoles(userID, workspaceID uint) error {
+	_, err := Enforcer.RemoveFilteredGroupingPolicy(0, UserSubject(userID), "", WorkspaceDomain(workspaceID))
+	return err
+}
+
+// RemoveWorkspaceDomain 移除工作区域内的全部角色授予与策略
+func RemoveWorkspaceDomain(workspaceID uint) error {
+	dom := WorkspaceDomain(workspaceID)
+	if _, err := Enforcer.RemoveFilteredGroupingPolicy(2, dom); err != nil {
+		return err
+	}
+	_, err := Enforcer.RemoveFilteredPolicy(1, dom)
+	return err
+}
+
+// SnapshotWorkspaceRoles 记录用户在工作区内的角色授予，返回的函数将授予恢复为记录时的状态
+// gorm-adapter 不参与调用方的数据库事务，事务失败时需要用它撤销已写入的授予
+func SnapshotWorkspaceRoles(userID, workspaceID uint) (func() error, error) {
+	sub, dom := UserSubject(userID), WorkspaceDomain(workspaceID)
+	grants, err := Enforcer.GetFilteredGroupingPolicy(0, sub, "", dom)
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		if err := RevokeWorkspaceRoles(userID, workspaceID); err != nil {
+			return err
+		}
+		return addGroupingPolicies(grants)
+	}, nil
+}
+
+// SnapshotWorkspaceDomain 记录工作区域内的全部角色授予与策略，返回的函数将其恢复为记录时的状态
+func SnapshotWorkspaceDomain(workspaceID uint) (func() error, error) {
+	dom := WorkspaceDomain(workspaceID)
+	grants, err := Enforcer.GetFilteredGroupingPolicy(2, dom)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := Enforcer.GetFilteredPolicy(1, dom)
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		if err := RemoveWorkspaceDomain(workspaceID); err != nil {
+			return err
+		}
+		if err := addGroupingPolicies(grants); err != nil {
+			return err
+		}
+		if len(policies) == 0 {
+			return nil
+		}
+		_, err := Enforcer.AddPolicies(policies)
+		return err
+	}, nil
+}
+
+// addGroupingPolicies 批量写入角色授予，为空时跳过
+func addGroupingPolicies(grants [][]string) error {
+	if len(grants) == 0 {
+		return nil
+	}
+	_, err := Enforcer.AddGroupingPolicies(grants)
+	return err
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"io"
+	"proomet/internal/domain/models"
+	"proomet/pkg/utils"
+	"slices"
+	"testing"
+
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/util"
+	"github.com/sirupsen/logrus"
+)
+
+// setupEnforcer 使用仓库中的模型与默认策略创建内存中的 Enforcer
+func setupEnforcer(t *testing.T) {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	utils.Log = &utils.Logger{Logger: logger}
+
+	enforcer, err := casbin.NewSyncedEnforcer("../../../config/rbac_model.conf")
+	if err != nil {
+		t.Fatalf("创建 Enforcer 失败: %v", err)
+	}
+	enforcer.AddNamedDomainMatchingFunc("g", "KeyMatch", util.KeyMatch)
+	Enforcer = enforcer
+	if _, err := SeedPolicies("../../../" + DefaultPolicyFile); err != nil {
+		t.Fatalf("写入默认策略失败: %v", err)
+	}
+}
+
+func TestEnforceWorkspaceDomain(t *testing.T) {
+	setupEnforcer(t)
+	for userID, role := range map[uint]string{1: "viewer", 2: "editor", 3: "admin", 4: "owner"} {
+		if err := AssignWorkspaceRole(userID, 10, role); err != nil {
+			t.Fatal(err)
+		}
+	}
+	member := func(id uint) models.JwtUser { return models.JwtUser{UserID: id, Role: models.RoleMember} }
+	ws := WorkspaceDomain(10)
+
+	tests := []struct {
+		name     string
+		user     models.JwtUser
+		dom      string
+		obj, act string
+		want     bool
+	}{
+		{"viewer 可以读取工作区提示词", member(1), ws, "/prompts/5", "GET", true},
+		{"viewer 不能修改工作区提示词", member(1), ws, "/prompts/5", "PUT", false},
+		{"viewer 不能删除工作区提示词", member(1), ws, "/prompts/5", "DELETE", false},
+		{"viewer 不能在工作区内创建提示词", member(1), ws, "/prompts", "POST", false},
+		{"viewer 不能添加成员", member(1), ws, "/workspaces/10/members", "POST", false},
+		{"viewer 可以退出工作区", member(1), ws, "/workspaces/10/members/1", "DELETE", true},
+		{"editor 可以修改工作区提示词", member(2), ws, "/prompts/5", "PUT", true},
+		{"editor 不能修改工作区", member(2), ws, "/workspaces/10", "PUT", false},
+		{"admin 可以修改工作区", member(3), ws, "/workspaces/10", "PUT", true},
+		{"admin 不能删除工作区", member(3), ws, "/workspaces/10", "DELETE", false},
+		{"owner 可以删除工作区", member(4), ws, "/workspaces/10", "DELETE", true},
+		{"非成员不能访问工作区", member(5), ws, "/workspaces/10", "GET", false},
+		{"非成员不能修改工作区提示词", member(5), ws, "/prompts/5", "PUT", false},
+		{"editor 在其他工作区不能修改提示词", member(2), WorkspaceDomain(11), "/prompts/5", "PUT", false},
+		{"成员可以修改个人提示词", member(1), PersonalDomain, "/prompts/5", "PUT", true},
+		{"成员可以创建工作区", member(5), PersonalDomain, "/workspaces", "POST", true},
+		{"访客不能修改个人提示词", models.JwtUser{Role: models.RoleGuest}, PersonalDomain, "/prompts/5", "PUT", false},
+		{"全局管理员可以修改工作区提示词", models.JwtUser{UserID: 6, Role: models.RoleAdmin}, ws, "/prompts/5", "PUT", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Enforce(tt.user, tt.dom, tt.obj, tt.act)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("Enforce(%s, %s, %s, %s) = %v，期望 %v", tt.user.Role, tt.dom, tt.obj, tt.act, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotWorkspaceRoles(t *testing.T) {
+	setupEnforcer(t)
+	if err := AssignWorkspaceRole(1, 10, "viewer"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		change func() error
+	}{
+		{name: "修改角色", change: func() error { return AssignWorkspaceRole(1, 10, "admin") }},
+		{name: "移除角色", change: func() error { return RevokeWorkspaceRoles(1, 10) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore, err := SnapshotWorkspaceRoles(1, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.change(); err != nil {
+				t.Fatal(err)
+			}
+			if err := restore(); err != nil {
+				t.Fatal(err)
+			}
+			assertGrants(t, [][]string{{UserSubject(1), WorkspaceRole("viewer"), WorkspaceDomain(10)}})
+		})
+	}
+}
+
+func TestSnapshotWorkspaceDomain(t *testing.T) {
+	setupEnforcer(t)
+	if err := AssignWorkspaceRole(1, 10, "owner"); err != nil {
+		t.Fatal(err)
+	}
+	if err := AssignWorkspaceRole(2, 10, "editor"); err != nil {
+		t.Fatal(err)
+	}
+
+	restore, err := SnapshotWorkspaceDomain(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RemoveWorkspaceDomain(10); err != nil {
+		t.Fatal(err)
+	}
+	assertGrants(t, nil)
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+	assertGrants(t, [][]string{
+		{UserSubject(1), WorkspaceRole("owner"), WorkspaceDomain(10)},
+		{UserSubject(2), WorkspaceRole("editor"), WorkspaceDomain(10)},
+	})
+}
+
+// assertGrants 校验 workspace:10 域内的角色授予
+func assertGrants(t *testing.T, want [][]string) {
+	t.Helper()
+	got, err := Enforcer.GetFilteredGroupingPolicy(2, WorkspaceDomain(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.SortFunc(got, slices.Compare)
+	if len(got) != len(want) {
+		t.Fatalf("期望授予 %v，实际为 %v", want, got)
+	}
+	for i := range want {
+		if !slices.Equal(got[i], want[i]) {
+			t.Fatalf("期望授予 %v，实际为 %v", want, got)
+		}
+	}
+}
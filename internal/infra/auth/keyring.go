@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"proomet/config"
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"proomet/pkg/utils"
+	"proomet/pkg/utils/jwt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// keyringReloadInterval 从数据库重新加载密钥环的间隔，多实例部署时据此同步其他实例轮换的密钥
+	keyringReloadInterval = time.Minute
+	// keyPropagationDelay 新密钥发布到启用之间的等待时间，需大于重新加载间隔
+	keyPropagationDelay = 2 * keyringReloadInterval
+	// keyringLockID 轮换密钥时使用的 PostgreSQL 事务级咨询锁
+	keyringLockID = 7071_0017
+)
+
+// InitKeyring 初始化访问令牌签名密钥环，HS256 使用共享密钥，无需密钥环
+func InitKeyring(cfg config.JWTConfig) {
+	if cfg.Algorithm == "" || cfg.Algorithm == jwt.AlgorithmHS256 {
+		jwt.SetKeys(nil, nil)
+		return
+	}
+	if cfg.Algorithm != jwt.AlgorithmRS256 && cfg.Algorithm != jwt.AlgorithmEdDSA {
+		utils.Log.Fatalf("不支持的 JWT 签名算法 %s", cfg.Algorithm)
+	}
+
+	if err := rotateKeys(cfg); err != nil {
+		utils.Log.Fatalf("JWT 密钥环初始化失败: %v", err)
+	}
+	if err := reloadKeys(cfg.Algorithm); err != nil {
+		utils.Log.Fatalf("JWT 密钥环加载失败: %v", err)
+	}
+	utils.Log.Infof("JWT 密钥环初始化成功，算法: %s", cfg.Algorithm)
+}
+
+// RunKeyRotation 定期重新加载密钥环并按配置的周期轮换密钥，直到 ctx 结束
+// 每次轮换都读取最新配置，热更新的轮换周期与保留时长无需重启即可生效；算法变更需要重启
+func RunKeyRotation(ctx context.Context) {
+	algorithm := config.Get().JWT.Algorithm
+	if algorithm == "" || algorithm == jwt.AlgorithmHS256 {
+		return
+	}
+	ticker := time.NewTicker(keyringReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cfg := config.Get().JWT
+			cfg.Algorithm = algorithm
+			if err := rotateKeys(cfg); err != nil {
+				utils.Log.Errorf("JWT 密钥轮换失败: %v", err)
+			}
+			if err := reloadKeys(algorithm); err != nil {
+				utils.Log.Errorf("JWT 密钥环加载失败: %v", err)
+			}
+		}
+	}
+}
+
+// rotateKeys 没有密钥时生成立即启用的密钥；最新密钥超过轮换周期时生成新密钥并设置旧密钥的过期时间
+func rotateKeys(cfg config.JWTConfig) error {
+	return database.GetDB().Transaction(func(tx *gorm.DB) error {
+		// 多实例同时启动或轮换时只有一个实例生成密钥
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", keyringLockID).Error; err != nil {
+			return err
+		}
+
+		var latest models.SigningKey
+		err := tx.Where("algorithm = ? AND expires_at IS NULL", cfg.Algorithm).
+			Order("activates_at DESC").
+			Limit(1).
+			Find(&latest).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		activatesAt := now
+		if latest.KID != "" {
+			rotation := time.Duration(cfg.RotationInterval) * time.Second
+			due := cfg.RotationInterval > 0 && now.After(latest.ActivatesAt.Add(rotation))
+			if !due {
+				return nil
+			}
+			activatesAt = now.Add(keyPropagationDelay)
+		}
+
+		key, err := generateSigningKey(cfg.Algorithm, activatesAt)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(key).Error; err != nil {
+			return err
+		}
+
+		// 旧密钥在新密钥启用后继续保留一段时间用于验证，保留时间不小于访问令牌有效期
+		retention := time.Duration(max(cfg.KeyRetention, cfg.Expired)) * time.Second
+		if err := tx.Model(&models.SigningKey{}).
+			Where("algorithm = ? AND expires_at IS NULL AND kid <> ?", cfg.Algorithm, key.KID).
+			Update("expires_at", activatesAt.Add(retention)).Error; err != nil {
+			return err
+		}
+		utils.Log.Infof("JWT 签名密钥已生成，kid: %s，启用时间: %s", key.KID, activatesAt.Format(time.RFC3339))
+		return nil
+	})
+}
+
+// reloadKeys 从数据库加载未过期的密钥，已到启用时间的最新密钥用于签发
+func reloadKeys(algorithm string) error {
+	var records []models.SigningKey
+	now := time.Now()
+	if err := database.GetDB().
+		Where("algorithm = ? AND (expires_at IS NULL OR expires_at > ?)", algorithm, now).
+		Order("activates_at DESC").
+		Find(&records).Error; err != nil {
+		return err
+	}
+
+	var active *jwt.SigningKey
+	keys := make([]*jwt.SigningKey, 0, len(records))
+	for _, record := range records {
+		key, err := parseSigningKey(&record)
+		if err != nil {
+			utils.Log.Errorf("JWT 签名密钥 %s 解析失败: %v", record.KID, err)
+			continue
+		}
+		keys = append(keys, key)
+		if active == nil && !record.ActivatesAt.After(now) {
+			active = key
+		}
+	}
+	if active == nil {
+		return fmt.Errorf("没有可用的 %s 签名密钥", algorithm)
+	}
+	jwt.SetKeys(active, keys)
+	return nil
+}
+
+// generateSigningKey 生成新的签名密钥对
+func generateSigningKey(algorithm string, activatesAt time.Time) (*models.SigningKey, error) {
+	var (
+		private crypto.Signer
+		err     error
+	)
+	switch algorithm {
+	case jwt.AlgorithmRS256:
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case jwt.AlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = fmt.Errorf("不支持的签名算法 %s", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(private.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &models.SigningKey{
+		KID:         uuid.NewString(),
+		Algorithm:   algorithm,
+		PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})),
+		PublicKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+		ActivatesAt: activatesAt,
+	}, nil
+}
+
+// parseSigningKey 解析数据库中的 PEM 密钥
+func parseSigningKey(record *models.SigningKey) (*jwt.SigningKey, error) {
+	block, _ := pem.Decode([]byte(record.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("私钥格式错误")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	private, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("不支持的私钥类型 %T", parsed)
+	}
+	return &jwt.SigningKey{
+		KID:       record.KID,
+		Algorithm: record.Algorithm,
+		Private:   private,
+		Public:    private.Public(),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"fmt"
+	"proomet/pkg/utils"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultPolicyFile 默认策略文件，与模型文件放在一起
+const DefaultPolicyFile = "config/rbac_policy.yaml"
+
+// PolicyFile 声明式策略文件结构
+type PolicyFile struct {
+	Policies  [][]string `mapstructure:"policies"`
+	Groupings [][]string `mapstructure:"groupings"`
+}
+
+// LoadPolicyFile 读取并校验策略文件
+func LoadPolicyFile(path string) (*PolicyFile, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("读取策略文件失败: %w", err)
+	}
+
+	var file PolicyFile
+	if err := v.Unmarshal(&file); err != nil {
+		return nil, fmt.Errorf("解析策略文件失败: %w", err)
+	}
+	for i, rule := range file.Policies {
+		if len(rule) != 4 {
+			return nil, fmt.Errorf("policies 第 %d 条应包含 sub, dom, obj, act 四个字段", i+1)
+		}
+	}
+	for i, rule := range file.Groupings {
+		if len(rule) != 3 {
+			return nil, fmt.Errorf("groupings 第 %d 条应包含 sub, role, dom 三个字段", i+1)
+		}
+	}
+	return &file, nil
+}
+
+// SeedPolicies 将策略文件中的规则写入 Casbin，已存在的规则跳过，返回新增的规则数量
+func SeedPolicies(path string) (int, error) {
+	file, err := LoadPolicyFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, rule := range file.Policies {
+		ok, err := Enforcer.AddPolicy(rule[0], rule[1], rule[2], rule[3])
+		if err != nil {
+			return added, fmt.Errorf("写入策略 %v 失败: %w", rule, err)
+		}
+		if ok {
+			added++
+		}
+	}
+	for _, rule := range file.Groupings {
+		ok, err := Enforcer.AddGroupingPolicy(rule[0], rule[1], rule[2])
+		if err != nil {
+			return added, fmt.Errorf("写入角色授予 %v 失败: %w", rule, err)
+		}
+		if ok {
+			added++
+		}
+	}
+	utils.Log.Infof("默认策略写入完成，新增 %d 条，跳过 %d 条", added, len(file.Policies)+len(file.Groupings)-added)
+	return added, nil
+}
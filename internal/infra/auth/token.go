@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// RevokeToken 将访问令牌加入吊销列表，直到其原本的过期时间
+func RevokeToken(jti string, expiresAt time.Time) error {
+	db := database.GetDB()
+	// 顺带清理已自然过期的记录
+	if err := db.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{}).Error; err != nil {
+		return err
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.RevokedToken{JTI: jti, ExpiresAt: expiresAt}).Error
+}
+
+// IsTokenRevoked 判断访问令牌是否已被吊销
+func IsTokenRevoked(jti string) (bool, error) {
+	var count int64
+	err := database.GetDB().Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,21 @@
+package auth
+
+import (
+	"proomet/internal/domain/models"
+	"proomet/internal/infra/database"
+)
+
+// LoadUserState 查询令牌对应用户的最新角色与状态，用户已删除时返回 gorm.ErrRecordNotFound
+func LoadUserState(userID uint) (*models.User, error) {
+	var user models.User
+	if err := database.GetDB().Select("id", "username", "role", "status").First(&user, userID).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// RemoveUser 删除用户主体的全部权限策略与角色授予
+func RemoveUser(userID uint) error {
+	_, err := GetEnforcer().DeleteUser(UserSubject(userID))
+	return err
+}
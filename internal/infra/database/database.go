@@ -1,6 +1,8 @@
 package database
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"proomet/config"
 	"proomet/pkg/utils"
@@ -14,19 +16,20 @@ var DB *gorm.DB
 
 // InitDatabase 初始化数据库连接
 func InitDatabase() {
-	if config.AppConfig == nil {
+	cfg := config.Get()
+	if cfg == nil {
 		utils.Log.Fatal("配置未初始化")
 	}
 
 	// 构建PostgreSQL连接字符串
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=%s",
-		config.AppConfig.Database.Host,
-		config.AppConfig.Database.User,
-		config.AppConfig.Database.Password,
-		config.AppConfig.Database.Name,
-		config.AppConfig.Database.Port,
-		config.AppConfig.Database.SSLMode,
-		config.AppConfig.Database.Timezone, // 修复拼写错误
+		cfg.Database.Host,
+		cfg.Database.User,
+		cfg.Database.Password,
+		cfg.Database.Name,
+		cfg.Database.Port,
+		cfg.Database.SSLMode,
+		cfg.Database.Timezone, // 修复拼写错误
 	)
 
 	var err error
@@ -54,6 +57,18 @@ func GetDB() *gorm.DB {
 	return DB
 }
 
+// Ping 检查数据库连接是否可用
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return errors.New("数据库未初始化")
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
 // Close 关闭数据库连接
 func Close() {
 	if DB != nil {
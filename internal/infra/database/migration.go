@@ -1,32 +1,354 @@
 package database
 
 import (
-	"log"
-	"proomet/internal/domain/models"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"gorm.io/gorm"
 )
 
-// AutoMigrate 自动迁移数据库表结构
-func AutoMigrate() {
-	if DB == nil {
-		log.Fatal("数据库未初始化")
+// SearchConfig 全文检索使用的 PostgreSQL 文本检索配置
+// 使用 simple 配置以避免英文词干化误伤中文及代码片段，修改时需要新增迁移重建 prompts.search_vector
+const SearchConfig = "simple"
+
+// MigrationsDir 迁移文件所在目录（相对项目根目录），create 子命令在此生成文件
+const MigrationsDir = "internal/infra/database/migrations"
+
+// migrationLockID 执行迁移时使用的 PostgreSQL 会话级咨询锁，避免多个实例同时迁移
+const migrationLockID = 7071_0020
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationFilePattern 迁移文件名格式：<版本号>_<名称>.<up|down>.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// migrationNamePattern create 子命令允许的迁移名称
+var migrationNamePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// Migration 一个版本的迁移，Up 与 Down 在同一事务中执行
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// SchemaMigration 已执行的迁移记录，Checksum 用于发现已执行后又被修改的迁移文件
+type SchemaMigration struct {
+	Version   int64     `gorm:"primarykey;autoIncrement:false" json:"version"`
+	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	Checksum  string    `gorm:"type:char(64);not null" json:"checksum"`
+	AppliedAt time.Time `gorm:"not null" json:"applied_at"`
+}
+
+// TableName 迁移记录表
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationState 迁移状态
+type MigrationState struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	// Modified 已执行的迁移文件内容发生了变化
+	Modified bool
+	// Missing 数据库中存在记录但没有对应的迁移文件（通常是数据库已被更新版本的程序迁移）
+	Missing bool
+}
+
+// LoadMigrations 读取内嵌的迁移文件，按版本号升序返回
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		matches := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("迁移文件名格式错误: %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(matches[1], 10, 64)
+		content, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != matches[2] {
+			return nil, fmt.Errorf("迁移版本 %d 存在多个名称: %s, %s", version, migration.Name, matches[2])
+		}
+		if matches[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" {
+			return nil, fmt.Errorf("迁移 %d_%s 缺少 up 文件", migration.Version, migration.Name)
+		}
+		sum := sha256.Sum256([]byte(migration.Up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// MigrateUp 按顺序执行未执行的迁移，steps 不大于 0 时执行全部，返回本次执行的迁移
+func MigrateUp(steps int) ([]Migration, error) {
+	var applied []Migration
+	err := withMigrationLock(func(conn *gorm.DB) error {
+		migrations, records, err := migrationPlan(conn)
+		if err != nil {
+			return err
+		}
+		if err := verifyChecksums(migrations, records); err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if _, ok := records[migration.Version]; ok {
+				continue
+			}
+			if steps > 0 && len(applied) >= steps {
+				break
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+				return tx.Create(&SchemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					Checksum:  migration.Checksum,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("执行迁移 %d_%s 失败: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// MigrateDown 按倒序回滚已执行的迁移，steps 不大于 0 时回滚一个，返回本次回滚的迁移
+func MigrateDown(steps int) ([]Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var reverted []Migration
+	err := withMigrationLock(func(conn *gorm.DB) error {
+		migrations, records, err := migrationPlan(conn)
+		if err != nil {
+			return err
+		}
+		if err := verifyChecksums(migrations, records); err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := migrations[i]
+			if _, ok := records[migration.Version]; !ok {
+				continue
+			}
+			if strings.TrimSpace(migration.Down) == "" {
+				return fmt.Errorf("迁移 %d_%s 没有 down 文件，无法回滚", migration.Version, migration.Name)
+			}
+			err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+				return tx.Delete(&SchemaMigration{}, migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("回滚迁移 %d_%s 失败: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// MigrationStatus 返回全部迁移的执行状态，包含数据库中存在但本程序没有的迁移
+func MigrationStatus() ([]MigrationState, error) {
+	if err := ensureMigrationTable(DB); err != nil {
+		return nil, err
+	}
+	migrations, records, err := migrationPlan(DB)
+	if err != nil {
+		return nil, err
 	}
 
-	// 添加需要迁移的模型
-	// 注意：Casbin 使用自己的表来管理用户-角色关系和角色-权限关系
-	err := DB.AutoMigrate(&models.User{})
+	states := make([]MigrationState, 0, len(migrations))
+	known := make(map[int64]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = true
+		state := MigrationState{Version: migration.Version, Name: migration.Name}
+		if record, ok := records[migration.Version]; ok {
+			state.Applied = true
+			state.AppliedAt = &record.AppliedAt
+			state.Modified = record.Checksum != migration.Checksum
+		}
+		states = append(states, state)
+	}
+	for version, record := range records {
+		if !known[version] {
+			states = append(states, MigrationState{
+				Version:   version,
+				Name:      record.Name,
+				Applied:   true,
+				AppliedAt: &record.AppliedAt,
+				Missing:   true,
+			})
+		}
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Version < states[j].Version
+	})
+	return states, nil
+}
 
+// CheckSchema 启动前检查数据库结构：存在未执行或已被修改的迁移时返回错误
+func CheckSchema() error {
+	states, err := MigrationStatus()
 	if err != nil {
-		log.Fatalf("数据库迁移失败: %v", err)
+		return err
+	}
+
+	var pending, modified []string
+	for _, state := range states {
+		name := fmt.Sprintf("%d_%s", state.Version, state.Name)
+		switch {
+		case !state.Applied:
+			pending = append(pending, name)
+		case state.Modified:
+			modified = append(modified, name)
+		}
+	}
+	if len(modified) > 0 {
+		return fmt.Errorf("已执行的迁移文件被修改: %s", strings.Join(modified, ", "))
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("数据库结构落后，存在 %d 个未执行的迁移: %s，请先执行 migrate up", len(pending), strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// CreateMigration 在 dir 目录下生成以当前时间为版本号的 up/down 迁移文件
+func CreateMigration(dir, name string) (string, string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !migrationNamePattern.MatchString(name) {
+		return "", "", errors.New("迁移名称只能包含小写字母、数字和下划线")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", err
 	}
 
-	// 使用Casbin官方适配器创建表
-	// gorm-adapter 会在首次使用时自动创建所需的表
-	_, err = gormadapter.NewAdapterByDB(DB)
+	base := fmt.Sprintf("%s_%s", time.Now().UTC().Format("20060102150405"), name)
+	upPath := filepath.Join(dir, base+".up.sql")
+	downPath := filepath.Join(dir, base+".down.sql")
+	files := map[string]string{
+		upPath:   "-- " + name + "\n",
+		downPath: "-- 回滚 " + name + "\n",
+	}
+	for path, content := range files {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", err
+		}
+		_, err = file.WriteString(content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return upPath, downPath, nil
+}
+
+// withMigrationLock 在同一个连接上持有咨询锁执行 fn
+func withMigrationLock(fn func(conn *gorm.DB) error) error {
+	if DB == nil {
+		return errors.New("数据库未初始化")
+	}
+	return DB.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockID)
+
+		if err := ensureMigrationTable(conn); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// ensureMigrationTable 创建迁移记录表
+func ensureMigrationTable(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS "schema_migrations" (
+		"version" bigint PRIMARY KEY,
+		"name" varchar(255) NOT NULL,
+		"checksum" char(64) NOT NULL,
+		"applied_at" timestamptz NOT NULL
+	)`).Error
+}
+
+// migrationPlan 读取迁移文件与已执行的记录
+func migrationPlan(db *gorm.DB) ([]Migration, map[int64]SchemaMigration, error) {
+	migrations, err := LoadMigrations()
 	if err != nil {
-		log.Fatalf("Casbin适配器初始化失败: %v", err)
+		return nil, nil, err
+	}
+	var list []SchemaMigration
+	if err := db.Order("version ASC").Find(&list).Error; err != nil {
+		return nil, nil, err
 	}
+	records := make(map[int64]SchemaMigration, len(list))
+	for _, record := range list {
+		records[record.Version] = record
+	}
+	return migrations, records, nil
+}
 
-	log.Println("数据库迁移完成")
+// verifyChecksums 已执行的迁移文件不允许修改，需要变更时应新增迁移
+func verifyChecksums(migrations []Migration, records map[int64]SchemaMigration) error {
+	for _, migration := range migrations {
+		if record, ok := records[migration.Version]; ok && record.Checksum != migration.Checksum {
+			return fmt.Errorf("迁移 %d_%s 已执行但文件内容被修改（校验和不一致）", migration.Version, migration.Name)
+		}
+	}
+	return nil
 }
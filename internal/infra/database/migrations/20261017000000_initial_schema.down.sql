@@ -0,0 +1,23 @@
+-- 删除全部表，会丢失所有数据
+DROP TABLE IF EXISTS "casbin_rule" CASCADE;
+DROP TABLE IF EXISTS "attachments" CASCADE;
+DROP TABLE IF EXISTS "signing_keys" CASCADE;
+DROP TABLE IF EXISTS "audit_logs" CASCADE;
+DROP TABLE IF EXISTS "login_failures" CASCADE;
+DROP TABLE IF EXISTS "action_tokens" CASCADE;
+DROP TABLE IF EXISTS "login_challenges" CASCADE;
+DROP TABLE IF EXISTS "recovery_codes" CASCADE;
+DROP TABLE IF EXISTS "user_totps" CASCADE;
+DROP TABLE IF EXISTS "o_id_c_states" CASCADE;
+DROP TABLE IF EXISTS "user_identities" CASCADE;
+DROP TABLE IF EXISTS "api_keys" CASCADE;
+DROP TABLE IF EXISTS "revoked_tokens" CASCADE;
+DROP TABLE IF EXISTS "refresh_tokens" CASCADE;
+DROP TABLE IF EXISTS "workspace_members" CASCADE;
+DROP TABLE IF EXISTS "workspaces" CASCADE;
+DROP TABLE IF EXISTS "collections" CASCADE;
+DROP TABLE IF EXISTS "prompt_versions" CASCADE;
+DROP TABLE IF EXISTS "prompt_tags" CASCADE;
+DROP TABLE IF EXISTS "tags" CASCADE;
+DROP TABLE IF EXISTS "prompts" CASCADE;
+DROP TABLE IF EXISTS "users" CASCADE;
@@ -0,0 +1,224 @@
+-- 初始表结构，与引入版本化迁移前 AutoMigrate 生成的结构一致
+-- 使用 IF NOT EXISTS，已通过 AutoMigrate 建表的数据库可以直接执行本迁移完成接入
+-- 较早版本建立的表缺少之后新增的列，建表后逐列补齐，保证后续索引与注释可以执行
+
+CREATE TABLE IF NOT EXISTS "users" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"username" varchar(64) NOT NULL,"password_hash" varchar(255) NOT NULL,"nickname" varchar(64),"email" varchar(128),"role" varchar(20) DEFAULT 'member',"status" smallint DEFAULT 1,"email_verified_at" timestamptz,PRIMARY KEY ("id"));
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "email_verified_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_users_role" ON "users" ("role");
+CREATE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username" ON "users" ("username");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+COMMENT ON COLUMN "users"."username" IS '登录名';
+COMMENT ON COLUMN "users"."nickname" IS '昵称';
+COMMENT ON COLUMN "users"."email" IS '邮箱';
+COMMENT ON COLUMN "users"."role" IS '角色标识';
+COMMENT ON COLUMN "users"."status" IS '状态(1:正常, 2:禁用)';
+COMMENT ON COLUMN "users"."email_verified_at" IS '邮箱验证时间';
+
+CREATE TABLE IF NOT EXISTS "prompts" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"title" varchar(128) NOT NULL,"type" varchar(20) NOT NULL DEFAULT 'text',"messages" jsonb,"body" text NOT NULL,"description" varchar(512),"variables" jsonb,"workspace_id" bigint,"collection_id" bigint,"owner_id" bigint NOT NULL,"visibility" varchar(20) DEFAULT 'private',"current_version" bigint NOT NULL DEFAULT 0,PRIMARY KEY ("id"));
+ALTER TABLE "prompts" ADD COLUMN IF NOT EXISTS "type" varchar(20) NOT NULL DEFAULT 'text';
+ALTER TABLE "prompts" ADD COLUMN IF NOT EXISTS "messages" jsonb;
+ALTER TABLE "prompts" ADD COLUMN IF NOT EXISTS "variables" jsonb;
+ALTER TABLE "prompts" ADD COLUMN IF NOT EXISTS "workspace_id" bigint;
+ALTER TABLE "prompts" ADD COLUMN IF NOT EXISTS "collection_id" bigint;
+ALTER TABLE "prompts" ADD COLUMN IF NOT EXISTS "current_version" bigint NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS "idx_prompts_visibility" ON "prompts" ("visibility");
+CREATE INDEX IF NOT EXISTS "idx_prompts_owner_id" ON "prompts" ("owner_id");
+CREATE INDEX IF NOT EXISTS "idx_prompts_collection_id" ON "prompts" ("collection_id");
+CREATE INDEX IF NOT EXISTS "idx_prompts_workspace_id" ON "prompts" ("workspace_id");
+CREATE INDEX IF NOT EXISTS "idx_prompts_type" ON "prompts" ("type");
+CREATE INDEX IF NOT EXISTS "idx_prompts_title" ON "prompts" ("title");
+CREATE INDEX IF NOT EXISTS "idx_prompts_deleted_at" ON "prompts" ("deleted_at");
+COMMENT ON COLUMN "prompts"."title" IS '标题';
+COMMENT ON COLUMN "prompts"."type" IS '类型(text, chat)';
+COMMENT ON COLUMN "prompts"."messages" IS '对话消息';
+COMMENT ON COLUMN "prompts"."body" IS '正文';
+COMMENT ON COLUMN "prompts"."description" IS '描述';
+COMMENT ON COLUMN "prompts"."variables" IS '变量定义';
+COMMENT ON COLUMN "prompts"."workspace_id" IS '所属工作区ID(为空表示个人提示词)';
+COMMENT ON COLUMN "prompts"."collection_id" IS '所属集合ID';
+COMMENT ON COLUMN "prompts"."owner_id" IS '所有者ID';
+COMMENT ON COLUMN "prompts"."visibility" IS '可见性(private, public)';
+COMMENT ON COLUMN "prompts"."current_version" IS '当前版本号';
+
+CREATE TABLE IF NOT EXISTS "tags" ("id" bigserial,"name" varchar(32) NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_tags_name" ON "tags" ("name");
+COMMENT ON COLUMN "tags"."name" IS '名称';
+
+CREATE TABLE IF NOT EXISTS "prompt_tags" ("prompt_id" bigint,"tag_id" bigint,PRIMARY KEY ("prompt_id","tag_id"),CONSTRAINT "fk_prompt_tags_prompt" FOREIGN KEY ("prompt_id") REFERENCES "prompts"("id"),CONSTRAINT "fk_prompt_tags_tag" FOREIGN KEY ("tag_id") REFERENCES "tags"("id"));
+
+CREATE TABLE IF NOT EXISTS "prompt_versions" ("id" bigserial,"prompt_id" bigint NOT NULL,"version" bigint NOT NULL,"title" varchar(128) NOT NULL,"type" varchar(20) NOT NULL DEFAULT 'text',"messages" jsonb,"body" text NOT NULL,"description" varchar(512),"variables" jsonb,"author_id" bigint NOT NULL,"message" varchar(255),"content_hash" char(64) NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"));
+ALTER TABLE "prompt_versions" ADD COLUMN IF NOT EXISTS "type" varchar(20) NOT NULL DEFAULT 'text';
+ALTER TABLE "prompt_versions" ADD COLUMN IF NOT EXISTS "messages" jsonb;
+ALTER TABLE "prompt_versions" ADD COLUMN IF NOT EXISTS "variables" jsonb;
+CREATE INDEX IF NOT EXISTS "idx_prompt_versions_content_hash" ON "prompt_versions" ("content_hash");
+CREATE INDEX IF NOT EXISTS "idx_prompt_versions_author_id" ON "prompt_versions" ("author_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_prompt_version" ON "prompt_versions" ("prompt_id","version");
+COMMENT ON COLUMN "prompt_versions"."prompt_id" IS '提示词ID';
+COMMENT ON COLUMN "prompt_versions"."version" IS '版本号';
+COMMENT ON COLUMN "prompt_versions"."title" IS '标题';
+COMMENT ON COLUMN "prompt_versions"."type" IS '类型(text, chat)';
+COMMENT ON COLUMN "prompt_versions"."messages" IS '对话消息';
+COMMENT ON COLUMN "prompt_versions"."body" IS '正文';
+COMMENT ON COLUMN "prompt_versions"."description" IS '描述';
+COMMENT ON COLUMN "prompt_versions"."variables" IS '变量定义';
+COMMENT ON COLUMN "prompt_versions"."author_id" IS '作者ID';
+COMMENT ON COLUMN "prompt_versions"."message" IS '变更说明';
+COMMENT ON COLUMN "prompt_versions"."content_hash" IS '内容哈希(SHA-256)';
+
+CREATE TABLE IF NOT EXISTS "collections" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" varchar(64) NOT NULL,"description" varchar(255),"parent_id" bigint,"owner_id" bigint NOT NULL,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_collections_owner_id" ON "collections" ("owner_id");
+CREATE INDEX IF NOT EXISTS "idx_collections_parent_id" ON "collections" ("parent_id");
+CREATE INDEX IF NOT EXISTS "idx_collections_deleted_at" ON "collections" ("deleted_at");
+COMMENT ON COLUMN "collections"."name" IS '名称';
+COMMENT ON COLUMN "collections"."description" IS '描述';
+COMMENT ON COLUMN "collections"."parent_id" IS '父级集合ID';
+COMMENT ON COLUMN "collections"."owner_id" IS '所有者ID';
+
+CREATE TABLE IF NOT EXISTS "workspaces" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" varchar(64) NOT NULL,"slug" varchar(64) NOT NULL,"description" varchar(255),"owner_id" bigint NOT NULL,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_workspaces_owner_id" ON "workspaces" ("owner_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_workspaces_slug" ON "workspaces" ("slug");
+CREATE INDEX IF NOT EXISTS "idx_workspaces_deleted_at" ON "workspaces" ("deleted_at");
+COMMENT ON COLUMN "workspaces"."name" IS '名称';
+COMMENT ON COLUMN "workspaces"."slug" IS '唯一标识';
+COMMENT ON COLUMN "workspaces"."description" IS '描述';
+COMMENT ON COLUMN "workspaces"."owner_id" IS '创建者ID';
+
+CREATE TABLE IF NOT EXISTS "workspace_members" ("id" bigserial,"workspace_id" bigint NOT NULL,"user_id" bigint NOT NULL,"role" varchar(20) NOT NULL DEFAULT 'viewer',"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_workspace_members_user_id" ON "workspace_members" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_workspace_member" ON "workspace_members" ("workspace_id","user_id");
+COMMENT ON COLUMN "workspace_members"."workspace_id" IS '工作区ID';
+COMMENT ON COLUMN "workspace_members"."user_id" IS '用户ID';
+COMMENT ON COLUMN "workspace_members"."role" IS '角色(owner, admin, editor, viewer)';
+
+CREATE TABLE IF NOT EXISTS "refresh_tokens" ("id" bigserial,"user_id" bigint NOT NULL,"family_id" varchar(36) NOT NULL,"token_hash" char(64) NOT NULL,"expires_at" timestamptz NOT NULL,"used_at" timestamptz,"revoked_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_refresh_tokens_token_hash" ON "refresh_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_refresh_tokens_family_id" ON "refresh_tokens" ("family_id");
+CREATE INDEX IF NOT EXISTS "idx_refresh_tokens_user_id" ON "refresh_tokens" ("user_id");
+COMMENT ON COLUMN "refresh_tokens"."user_id" IS '用户ID';
+COMMENT ON COLUMN "refresh_tokens"."family_id" IS '令牌家族(登录会话)ID';
+COMMENT ON COLUMN "refresh_tokens"."token_hash" IS '令牌摘要';
+COMMENT ON COLUMN "refresh_tokens"."expires_at" IS '过期时间';
+COMMENT ON COLUMN "refresh_tokens"."used_at" IS '轮换时间';
+COMMENT ON COLUMN "refresh_tokens"."revoked_at" IS '吊销时间';
+
+CREATE TABLE IF NOT EXISTS "revoked_tokens" ("jti" varchar(36),"expires_at" timestamptz NOT NULL,"created_at" timestamptz,PRIMARY KEY ("jti"));
+CREATE INDEX IF NOT EXISTS "idx_revoked_tokens_expires_at" ON "revoked_tokens" ("expires_at");
+COMMENT ON COLUMN "revoked_tokens"."jti" IS '令牌ID';
+COMMENT ON COLUMN "revoked_tokens"."expires_at" IS '令牌过期时间';
+
+CREATE TABLE IF NOT EXISTS "api_keys" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" varchar(64) NOT NULL,"user_id" bigint NOT NULL,"workspace_id" bigint,"prefix" varchar(16) NOT NULL,"key_hash" char(64) NOT NULL,"scopes" jsonb,"expires_at" timestamptz,"last_used_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_api_keys_key_hash" ON "api_keys" ("key_hash");
+CREATE INDEX IF NOT EXISTS "idx_api_keys_prefix" ON "api_keys" ("prefix");
+CREATE INDEX IF NOT EXISTS "idx_api_keys_workspace_id" ON "api_keys" ("workspace_id");
+CREATE INDEX IF NOT EXISTS "idx_api_keys_user_id" ON "api_keys" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_api_keys_deleted_at" ON "api_keys" ("deleted_at");
+COMMENT ON COLUMN "api_keys"."name" IS '名称';
+COMMENT ON COLUMN "api_keys"."user_id" IS '创建者ID';
+COMMENT ON COLUMN "api_keys"."workspace_id" IS '所属工作区ID';
+COMMENT ON COLUMN "api_keys"."prefix" IS '可见前缀';
+COMMENT ON COLUMN "api_keys"."key_hash" IS '密钥摘要';
+COMMENT ON COLUMN "api_keys"."scopes" IS '权限范围';
+COMMENT ON COLUMN "api_keys"."expires_at" IS '过期时间';
+COMMENT ON COLUMN "api_keys"."last_used_at" IS '最近使用时间';
+
+CREATE TABLE IF NOT EXISTS "user_identities" ("id" bigserial,"user_id" bigint NOT NULL,"provider" varchar(32) NOT NULL,"subject" varchar(255) NOT NULL,"email" varchar(128),"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_identity_subject" ON "user_identities" ("provider","subject");
+CREATE INDEX IF NOT EXISTS "idx_user_identities_user_id" ON "user_identities" ("user_id");
+COMMENT ON COLUMN "user_identities"."user_id" IS '用户ID';
+COMMENT ON COLUMN "user_identities"."provider" IS '提供方';
+COMMENT ON COLUMN "user_identities"."subject" IS '提供方中的用户标识(sub)';
+COMMENT ON COLUMN "user_identities"."email" IS '提供方返回的邮箱';
+
+CREATE TABLE IF NOT EXISTS "o_id_c_states" ("state" varchar(64),"provider" varchar(32) NOT NULL,"nonce" varchar(64) NOT NULL,"code_verifier" varchar(128) NOT NULL,"expires_at" timestamptz NOT NULL,"created_at" timestamptz,PRIMARY KEY ("state"));
+CREATE INDEX IF NOT EXISTS "idx_o_id_c_states_expires_at" ON "o_id_c_states" ("expires_at");
+COMMENT ON COLUMN "o_id_c_states"."state" IS 'state 参数';
+COMMENT ON COLUMN "o_id_c_states"."provider" IS '提供方';
+COMMENT ON COLUMN "o_id_c_states"."nonce" IS 'ID Token nonce';
+COMMENT ON COLUMN "o_id_c_states"."code_verifier" IS 'PKCE code_verifier';
+COMMENT ON COLUMN "o_id_c_states"."expires_at" IS '过期时间';
+
+CREATE TABLE IF NOT EXISTS "user_totps" ("id" bigserial,"user_id" bigint NOT NULL,"secret" varchar(64) NOT NULL,"enabled_at" timestamptz,"last_used_step" bigint NOT NULL DEFAULT 0,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_totps_user_id" ON "user_totps" ("user_id");
+COMMENT ON COLUMN "user_totps"."user_id" IS '用户ID';
+COMMENT ON COLUMN "user_totps"."secret" IS 'TOTP密钥';
+COMMENT ON COLUMN "user_totps"."enabled_at" IS '启用时间';
+COMMENT ON COLUMN "user_totps"."last_used_step" IS '最近使用的时间步';
+
+CREATE TABLE IF NOT EXISTS "recovery_codes" ("id" bigserial,"user_id" bigint NOT NULL,"code_hash" char(64) NOT NULL,"used_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_recovery_codes_user_id" ON "recovery_codes" ("user_id");
+COMMENT ON COLUMN "recovery_codes"."user_id" IS '用户ID';
+COMMENT ON COLUMN "recovery_codes"."code_hash" IS '恢复码摘要';
+COMMENT ON COLUMN "recovery_codes"."used_at" IS '使用时间';
+
+CREATE TABLE IF NOT EXISTS "login_challenges" ("id" bigserial,"user_id" bigint NOT NULL,"token_hash" char(64) NOT NULL,"attempts" bigint NOT NULL DEFAULT 0,"expires_at" timestamptz NOT NULL,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_login_challenges_expires_at" ON "login_challenges" ("expires_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_login_challenges_token_hash" ON "login_challenges" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_login_challenges_user_id" ON "login_challenges" ("user_id");
+COMMENT ON COLUMN "login_challenges"."user_id" IS '用户ID';
+COMMENT ON COLUMN "login_challenges"."token_hash" IS '挑战令牌摘要';
+COMMENT ON COLUMN "login_challenges"."attempts" IS '失败次数';
+COMMENT ON COLUMN "login_challenges"."expires_at" IS '过期时间';
+
+CREATE TABLE IF NOT EXISTS "action_tokens" ("jti" varchar(36),"user_id" bigint NOT NULL,"purpose" varchar(32) NOT NULL,"expires_at" timestamptz NOT NULL,"used_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("jti"));
+CREATE INDEX IF NOT EXISTS "idx_action_tokens_expires_at" ON "action_tokens" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_action_tokens_user_id" ON "action_tokens" ("user_id");
+COMMENT ON COLUMN "action_tokens"."jti" IS '令牌ID';
+COMMENT ON COLUMN "action_tokens"."user_id" IS '用户ID';
+COMMENT ON COLUMN "action_tokens"."purpose" IS '操作类型';
+COMMENT ON COLUMN "action_tokens"."expires_at" IS '过期时间';
+COMMENT ON COLUMN "action_tokens"."used_at" IS '使用时间';
+
+CREATE TABLE IF NOT EXISTS "login_failures" ("key" varchar(160),"failures" bigint NOT NULL DEFAULT 0,"lockouts" bigint NOT NULL DEFAULT 0,"window_start" timestamptz NOT NULL,"locked_until" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("key"));
+CREATE INDEX IF NOT EXISTS "idx_login_failures_updated_at" ON "login_failures" ("updated_at");
+COMMENT ON COLUMN "login_failures"."key" IS '计数键';
+COMMENT ON COLUMN "login_failures"."failures" IS '窗口内失败次数';
+COMMENT ON COLUMN "login_failures"."lockouts" IS '连续锁定次数';
+COMMENT ON COLUMN "login_failures"."window_start" IS '统计窗口开始时间';
+COMMENT ON COLUMN "login_failures"."locked_until" IS '锁定截止时间';
+
+CREATE TABLE IF NOT EXISTS "audit_logs" ("id" bigserial,"user_id" bigint,"action" varchar(64) NOT NULL,"target" varchar(255),"ip" varchar(64),"detail" text,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_created_at" ON "audit_logs" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_action" ON "audit_logs" ("action");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_user_id" ON "audit_logs" ("user_id");
+COMMENT ON COLUMN "audit_logs"."user_id" IS '相关用户ID';
+COMMENT ON COLUMN "audit_logs"."action" IS '事件类型';
+COMMENT ON COLUMN "audit_logs"."target" IS '事件对象';
+COMMENT ON COLUMN "audit_logs"."ip" IS '来源IP';
+COMMENT ON COLUMN "audit_logs"."detail" IS '详情';
+
+CREATE TABLE IF NOT EXISTS "signing_keys" ("kid" varchar(64),"algorithm" varchar(16) NOT NULL,"private_key" text NOT NULL,"public_key" text NOT NULL,"activates_at" timestamptz NOT NULL,"expires_at" timestamptz,"created_at" timestamptz,PRIMARY KEY ("kid"));
+CREATE INDEX IF NOT EXISTS "idx_signing_keys_expires_at" ON "signing_keys" ("expires_at");
+CREATE INDEX IF NOT EXISTS "idx_signing_keys_algorithm" ON "signing_keys" ("algorithm");
+COMMENT ON COLUMN "signing_keys"."kid" IS '密钥ID';
+COMMENT ON COLUMN "signing_keys"."algorithm" IS '签名算法';
+COMMENT ON COLUMN "signing_keys"."private_key" IS '私钥(PKCS8 PEM)';
+COMMENT ON COLUMN "signing_keys"."public_key" IS '公钥(PKIX PEM)';
+COMMENT ON COLUMN "signing_keys"."activates_at" IS '启用时间';
+COMMENT ON COLUMN "signing_keys"."expires_at" IS '停止验证时间';
+
+CREATE TABLE IF NOT EXISTS "attachments" ("id" bigserial,"prompt_id" bigint NOT NULL,"uploader_id" bigint NOT NULL,"file_name" varchar(255) NOT NULL,"content_type" varchar(128) NOT NULL,"size" bigint NOT NULL,"bucket" varchar(64) NOT NULL,"object_key" varchar(512) NOT NULL,"status" varchar(20) NOT NULL DEFAULT 'ready',"expires_at" timestamptz,"created_at" timestamptz,"updated_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_attachments_status" ON "attachments" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_attachments_object_key" ON "attachments" ("object_key");
+CREATE INDEX IF NOT EXISTS "idx_attachments_uploader_id" ON "attachments" ("uploader_id");
+CREATE INDEX IF NOT EXISTS "idx_attachments_prompt_id" ON "attachments" ("prompt_id");
+COMMENT ON COLUMN "attachments"."prompt_id" IS '提示词ID';
+COMMENT ON COLUMN "attachments"."uploader_id" IS '上传者ID';
+COMMENT ON COLUMN "attachments"."file_name" IS '文件名';
+COMMENT ON COLUMN "attachments"."content_type" IS 'MIME类型';
+COMMENT ON COLUMN "attachments"."size" IS '文件大小(字节)';
+COMMENT ON COLUMN "attachments"."bucket" IS '存储桶';
+COMMENT ON COLUMN "attachments"."object_key" IS '对象键';
+COMMENT ON COLUMN "attachments"."status" IS '状态(pending, ready)';
+COMMENT ON COLUMN "attachments"."expires_at" IS '待上传记录的过期时间';
+
+CREATE TABLE IF NOT EXISTS "casbin_rule" ("id" bigserial,"ptype" varchar(100),"v0" varchar(100),"v1" varchar(100),"v2" varchar(100),"v3" varchar(100),"v4" varchar(100),"v5" varchar(100),PRIMARY KEY ("id"));
+
+-- 全文检索：标题 A > 描述 B > 正文 C 加权的 tsvector 生成列及 GIN 索引
+ALTER TABLE "prompts" ADD COLUMN IF NOT EXISTS "search_vector" tsvector
+	GENERATED ALWAYS AS (
+		setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+		setweight(to_tsvector('simple', coalesce(description, '')), 'B') ||
+		setweight(to_tsvector('simple', coalesce(body, '')), 'C')
+	) STORED;
+CREATE INDEX IF NOT EXISTS "idx_prompts_search_vector" ON "prompts" USING GIN ("search_vector");
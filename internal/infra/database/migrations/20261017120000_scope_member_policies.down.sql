@@ -0,0 +1,9 @@
+-- 恢复普通成员在所有域生效的提示词与工作区规则
+DELETE FROM "casbin_rule"
+WHERE "ptype" = 'p' AND "v0" = 'member' AND "v1" = 'personal'
+  AND "v2" IN ('/prompts', '/prompts/*', '/workspaces');
+INSERT INTO "casbin_rule" ("ptype", "v0", "v1", "v2", "v3", "v4", "v5") VALUES
+  ('p', 'member', '*', '/prompts', 'POST', '', ''),
+  ('p', 'member', '*', '/prompts/*', '*', '', ''),
+  ('p', 'member', '*', '/workspaces', '*', '', ''),
+  ('p', 'member', '*', '/workspaces/*', '*', '', '');
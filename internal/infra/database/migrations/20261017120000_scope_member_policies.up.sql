@@ -0,0 +1,5 @@
+-- 普通成员的提示词与工作区规则改为只在个人域生效，避免在工作区内绕过工作区角色（如 viewer 写入提示词）
+-- 新规则由 migrate up 完成后的默认策略写入添加，这里只删除旧的全局规则
+DELETE FROM "casbin_rule"
+WHERE "ptype" = 'p' AND "v0" = 'member' AND "v1" = '*'
+  AND "v2" IN ('/prompts', '/prompts/*', '/workspaces', '/workspaces/*');
@@ -0,0 +1,4 @@
+-- 恢复 OIDC 授权状态表的原表名
+ALTER INDEX "idx_oidc_states_expires_at" RENAME TO "idx_o_id_c_states_expires_at";
+ALTER TABLE "oidc_states" RENAME CONSTRAINT "oidc_states_pkey" TO "o_id_c_states_pkey";
+ALTER TABLE "oidc_states" RENAME TO "o_id_c_states";
@@ -0,0 +1,4 @@
+-- OIDC 授权状态表原先按默认命名规则生成为 o_id_c_states，统一改为 oidc_states
+ALTER TABLE "o_id_c_states" RENAME TO "oidc_states";
+ALTER TABLE "oidc_states" RENAME CONSTRAINT "o_id_c_states_pkey" TO "oidc_states_pkey";
+ALTER INDEX "idx_o_id_c_states_expires_at" RENAME TO "idx_oidc_states_expires_at";
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"proomet/config"
+	"proomet/internal/domain/models"
+	"proomet/pkg/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SeedAdmin 创建初始管理员：系统中已存在管理员时跳过，保证重复执行迁移不会产生副作用
+func SeedAdmin(cfg config.AdminConfig) error {
+	var count int64
+	if err := DB.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&count).Error; err != nil {
+		return fmt.Errorf("查询管理员失败: %w", err)
+	}
+	if count > 0 {
+		utils.Log.Info("已存在管理员，跳过初始管理员创建")
+		return nil
+	}
+
+	username := utils.DefaultString(cfg.Username, "admin")
+	var existing int64
+	if err := DB.Model(&models.User{}).Unscoped().Where("username = ?", username).Count(&existing).Error; err != nil {
+		return fmt.Errorf("查询用户失败: %w", err)
+	}
+	if existing > 0 {
+		return fmt.Errorf("用户名 %s 已被普通用户占用，请修改 admin.username 后重试", username)
+	}
+
+	password := cfg.Password
+	generated := password == ""
+	if generated {
+		buf := make([]byte, 12)
+		if _, err := rand.Read(buf); err != nil {
+			return fmt.Errorf("生成随机密码失败: %w", err)
+		}
+		password = base64.RawURLEncoding.EncodeToString(buf)
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("密码加密失败: %w", err)
+	}
+
+	admin := models.User{
+		Username:     username,
+		PasswordHash: string(hashedPassword),
+		Nickname:     username,
+		Email:        cfg.Email,
+		Role:         models.RoleAdmin,
+		Status:       models.UserStatusActive,
+	}
+	if err := DB.Create(&admin).Error; err != nil {
+		return fmt.Errorf("创建管理员失败: %w", err)
+	}
+
+	if generated {
+		utils.Log.Warnf("已创建初始管理员 %s，随机密码: %s（仅输出一次，请登录后尽快修改）", username, password)
+	} else {
+		utils.Log.Infof("已创建初始管理员 %s", username)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"proomet/pkg/utils"
+	"time"
+)
+
+// FileMailer 将邮件写入目录中的 .eml 文件，用于开发与测试环境
+type FileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer 创建文件邮件发送实例
+func NewFileMailer(from, dir string) *FileMailer {
+	return &FileMailer{from: from, dir: dir}
+}
+
+// Send 写入邮件文件
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	data, err := build(m.from, msg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), messageID()[:8])
+	path := filepath.Join(m.dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	utils.Log.Infof("邮件已写入 %s，收件人: %v，主题: %s", path, msg.To, msg.Subject)
+	return nil
+}
@@ -0,0 +1,155 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"proomet/config"
+	"proomet/pkg/utils"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message 邮件内容，目前只发送纯文本邮件
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+var defaultMailer Mailer
+
+// pending 正在后台发送的邮件，服务停止时等待发送完成
+var pending sync.WaitGroup
+
+// InitMailer 按配置初始化邮件发送方式，未知的发送方式回退到 file
+func InitMailer(cfg config.MailConfig) {
+	switch cfg.Driver {
+	case "smtp":
+		if cfg.SMTP.Host == "" {
+			utils.Log.Fatal("邮件初始化失败，smtp.host 未配置")
+		}
+		defaultMailer = NewSMTPMailer(cfg.From, cfg.SMTP)
+		utils.Log.Infof("邮件初始化成功，SMTP: %s:%d", cfg.SMTP.Host, cfg.SMTP.Port)
+	default:
+		if cfg.Driver != "file" {
+			utils.Log.Warnf("未知的邮件发送方式 %s，使用 file", cfg.Driver)
+		}
+		defaultMailer = NewFileMailer(cfg.From, cfg.FileDir)
+		utils.Log.Infof("邮件初始化成功，邮件将写入目录: %s", cfg.FileDir)
+	}
+}
+
+// GetMailer 获取邮件发送实例
+func GetMailer() Mailer {
+	return defaultMailer
+}
+
+// SetMailer 替换邮件发送实例
+func SetMailer(m Mailer) {
+	defaultMailer = m
+}
+
+// Send 使用默认实例发送邮件
+func Send(ctx context.Context, msg *Message) error {
+	if defaultMailer == nil {
+		return fmt.Errorf("邮件服务未初始化")
+	}
+	return defaultMailer.Send(ctx, msg)
+}
+
+// SendAsync 在后台发送邮件，发送失败时调用 onError
+func SendAsync(msg *Message, timeout time.Duration, onError func(error)) {
+	pending.Add(1)
+	go func() {
+		defer pending.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := Send(ctx, msg); err != nil {
+			onError(err)
+		}
+	}()
+}
+
+// Wait 等待后台发送的邮件完成，ctx 结束时返回错误
+func Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// build 生成 RFC 5322 格式的邮件，正文使用 quoted-printable 编码（链接与令牌保持可读）
+func build(from string, msg *Message) ([]byte, error) {
+	if len(msg.To) == 0 {
+		return nil, fmt.Errorf("收件人不能为空")
+	}
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("发件人格式错误: %w", err)
+	}
+	for _, to := range msg.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return nil, fmt.Errorf("收件人格式错误: %w", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", sender.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", messageID(), domainOf(sender.Address))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(msg.Body, "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// senderAddress 发件人邮箱地址（SMTP MAIL FROM）
+func senderAddress(from string) (string, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return "", fmt.Errorf("发件人格式错误: %w", err)
+	}
+	return sender.Address, nil
+}
+
+func messageID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func domainOf(address string) string {
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		return address[at+1:]
+	}
+	return "localhost"
+}
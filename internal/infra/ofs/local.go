@@ -0,0 +1,413 @@
+package ofs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultContentType 未声明内容类型的对象
+const defaultContentType = "application/octet-stream"
+
+// LocalStorage 基于本地目录的对象存储，每个 bucket 对应 dir 下的一个子目录
+// 对象的内容类型保存在 dir/.meta 下与对象相同的相对路径中
+// 预签名 URL 由本服务通过 ServeHTTP 处理，需要将 BasePath 挂载到 HTTP 路由
+type LocalStorage struct {
+	dir     string
+	baseURL *url.URL
+	key     []byte
+}
+
+// NewLocalStorage 创建本地存储实例，baseURL 为预签名 URL 的前缀，key 为签名密钥
+func NewLocalStorage(dir, baseURL string, key []byte) (*LocalStorage, error) {
+	if dir == "" {
+		return nil, errors.New("本地存储目录不能为空")
+	}
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" || u.Path == "" {
+		return nil, fmt.Errorf("本地存储 base_url 格式错误: %s", baseURL)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(abs, ".tmp"), 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{dir: abs, baseURL: u, key: key}, nil
+}
+
+// BasePath 预签名 URL 的路径前缀，用于挂载到 HTTP 路由
+func (s *LocalStorage) BasePath() string {
+	return s.baseURL.Path
+}
+
+// Ping 检查存储目录可写
+func (s *LocalStorage) Ping(ctx context.Context) error {
+	file, err := os.CreateTemp(filepath.Join(s.dir, ".tmp"), "ping-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// Put 写入对象，先写入临时文件再重命名，读取方不会看到写了一半的文件
+func (s *LocalStorage) Put(ctx context.Context, bucket, key string, body io.Reader, size int64, contentType string) error {
+	target, err := s.path(bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(s.dir, ".tmp"), "put-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, contextReader{ctx: ctx, r: body})
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && written != size {
+		return fmt.Errorf("对象大小不一致，期望 %d，实际 %d", size, written)
+	}
+	if err := s.writeContentType(bucket, key, contentType); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// Get 读取对象
+func (s *LocalStorage) Get(ctx context.Context, bucket, key string) (io.ReadCloser, *ObjectInfo, error) {
+	target, err := s.path(bucket, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	file, err := os.Open(target)
+	if err != nil {
+		return nil, nil, localError(err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	if stat.IsDir() {
+		file.Close()
+		return nil, nil, ErrNotFound
+	}
+	return file, s.objectInfo(bucket, key, stat), nil
+}
+
+// Delete 删除对象
+func (s *LocalStorage) Delete(ctx context.Context, bucket, key string) error {
+	target, err := s.path(bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.Remove(s.metaPath(bucket, key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// List 列出指定前缀下的全部对象
+func (s *LocalStorage) List(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error) {
+	if !validBucket(bucket) {
+		return nil, fmt.Errorf("bucket 名称不合法: %s", bucket)
+	}
+	root := filepath.Join(s.dir, bucket)
+
+	var objects []ObjectInfo
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return ctx.Err()
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		stat, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, *s.objectInfo(bucket, key, stat))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// Stat 获取对象元数据
+func (s *LocalStorage) Stat(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	target, err := s.path(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	stat, err := os.Stat(target)
+	if err != nil {
+		return nil, localError(err)
+	}
+	if stat.IsDir() {
+		return nil, ErrNotFound
+	}
+	return s.objectInfo(bucket, key, stat), nil
+}
+
+// Presign 生成由本服务处理的预签名 URL
+func (s *LocalStorage) Presign(ctx context.Context, bucket, key string, opts PresignOptions) (string, error) {
+	if opts.Method != http.MethodGet && opts.Method != http.MethodPut {
+		return "", fmt.Errorf("不支持的预签名方法 %s", opts.Method)
+	}
+	if _, err := s.path(bucket, key); err != nil {
+		return "", err
+	}
+
+	params := presignParams{
+		method:        opts.Method,
+		bucket:        bucket,
+		key:           key,
+		expires:       time.Now().Add(opts.Expires).Unix(),
+		contentType:   opts.ContentType,
+		contentLength: opts.ContentLength,
+	}
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(params.expires, 10))
+	if params.contentType != "" {
+		query.Set("content_type", params.contentType)
+	}
+	if params.contentLength > 0 {
+		query.Set("content_length", strconv.FormatInt(params.contentLength, 10))
+	}
+	query.Set("signature", s.sign(params))
+
+	u := *s.baseURL
+	u.Path = path.Join(u.Path, bucket, key)
+	u.RawPath = ""
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// ServeHTTP 处理预签名 URL 的下载（GET/HEAD）与上传（PUT），请求路径需已去掉 BasePath
+func (s *LocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	if method != http.MethodGet && method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	params := presignParams{
+		method:      method,
+		bucket:      bucket,
+		key:         key,
+		contentType: query.Get("content_type"),
+	}
+	params.expires, _ = strconv.ParseInt(query.Get("expires"), 10, 64)
+	params.contentLength, _ = strconv.ParseInt(query.Get("content_length"), 10, 64)
+	if !hmac.Equal([]byte(s.sign(params)), []byte(query.Get("signature"))) {
+		http.Error(w, "signature does not match", http.StatusForbidden)
+		return
+	}
+	if time.Now().Unix() > params.expires {
+		http.Error(w, "request has expired", http.StatusForbidden)
+		return
+	}
+
+	if method == http.MethodGet {
+		s.serveGet(w, r, bucket, key)
+		return
+	}
+	s.servePut(w, r, params)
+}
+
+// serveGet 输出对象内容，支持 Range 与条件请求
+func (s *LocalStorage) serveGet(w http.ResponseWriter, r *http.Request, bucket, key string) {
+	body, info, err := s.Get(r.Context(), bucket, key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	defer body.Close()
+
+	// 对象由用户上传，始终作为附件下载并禁止浏览器猜测类型，避免在本服务的域名下执行脚本
+	w.Header().Set("Content-Type", info.ContentType)
+	w.Header().Set("Content-Disposition", "attachment")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", `"`+info.ETag+`"`)
+	http.ServeContent(w, r, "", info.LastModified, body.(io.ReadSeeker))
+}
+
+// servePut 写入对象，签名中包含内容类型与大小时校验请求是否一致
+func (s *LocalStorage) servePut(w http.ResponseWriter, r *http.Request, params presignParams) {
+	contentType := r.Header.Get("Content-Type")
+	if params.contentType != "" && contentType != params.contentType {
+		http.Error(w, "content type does not match", http.StatusBadRequest)
+		return
+	}
+	size := int64(-1)
+	if params.contentLength > 0 {
+		if r.ContentLength != params.contentLength {
+			http.Error(w, "content length does not match", http.StatusBadRequest)
+			return
+		}
+		size = params.contentLength
+		r.Body = http.MaxBytesReader(w, r.Body, size)
+	}
+
+	if err := s.Put(r.Context(), params.bucket, params.key, r.Body, size, contentType); err != nil {
+		http.Error(w, "upload failed", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// presignParams 预签名参数
+type presignParams struct {
+	method        string
+	bucket        string
+	key           string
+	expires       int64
+	contentType   string
+	contentLength int64
+}
+
+// sign 计算预签名签名
+func (s *LocalStorage) sign(p presignParams) string {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d\n%s\n%d", p.method, p.bucket, p.key, p.expires, p.contentType, p.contentLength)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path 校验 bucket 与 key 并返回对象的文件路径
+func (s *LocalStorage) path(bucket, key string) (string, error) {
+	if !validBucket(bucket) {
+		return "", fmt.Errorf("bucket 名称不合法: %s", bucket)
+	}
+	if key == "" || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("对象 key 不合法: %s", key)
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("对象 key 不合法: %s", key)
+		}
+	}
+	return filepath.Join(s.dir, bucket, filepath.FromSlash(key)), nil
+}
+
+// validBucket bucket 名称不能为空、不能包含路径分隔符且不能以 . 开头
+func validBucket(bucket string) bool {
+	return bucket != "" && !strings.HasPrefix(bucket, ".") && !strings.ContainsAny(bucket, "/\\")
+}
+
+// metaPath 对象元数据文件路径，bucket 名称不能以 . 开头，不会与 .meta 目录冲突
+func (s *LocalStorage) metaPath(bucket, key string) string {
+	return filepath.Join(s.dir, ".meta", bucket, filepath.FromSlash(key))
+}
+
+// writeContentType 保存对象写入时声明的内容类型
+func (s *LocalStorage) writeContentType(bucket, key, contentType string) error {
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+	target := s.metaPath(bucket, key)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Join(s.dir, ".tmp"), "meta-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(contentType)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// objectInfo 根据文件信息与保存的内容类型生成对象元数据，没有保存内容类型时按二进制流处理
+func (s *LocalStorage) objectInfo(bucket, key string, stat fs.FileInfo) *ObjectInfo {
+	contentType := defaultContentType
+	if data, err := os.ReadFile(s.metaPath(bucket, key)); err == nil && len(data) > 0 {
+		contentType = string(data)
+	}
+	return &ObjectInfo{
+		Key:          key,
+		Size:         stat.Size(),
+		ContentType:  contentType,
+		ETag:         fmt.Sprintf("%x-%x", stat.ModTime().UnixNano(), stat.Size()),
+		LastModified: stat.ModTime(),
+	}
+}
+
+// localError 将文件不存在的错误转换为 ErrNotFound
+func localError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// contextReader 在 ctx 结束后中止读取
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
@@ -0,0 +1,70 @@
+package ofs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStorageServeGet(t *testing.T) {
+	const body = "<script>alert(document.cookie)</script>"
+	tests := []struct {
+		name        string
+		key         string
+		contentType string
+		wantType    string
+	}{
+		{"按写入时的类型返回而不是扩展名", "prompts/1/x.html", "text/plain", "text/plain"},
+		{"图片", "prompts/1/a.png", "image/png", "image/png"},
+		{"未声明类型按二进制流返回", "prompts/1/b.svg", "", "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage, err := NewLocalStorage(t.TempDir(), "http://localhost:7070/ofs", []byte("test-key"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := context.Background()
+			if err := storage.Put(ctx, "attachments", tt.key, strings.NewReader(body), int64(len(body)), tt.contentType); err != nil {
+				t.Fatal(err)
+			}
+			info, err := storage.Stat(ctx, "attachments", tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.ContentType != tt.wantType {
+				t.Fatalf("Stat 返回类型 %s，期望 %s", info.ContentType, tt.wantType)
+			}
+
+			signed, err := storage.Presign(ctx, "attachments", tt.key, PresignOptions{Method: http.MethodGet, Expires: time.Minute})
+			if err != nil {
+				t.Fatal(err)
+			}
+			u, err := url.Parse(signed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, strings.TrimPrefix(u.Path, storage.BasePath())+"?"+u.RawQuery, nil)
+			storage.ServeHTTP(recorder, req)
+
+			if recorder.Code != http.StatusOK || recorder.Body.String() != body {
+				t.Fatalf("下载失败: %d %s", recorder.Code, recorder.Body.String())
+			}
+			header := recorder.Header()
+			if got := header.Get("Content-Type"); got != tt.wantType {
+				t.Fatalf("Content-Type 为 %s，期望 %s", got, tt.wantType)
+			}
+			if got := header.Get("X-Content-Type-Options"); got != "nosniff" {
+				t.Fatalf("X-Content-Type-Options 为 %q", got)
+			}
+			if got := header.Get("Content-Disposition"); got != "attachment" {
+				t.Fatalf("Content-Disposition 为 %q", got)
+			}
+		})
+	}
+}
@@ -2,66 +2,144 @@ package ofs
 
 import (
 	"context"
-	"fmt"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"io"
 	"proomet/config"
 	"proomet/pkg/utils"
-	"strings"
 	"time"
+)
 
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
+const (
+	DriverS3    = "s3"
+	DriverLocal = "local"
 )
 
+// Bucket 各业务使用的 bucket 名称，由 InitOfs 根据配置设置
 var Bucket = struct {
-	Demo string
+	Attachments string
 }{
-	Demo: "demo",
+	Attachments: "proomet-attachments",
+}
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo 对象元数据
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
 }
 
-var client *s3.Client
+// PresignOptions 预签名参数，Method 为 GET 或 PUT
+// PUT 时 ContentType、ContentLength 不为空则一并签名，上传时必须与之一致
+type PresignOptions struct {
+	Method        string
+	Expires       time.Duration
+	ContentType   string
+	ContentLength int64
+}
+
+// Storage 对象存储接口
+type Storage interface {
+	// Put 写入对象，size 未知时传 -1
+	Put(ctx context.Context, bucket, key string, body io.Reader, size int64, contentType string) error
+	// Get 读取对象，调用方负责关闭返回的 ReadCloser
+	Get(ctx context.Context, bucket, key string) (io.ReadCloser, *ObjectInfo, error)
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(ctx context.Context, bucket, key string) error
+	// List 列出指定前缀下的全部对象
+	List(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error)
+	// Stat 获取对象元数据
+	Stat(ctx context.Context, bucket, key string) (*ObjectInfo, error)
+	// Presign 生成无需认证即可直接访问对象的临时 URL
+	Presign(ctx context.Context, bucket, key string, opts PresignOptions) (string, error)
+}
+
+// Pinger 可以检查连接状态的对象存储
+type Pinger interface {
+	// Ping 检查存储服务可用且业务 bucket 可以访问
+	Ping(ctx context.Context) error
+}
+
+var storage Storage
 
 func InitOfs() {
-	if config.AppConfig == nil {
+	cfg := config.Get()
+	if cfg == nil {
 		utils.Log.Fatal("配置未初始化")
 	}
-	if !config.AppConfig.S3.Enabled {
-		utils.Log.Info("S3未启用")
-		return
+	driver := cfg.StorageDriver()
+
+	var err error
+	switch driver {
+	case DriverS3:
+		storage, err = NewS3Storage(cfg.S3)
+	case DriverLocal:
+		local := cfg.Storage.Local
+		storage, err = NewLocalStorage(local.Dir, local.BaseURL, presignKey())
+	default:
+		utils.Log.Fatalf("不支持的对象存储类型 %s", driver)
+	}
+	if err != nil {
+		utils.Log.Fatalf("对象存储初始化失败: %v", err)
 	}
-	accessKeyID := config.AppConfig.S3.AccessKeyID
-	secretAccessKey := config.AppConfig.S3.SecretAccessKey
-	region := config.AppConfig.S3.Region
-	endpoint := config.AppConfig.S3.Endpoint
 
-	if accessKeyID == "" || secretAccessKey == "" || region == "" || endpoint == "" {
-		utils.Log.Fatalf("S3初始化失败，参数缺失")
+	buckets := cfg.Storage.Buckets
+	if buckets.Attachments != "" {
+		Bucket.Attachments = buckets.Attachments
 	}
-	creds := credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
-	cfg := aws.Config{
-		Region:      region,
-		Credentials: creds,
+	if s3Storage, ok := storage.(*S3Storage); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		for _, bucket := range []string{Bucket.Attachments} {
+			if err := s3Storage.EnsureBucket(ctx, bucket); err != nil {
+				utils.Log.Fatalf("创建 bucket %s 失败: %v", bucket, err)
+			}
+		}
 	}
+	utils.Log.Infof("对象存储初始化完成，类型: %s", driver)
+}
 
-	client = s3.NewFromConfig(cfg, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(endpoint)
-		o.UsePathStyle = true
-	})
+// GetStorage 获取对象存储实例
+func GetStorage() Storage {
+	return storage
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-	resp, err := client.ListBuckets(ctx, &s3.ListBucketsInput{})
-	if err != nil {
-		utils.Log.Fatalf("S3连接失败: %v", err)
+// SetStorage 替换对象存储实例
+func SetStorage(s Storage) {
+	storage = s
+}
+
+// Ping 检查对象存储是否可用，存储实现不支持检查时视为可用
+func Ping(ctx context.Context) error {
+	if storage == nil {
+		return errors.New("对象存储未初始化")
+	}
+	if pinger, ok := storage.(Pinger); ok {
+		return pinger.Ping(ctx)
 	}
-	bucketList := make([]string, len(resp.Buckets))
-	for i, b := range resp.Buckets {
-		bucketList[i] = *b.Name
+	return nil
+}
+
+// Close 释放对象存储占用的资源（如连接池）
+func Close() {
+	if closer, ok := storage.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			utils.Log.Errorf("关闭对象存储失败: %v", err)
+			return
+		}
+		utils.Log.Println("对象存储已关闭")
 	}
-	utils.Log.Info(fmt.Sprintf("Buckets: %s", strings.Join(bucketList, ", ")))
-	utils.Log.Info("S3初始化完成")
 }
 
-func GetClient() *s3.Client {
-	return client
+// presignKey 本地存储预签名使用的密钥，由 JWT 密钥派生
+func presignKey() []byte {
+	mac := hmac.New(sha256.New, []byte(config.Get().JWT.Secret))
+	mac.Write([]byte("ofs-presign"))
+	return mac.Sum(nil)
 }
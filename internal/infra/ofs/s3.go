@@ -0,0 +1,246 @@
+package ofs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"proomet/config"
+	"proomet/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+// S3Storage 基于 S3 兼容服务（AWS S3、MinIO、RustFS 等）的对象存储
+type S3Storage struct {
+	client    *s3.Client
+	presign   *s3.PresignClient
+	transport *http.Transport
+}
+
+// NewS3Storage 创建 S3 存储实例并检查连接
+func NewS3Storage(cfg config.S3Config) (*S3Storage, error) {
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" || cfg.Region == "" || cfg.Endpoint == "" {
+		return nil, errors.New("S3 参数缺失")
+	}
+	creds := credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, "")
+	// 自行持有连接池，以便关闭时释放空闲连接；与 SDK 默认行为一致，不跟随重定向
+	transport := awshttp.NewBuildableClient().GetTransport()
+	awsCfg := aws.Config{
+		Region:      cfg.Region,
+		Credentials: creds,
+		HTTPClient: &http.Client{
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(cfg.Endpoint)
+		o.UsePathStyle = true
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := client.ListBuckets(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("S3连接失败: %w", err)
+	}
+	bucketList := make([]string, len(resp.Buckets))
+	for i, b := range resp.Buckets {
+		bucketList[i] = aws.ToString(b.Name)
+	}
+	utils.Log.Info(fmt.Sprintf("Buckets: %s", strings.Join(bucketList, ", ")))
+
+	return &S3Storage{
+		client:    client,
+		presign:   s3.NewPresignClient(client),
+		transport: transport,
+	}, nil
+}
+
+// Ping 检查业务 bucket 可以访问
+func (s *S3Storage) Ping(ctx context.Context) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(Bucket.Attachments)})
+	return s3Error(err)
+}
+
+// Close 释放空闲连接
+func (s *S3Storage) Close() error {
+	s.transport.CloseIdleConnections()
+	return nil
+}
+
+// Client 底层 S3 客户端
+func (s *S3Storage) Client() *s3.Client {
+	return s.client
+}
+
+// EnsureBucket bucket 不存在时创建
+func (s *S3Storage) EnsureBucket(ctx context.Context, bucket string) error {
+	_, err := s.client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)})
+	if err == nil || !errors.Is(s3Error(err), ErrNotFound) {
+		return err
+	}
+	_, err = s.client.CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return err
+	}
+	utils.Log.Infof("已创建 bucket %s", bucket)
+	return nil
+}
+
+// Put 写入对象
+func (s *S3Storage) Put(ctx context.Context, bucket, key string, body io.Reader, size int64, contentType string) error {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	}
+	if size >= 0 {
+		input.ContentLength = aws.Int64(size)
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	var optFns []func(*s3.Options)
+	// 不可 Seek 的流无法预先计算载荷摘要，改为不签名载荷
+	if _, ok := body.(io.ReadSeeker); !ok {
+		optFns = append(optFns, s3.WithAPIOptions(v4.SwapComputePayloadSHA256ForUnsignedPayloadMiddleware))
+	}
+	_, err := s.client.PutObject(ctx, input, optFns...)
+	return err
+}
+
+// Get 读取对象
+func (s *S3Storage) Get(ctx context.Context, bucket, key string) (io.ReadCloser, *ObjectInfo, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, nil, s3Error(err)
+	}
+	return out.Body, &ObjectInfo{
+		Key:          key,
+		Size:         aws.ToInt64(out.ContentLength),
+		ContentType:  aws.ToString(out.ContentType),
+		ETag:         strings.Trim(aws.ToString(out.ETag), `"`),
+		LastModified: aws.ToTime(out.LastModified),
+	}, nil
+}
+
+// Delete 删除对象
+func (s *S3Storage) Delete(ctx context.Context, bucket, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err = s3Error(err); errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// List 列出指定前缀下的全部对象
+func (s *S3Storage) List(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error) {
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	var objects []ObjectInfo
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, s3Error(err)
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				ETag:         strings.Trim(aws.ToString(obj.ETag), `"`),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+	return objects, nil
+}
+
+// Stat 获取对象元数据
+func (s *S3Storage) Stat(ctx context.Context, bucket, key string) (*ObjectInfo, error) {
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, s3Error(err)
+	}
+	return &ObjectInfo{
+		Key:          key,
+		Size:         aws.ToInt64(out.ContentLength),
+		ContentType:  aws.ToString(out.ContentType),
+		ETag:         strings.Trim(aws.ToString(out.ETag), `"`),
+		LastModified: aws.ToTime(out.LastModified),
+	}, nil
+}
+
+// Presign 生成预签名 URL
+func (s *S3Storage) Presign(ctx context.Context, bucket, key string, opts PresignOptions) (string, error) {
+	expires := func(o *s3.PresignOptions) {
+		o.Expires = opts.Expires
+	}
+
+	switch opts.Method {
+	case http.MethodGet:
+		req, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		}, expires)
+		if err != nil {
+			return "", err
+		}
+		return req.URL, nil
+	case http.MethodPut:
+		input := &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		}
+		if opts.ContentType != "" {
+			input.ContentType = aws.String(opts.ContentType)
+		}
+		if opts.ContentLength > 0 {
+			input.ContentLength = aws.Int64(opts.ContentLength)
+		}
+		req, err := s.presign.PresignPutObject(ctx, input, expires)
+		if err != nil {
+			return "", err
+		}
+		return req.URL, nil
+	default:
+		return "", fmt.Errorf("不支持的预签名方法 %s", opts.Method)
+	}
+}
+
+// s3Error 将对象不存在的错误转换为 ErrNotFound
+func s3Error(err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NoSuchKey", "NoSuchBucket", "NotFound":
+			return ErrNotFound
+		}
+	}
+	return err
+}
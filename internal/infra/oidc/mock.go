@@ -0,0 +1,272 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockCodeTTL 模拟提供方授权码有效期
+const mockCodeTTL = 5 * time.Minute
+
+// mockAuthorization 模拟提供方签发的授权码所对应的授权请求
+type mockAuthorization struct {
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	email         string
+	expiresAt     time.Time
+}
+
+// MockProvider 进程内的模拟 OIDC 提供方，用于本地开发与离线验证登录流程
+// 授权端点不展示登录页，直接以 login_hint（邮箱）对应的用户身份授权，未传时使用 mock.user@example.com
+type MockProvider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	kid          string
+	key          *rsa.PrivateKey
+	handler      http.Handler
+
+	mu    sync.Mutex
+	codes map[string]*mockAuthorization
+}
+
+// NewMockProvider 创建模拟提供方，每次启动生成新的 RSA 签名密钥
+func NewMockProvider(issuer, clientID, clientSecret string) (*MockProvider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	kid, err := RandomString(8)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MockProvider{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		kid:          kid,
+		key:          key,
+		codes:        make(map[string]*mockAuthorization),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+m.path("/.well-known/openid-configuration"), m.handleDiscovery)
+	mux.HandleFunc("GET "+m.path("/jwks"), m.handleJWKS)
+	mux.HandleFunc("GET "+m.path("/authorize"), m.handleAuthorize)
+	mux.HandleFunc("POST "+m.path("/token"), m.handleToken)
+	m.handler = mux
+	return m, nil
+}
+
+// BasePath issuer 中的路径部分，用于挂载到 HTTP 路由
+func (m *MockProvider) BasePath() string {
+	return m.path("")
+}
+
+// ServeHTTP 实现 http.Handler
+func (m *MockProvider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.handler.ServeHTTP(w, r)
+}
+
+// Client 返回直接在进程内处理请求的 HTTP 客户端，服务端调用元数据、JWKS、令牌端点时无需经过网络
+func (m *MockProvider) Client() *http.Client {
+	return &http.Client{Transport: inProcessTransport{handler: m.handler}, Timeout: 10 * time.Second}
+}
+
+// path 拼接 issuer 路径
+func (m *MockProvider) path(suffix string) string {
+	base := "/"
+	if u, err := url.Parse(m.issuer); err == nil && u.Path != "" {
+		base = u.Path
+	}
+	return strings.TrimSuffix(base, "/") + suffix
+}
+
+// handleDiscovery 元数据端点
+func (m *MockProvider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                m.issuer,
+		"authorization_endpoint":                m.issuer + "/authorize",
+		"token_endpoint":                        m.issuer + "/token",
+		"jwks_uri":                              m.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+// handleJWKS 公钥端点
+func (m *MockProvider) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": m.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}},
+	})
+}
+
+// handleAuthorize 授权端点：校验请求后直接重定向回客户端并附带授权码
+func (m *MockProvider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI := query.Get("redirect_uri")
+	if query.Get("client_id") != m.clientID || redirectURI == "" {
+		http.Error(w, "invalid client_id or redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "only authorization code flow with PKCE S256 is supported", http.StatusBadRequest)
+		return
+	}
+
+	code, err := RandomString(24)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	email := query.Get("login_hint")
+	if email == "" {
+		email = "mock.user@example.com"
+	}
+
+	m.mu.Lock()
+	for c, authorization := range m.codes {
+		if time.Now().After(authorization.expiresAt) {
+			delete(m.codes, c)
+		}
+	}
+	m.codes[code] = &mockAuthorization{
+		redirectURI:   redirectURI,
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		email:         strings.ToLower(email),
+		expiresAt:     time.Now().Add(mockCodeTTL),
+	}
+	m.mu.Unlock()
+
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	values := target.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	target.RawQuery = values.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+// handleToken 令牌端点：校验客户端凭证、授权码与 PKCE，签发 RS256 ID Token
+func (m *MockProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeTokenError(w, "invalid_request", err.Error())
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != m.clientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(m.clientSecret)) != 1 {
+		writeTokenError(w, "invalid_client", "client authentication failed")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		writeTokenError(w, "unsupported_grant_type", "")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	m.mu.Lock()
+	authorization, found := m.codes[code]
+	delete(m.codes, code)
+	m.mu.Unlock()
+	if !found || time.Now().After(authorization.expiresAt) || authorization.redirectURI != r.PostForm.Get("redirect_uri") {
+		writeTokenError(w, "invalid_grant", "authorization code is invalid or expired")
+		return
+	}
+	if CodeChallengeS256(r.PostForm.Get("code_verifier")) != authorization.codeChallenge {
+		writeTokenError(w, "invalid_grant", "PKCE verification failed")
+		return
+	}
+
+	idToken, err := m.signIDToken(authorization)
+	if err != nil {
+		writeTokenError(w, "server_error", err.Error())
+		return
+	}
+	accessToken, _ := RandomString(24)
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+// signIDToken 签发 ID Token，sub 由邮箱派生以保证同一邮箱多次登录得到同一主体
+func (m *MockProvider) signIDToken(authorization *mockAuthorization) (string, error) {
+	sum := sha256.Sum256([]byte(authorization.email))
+	username, _, _ := strings.Cut(authorization.email, "@")
+	now := time.Now()
+	claims := IDTokenClaims{
+		Nonce:             authorization.nonce,
+		Email:             authorization.email,
+		EmailVerified:     true,
+		Name:              username,
+		PreferredUsername: username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Subject:   "mock|" + hex.EncodeToString(sum[:8]),
+			Audience:  jwt.ClaimStrings{m.clientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = m.kid
+	return token.SignedString(m.key)
+}
+
+// inProcessTransport 将请求直接交给 handler 处理的 RoundTripper
+type inProcessTransport struct {
+	handler http.Handler
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (t inProcessTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	t.handler.ServeHTTP(recorder, req)
+	return recorder.Result(), nil
+}
+
+// writeJSON 输出 JSON 响应
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeTokenError 输出令牌端点错误
+func writeTokenError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code, "error_description": description})
+}
@@ -0,0 +1,62 @@
+package oidc
+
+import (
+	"maps"
+	"proomet/config"
+	"proomet/pkg/utils"
+	"slices"
+)
+
+var (
+	providers = map[string]*Provider{}
+	mocks     []*MockProvider
+)
+
+// InitOIDC 按配置初始化 OIDC 提供方，模拟提供方只在 test 环境下启用
+func InitOIDC(cfg config.OIDCConfig, environment string) {
+	providers = map[string]*Provider{}
+	mocks = nil
+
+	for name, providerCfg := range cfg.Providers {
+		if providerCfg.Issuer == "" || providerCfg.ClientID == "" || providerCfg.RedirectURL == "" {
+			utils.Log.Warnf("OIDC 提供方 %s 缺少 issuer、client_id 或 redirect_url，已忽略", name)
+			continue
+		}
+		if !providerCfg.Mock {
+			providers[name] = NewProvider(name, providerCfg, nil)
+			continue
+		}
+
+		if environment != "test" {
+			utils.Log.Warnf("模拟 OIDC 提供方 %s 只能在 test 环境使用，已忽略", name)
+			continue
+		}
+		mock, err := NewMockProvider(providerCfg.Issuer, providerCfg.ClientID, providerCfg.ClientSecret)
+		if err != nil {
+			utils.Log.Errorf("模拟 OIDC 提供方 %s 初始化失败: %v", name, err)
+			continue
+		}
+		mocks = append(mocks, mock)
+		providers[name] = NewProvider(name, providerCfg, mock.Client())
+	}
+
+	if len(providers) > 0 {
+		utils.Log.Infof("OIDC 初始化成功，提供方: %v", ProviderNames())
+	}
+}
+
+// GetProvider 获取提供方
+func GetProvider(name string) (*Provider, bool) {
+	provider, ok := providers[name]
+	return provider, ok
+}
+
+// ProviderNames 已启用的提供方名称
+func ProviderNames() []string {
+	return slices.Sorted(maps.Keys(providers))
+}
+
+// MockProviders 已启用的模拟提供方，需要挂载到 HTTP 路由上供浏览器访问授权端点
+func MockProviders() []*MockProvider {
+	return mocks
+}
@@ -0,0 +1,37 @@
+package oidc
+
+import (
+	"io"
+	"proomet/config"
+	"proomet/pkg/utils"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestInitOIDCMockEnvironment(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	utils.Log = &utils.Logger{Logger: logger}
+
+	cfg := config.OIDCConfig{Providers: map[string]config.OIDCProviderConfig{
+		"mock": {Issuer: testIssuer, ClientID: "proomet", ClientSecret: "secret", RedirectURL: testRedirectURL, Mock: true},
+	}}
+	tests := []struct {
+		environment string
+		want        bool
+	}{
+		{"development", false},
+		{"production", false},
+		{"test", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.environment, func(t *testing.T) {
+			InitOIDC(cfg, tt.environment)
+			_, ok := GetProvider("mock")
+			if ok != tt.want || (len(MockProviders()) > 0) != tt.want {
+				t.Fatalf("%s 环境下模拟提供方启用状态为 %v，期望 %v", tt.environment, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"proomet/config"
+	"strings"
+	"testing"
+)
+
+const (
+	testIssuer      = "http://localhost:7071/oidc/mock"
+	testRedirectURL = "http://localhost:7071/auth/oidc/mock/callback"
+)
+
+// newTestProvider 创建使用模拟提供方的 Provider
+func newTestProvider(t *testing.T) (*Provider, *MockProvider) {
+	t.Helper()
+	mock, err := NewMockProvider(testIssuer, "proomet", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := NewProvider("mock", config.OIDCProviderConfig{
+		Issuer:       testIssuer,
+		ClientID:     "proomet",
+		ClientSecret: "secret",
+		RedirectURL:  testRedirectURL,
+		Mock:         true,
+	}, mock.Client())
+	return provider, mock
+}
+
+// authorize 走一遍授权端点，返回回调中的授权码与 state
+func authorize(t *testing.T, provider *Provider, mock *MockProvider, state, nonce, verifier, email string) (string, string) {
+	t.Helper()
+	authURL, err := provider.AuthCodeURL(context.Background(), state, nonce, CodeChallengeS256(verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if email != "" {
+		authURL += "&login_hint=" + url.QueryEscape(email)
+	}
+	recorder := httptest.NewRecorder()
+	mock.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, authURL, nil))
+	if recorder.Code != http.StatusFound {
+		t.Fatalf("授权端点返回 %d: %s", recorder.Code, recorder.Body.String())
+	}
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(location.String(), testRedirectURL) {
+		t.Fatalf("回调地址错误: %s", location)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestMockProviderFlow(t *testing.T) {
+	tests := []struct {
+		name     string
+		verifier string // 换取令牌时使用的 code_verifier，为空时使用授权时的值
+		nonce    string // 校验 ID Token 时期望的 nonce，为空时使用授权时的值
+		replay   bool   // 授权码使用两次
+		wantErr  string
+	}{
+		{name: "正常登录"},
+		{name: "PKCE 不匹配", verifier: "another-verifier", wantErr: "PKCE"},
+		{name: "nonce 不匹配", nonce: "another-nonce", wantErr: "nonce"},
+		{name: "授权码重放", replay: true, wantErr: "invalid_grant"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, mock := newTestProvider(t)
+			ctx := context.Background()
+			code, state := authorize(t, provider, mock, "state-1", "nonce-1", "verifier-1", "Alice@Example.com")
+			if state != "state-1" {
+				t.Fatalf("state 未原样返回: %s", state)
+			}
+
+			verifier, nonce := "verifier-1", "nonce-1"
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+			claims, err := provider.Exchange(ctx, code, verifier, nonce)
+			if tt.replay {
+				if err != nil {
+					t.Fatalf("首次交换失败: %v", err)
+				}
+				claims, err = provider.Exchange(ctx, code, verifier, nonce)
+			}
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("期望包含 %q 的错误，实际为 %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.Email != "alice@example.com" || !claims.EmailVerified || claims.Subject == "" {
+				t.Fatalf("ID Token 声明错误: %+v", claims)
+			}
+		})
+	}
+}
+
+func TestMockProviderStableSubject(t *testing.T) {
+	provider, mock := newTestProvider(t)
+	subjects := map[string]string{}
+	for _, email := range []string{"a@example.com", "b@example.com", "a@example.com"} {
+		code, _ := authorize(t, provider, mock, "state", "nonce", "verifier", email)
+		claims, err := provider.Exchange(context.Background(), code, "verifier", "nonce")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if subject, ok := subjects[email]; ok && subject != claims.Subject {
+			t.Fatalf("同一邮箱的 sub 不一致: %s != %s", subject, claims.Subject)
+		}
+		subjects[email] = claims.Subject
+	}
+	if subjects["a@example.com"] == subjects["b@example.com"] {
+		t.Fatal("不同邮箱得到了相同的 sub")
+	}
+}
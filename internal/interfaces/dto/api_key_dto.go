@@ -0,0 +1,28 @@
+package dto
+
+import "time"
+
+// APIKeyIDDto API Key ID路径参数
+type APIKeyIDDto struct {
+	ID uint `uri:"id" binding:"required,min=1"`
+}
+
+// CreateAPIKeyDto 创建 API Key，workspace_id 非空时创建工作区密钥（需要工作区管理员及以上角色）
+// expires_at 为空表示永不过期
+type CreateAPIKeyDto struct {
+	Name        string     `json:"name" binding:"required,min=1,max=64"`
+	Scopes      []string   `json:"scopes" binding:"required,min=1,dive,oneof=prompts:read prompts:write collections:read collections:write tags:read workspaces:read"`
+	WorkspaceID *uint      `json:"workspace_id" binding:"omitempty,min=1"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// UpdateAPIKeyDto 更新 API Key 名称与权限范围，未传字段保持不变
+type UpdateAPIKeyDto struct {
+	Name   *string  `json:"name" binding:"omitempty,min=1,max=64"`
+	Scopes []string `json:"scopes" binding:"omitempty,min=1,dive,oneof=prompts:read prompts:write collections:read collections:write tags:read workspaces:read"`
+}
+
+// ListAPIKeyDto API Key 列表查询，传 workspace_id 时查询该工作区的密钥
+type ListAPIKeyDto struct {
+	WorkspaceID uint `form:"workspace_id" binding:"omitempty,min=1"`
+}
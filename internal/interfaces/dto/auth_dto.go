@@ -2,12 +2,58 @@ package dto
 
 // LoginWithPwdDto 使用密码登陆
 type LoginWithPwdDto struct {
-	Account  string `json:"account" binding:"required,min=3,max=20"`
+	Account  string `json:"account" binding:"required,min=3,max=128"`
 	Password string `json:"password" binding:"required,min=6,max=20"`
 }
 
 // RegisterDto 用户注册
 type RegisterDto struct {
 	Username string `json:"username" binding:"required,min=3,max=20"`
+	Email    string `json:"email" binding:"required,email,max=128"`
 	Password string `json:"password" binding:"required,min=6,max=20"`
 }
+
+// RefreshTokenDto 刷新令牌
+type RefreshTokenDto struct {
+	RefreshToken string `json:"refresh_token" binding:"required,max=128"`
+}
+
+// LoginTwoFactorDto 两步验证登录，code 可以是身份验证器中的验证码或恢复码
+type LoginTwoFactorDto struct {
+	ChallengeToken string `json:"challenge_token" binding:"required,max=128"`
+	Code           string `json:"code" binding:"required,max=32"`
+}
+
+// TwoFactorCodeDto 两步验证操作确认
+type TwoFactorCodeDto struct {
+	Code string `json:"code" binding:"required,max=32"`
+}
+
+// ConfirmEmailDto 确认邮箱验证
+type ConfirmEmailDto struct {
+	Token string `json:"token" binding:"required,max=1024"`
+}
+
+// ForgotPasswordDto 找回密码
+type ForgotPasswordDto struct {
+	Email string `json:"email" binding:"required,email,max=128"`
+}
+
+// ResetPasswordDto 重置密码
+type ResetPasswordDto struct {
+	Token    string `json:"token" binding:"required,max=1024"`
+	Password string `json:"password" binding:"required,min=6,max=20"`
+}
+
+// OIDCProviderDto OIDC 提供方路径参数
+type OIDCProviderDto struct {
+	Provider string `uri:"provider" binding:"required,max=32"`
+}
+
+// OIDCCallbackDto OIDC 授权回调参数，提供方拒绝授权时只返回 error
+type OIDCCallbackDto struct {
+	Code             string `form:"code" binding:"required_without=Error,max=2048"`
+	State            string `form:"state" binding:"required_without=Error,max=64"`
+	Error            string `form:"error" binding:"max=128"`
+	ErrorDescription string `form:"error_description" binding:"max=512"`
+}
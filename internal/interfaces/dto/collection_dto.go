@@ -0,0 +1,29 @@
+package dto
+
+// CollectionIDDto 集合ID路径参数
+type CollectionIDDto struct {
+	ID uint `uri:"id" binding:"required,min=1"`
+}
+
+// CreateCollectionDto 创建集合
+type CreateCollectionDto struct {
+	Name        string `json:"name" binding:"required,min=1,max=64"`
+	Description string `json:"description" binding:"max=255"`
+	ParentID    *uint  `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+// UpdateCollectionDto 更新集合，未传字段保持不变
+type UpdateCollectionDto struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=64"`
+	Description *string `json:"description" binding:"omitempty,max=255"`
+}
+
+// MoveCollectionDto 移动集合，parent_id 为空表示移动到根目录
+type MoveCollectionDto struct {
+	ParentID *uint `json:"parent_id" binding:"omitempty,min=1"`
+}
+
+// CollectionTreeDto 集合子树查询
+type CollectionTreeDto struct {
+	WithPrompts bool `form:"with_prompts"`
+}
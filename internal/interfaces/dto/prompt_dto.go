@@ -0,0 +1,136 @@
+package dto
+
+// PromptIDDto 提示词ID路径参数
+type PromptIDDto struct {
+	ID uint `uri:"id" binding:"required,min=1"`
+}
+
+// PromptVariableDto 模板变量定义
+type PromptVariableDto struct {
+	Name        string `json:"name" binding:"required,max=64,varname"`
+	Type        string `json:"type" binding:"required,oneof=string number integer boolean"`
+	Required    bool   `json:"required"`
+	Default     any    `json:"default"`
+	Enum        []any  `json:"enum"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+// PromptMessageDto 对话消息
+type PromptMessageDto struct {
+	Role    string `json:"role" binding:"required,oneof=system user assistant tool"`
+	Name    string `json:"name" binding:"max=64"`
+	Content string `json:"content" binding:"required,max=65536"`
+}
+
+// CreatePromptDto 创建提示词，对话类提示词使用 messages，正文由消息自动生成
+// workspace_id 未传时使用 X-Workspace-ID 请求头，均为空时创建为个人提示词
+type CreatePromptDto struct {
+	Title        string              `json:"title" binding:"required,min=1,max=128"`
+	Type         string              `json:"type" binding:"omitempty,oneof=text chat"`
+	Body         string              `json:"body" binding:"required_unless=Type chat,max=65536"`
+	Messages     []PromptMessageDto  `json:"messages" binding:"required_if=Type chat,omitempty,max=100,dive"`
+	Description  string              `json:"description" binding:"max=512"`
+	Variables    []PromptVariableDto `json:"variables" binding:"omitempty,dive"`
+	WorkspaceID  *uint               `json:"workspace_id" binding:"omitempty,min=1"`
+	CollectionID *uint               `json:"collection_id" binding:"omitempty,min=1"`
+	Tags         []string            `json:"tags" binding:"omitempty,max=20,dive,min=1,max=32"`
+	Visibility   string              `json:"visibility" binding:"omitempty,oneof=private public"`
+	Message      string              `json:"message" binding:"max=255"`
+}
+
+// UpdatePromptDto 更新提示词，未传字段保持不变（variables 传空数组表示清空）
+type UpdatePromptDto struct {
+	Title       *string             `json:"title" binding:"omitempty,min=1,max=128"`
+	Type        *string             `json:"type" binding:"omitempty,oneof=text chat"`
+	Body        *string             `json:"body" binding:"omitempty,min=1,max=65536"`
+	Messages    []PromptMessageDto  `json:"messages" binding:"omitempty,max=100,dive"`
+	Description *string             `json:"description" binding:"omitempty,max=512"`
+	Variables   []PromptVariableDto `json:"variables" binding:"omitempty,dive"`
+	Visibility  *string             `json:"visibility" binding:"omitempty,oneof=private public"`
+	Message     string              `json:"message" binding:"max=255"`
+}
+
+// ListPromptDto 提示词列表查询
+// workspace_id 未传时使用 X-Workspace-ID 请求头；tags 为逗号分隔的标签名，需全部命中；any_tags 命中任意一个即可
+type ListPromptDto struct {
+	Page         int    `form:"page" binding:"omitempty,min=1"`
+	PageSize     int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Keyword      string `form:"keyword" binding:"max=64"`
+	Scope        string `form:"scope" binding:"omitempty,oneof=mine public all"`
+	WorkspaceID  uint   `form:"workspace_id" binding:"omitempty,min=1"`
+	Tags         string `form:"tags" binding:"max=512"`
+	AnyTags      string `form:"any_tags" binding:"max=512"`
+	CollectionID uint   `form:"collection_id" binding:"omitempty,min=1"`
+	Recursive    bool   `form:"recursive"`
+}
+
+// SearchPromptDto 全文检索提示词，q 支持 websearch 语法（"短语"、or、-排除）
+type SearchPromptDto struct {
+	Q            string `form:"q" binding:"required,min=1,max=128"`
+	Tags         string `form:"tags" binding:"max=512"`
+	CollectionID uint   `form:"collection_id" binding:"omitempty,min=1"`
+	Recursive    bool   `form:"recursive"`
+	OwnerID      uint   `form:"owner_id" binding:"omitempty,min=1"`
+	Cursor       string `form:"cursor" binding:"max=256"`
+	Limit        int    `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// MovePromptDto 移动提示词到集合，collection_id 为空表示移出集合；集合只能存放个人提示词
+type MovePromptDto struct {
+	CollectionID *uint `json:"collection_id" binding:"omitempty,min=1"`
+}
+
+// SetPromptTagsDto 设置提示词标签，不存在的标签会自动创建
+type SetPromptTagsDto struct {
+	Tags []string `json:"tags" binding:"max=20,dive,min=1,max=32"`
+}
+
+// PromptVersionUriDto 提示词版本路径参数
+type PromptVersionUriDto struct {
+	ID      uint `uri:"id" binding:"required,min=1"`
+	Version int  `uri:"version" binding:"required,min=1"`
+}
+
+// ListPromptVersionDto 版本列表查询
+type ListPromptVersionDto struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`
+	PageSize int `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// DiffPromptVersionDto 版本对比查询
+type DiffPromptVersionDto struct {
+	From int    `form:"from" binding:"required,min=1"`
+	To   int    `form:"to" binding:"required,min=1"`
+	Mode string `form:"mode" binding:"omitempty,oneof=line word"`
+}
+
+// RollbackPromptDto 回滚提示词
+type RollbackPromptDto struct {
+	Message string `json:"message" binding:"max=255"`
+}
+
+// RenderPromptDto 渲染提示词
+type RenderPromptDto struct {
+	Variables map[string]any `json:"variables"`
+	Version   int            `json:"version" binding:"omitempty,min=1"`
+}
+
+// ExportPromptDto 以模型厂商的消息格式导出提示词
+type ExportPromptDto struct {
+	Format    string         `json:"format" binding:"required,oneof=openai anthropic"`
+	Variables map[string]any `json:"variables"`
+	Version   int            `json:"version" binding:"omitempty,min=1"`
+}
+
+// AttachmentUriDto 附件路径参数
+type AttachmentUriDto struct {
+	ID           uint `uri:"id" binding:"required,min=1"`
+	AttachmentID uint `uri:"attachment_id" binding:"required,min=1"`
+}
+
+// PresignAttachmentDto 申请预签名上传 URL
+type PresignAttachmentDto struct {
+	FileName    string `json:"file_name" binding:"required,max=255"`
+	ContentType string `json:"content_type" binding:"required,max=128"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+}
@@ -0,0 +1,58 @@
+package dto
+
+// PolicyDto 权限策略(p)：主体在域内对资源执行操作，dom 为空表示全局域 *
+// obj 支持 keyMatch2 通配（如 /prompts/:id、/prompts/*），act 为 HTTP 方法或 *
+type PolicyDto struct {
+	Sub string `json:"sub" binding:"required,max=100"`
+	Dom string `json:"dom" binding:"max=100"`
+	Obj string `json:"obj" binding:"required,max=255"`
+	Act string `json:"act" binding:"required,max=16"`
+}
+
+// ListPolicyDto 权限策略查询，各条件为空时不过滤
+type ListPolicyDto struct {
+	Sub string `form:"sub" binding:"max=100"`
+	Dom string `form:"dom" binding:"max=100"`
+	Obj string `form:"obj" binding:"max=255"`
+}
+
+// GroupingDto 角色授予(g)：主体在域内拥有角色，dom 为空表示全局域 *
+type GroupingDto struct {
+	Sub  string `json:"sub" binding:"required,max=100"`
+	Role string `json:"role" binding:"required,max=100"`
+	Dom  string `json:"dom" binding:"max=100"`
+}
+
+// ListGroupingDto 角色授予查询，各条件为空时不过滤
+type ListGroupingDto struct {
+	Sub  string `form:"sub" binding:"max=100"`
+	Role string `form:"role" binding:"max=100"`
+	Dom  string `form:"dom" binding:"max=100"`
+}
+
+// UserIDDto 用户ID路径参数
+type UserIDDto struct {
+	UserID uint `uri:"user_id" binding:"required,min=1"`
+}
+
+// UserRoleGrantDto 为用户授予或撤销角色，dom 为空表示全局域 *，不能是工作区域 workspace:<id>
+type UserRoleGrantDto struct {
+	Role string `json:"role" binding:"required,max=100"`
+	Dom  string `json:"dom" binding:"max=100"`
+}
+
+// SetUserRoleDto 设置用户的全局角色（写入用户表，立即生效）
+type SetUserRoleDto struct {
+	Role string `json:"role" binding:"required,oneof=admin member guest"`
+}
+
+// EnforceDto 权限决策试运行，user_id 与 sub 二选一：
+// 传 user_id 时按请求鉴权流程依次校验用户主体与其全局角色，传 sub 时直接校验该主体
+// dom 为空表示未指定工作区的请求，即个人域 personal
+type EnforceDto struct {
+	UserID uint   `json:"user_id" binding:"required_without=Sub,omitempty,min=1"`
+	Sub    string `json:"sub" binding:"required_without=UserID,omitempty,max=100"`
+	Dom    string `json:"dom" binding:"max=100"`
+	Obj    string `json:"obj" binding:"required,max=255"`
+	Act    string `json:"act" binding:"required,max=16"`
+}
@@ -0,0 +1,11 @@
+package dto
+
+// TagIDDto 标签ID路径参数
+type TagIDDto struct {
+	ID uint `uri:"id" binding:"required,min=1"`
+}
+
+// ListTagDto 标签列表查询
+type ListTagDto struct {
+	Keyword string `form:"keyword" binding:"max=32"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+// UpdateProfileDto 更新个人资料，未传字段保持不变；修改邮箱后需要重新验证
+type UpdateProfileDto struct {
+	Nickname *string `json:"nickname" binding:"omitempty,max=64"`
+	Email    *string `json:"email" binding:"omitempty,email,max=128"`
+}
+
+// ChangePasswordDto 修改密码，通过第三方登录创建、尚未设置密码的账号可以不传旧密码
+type ChangePasswordDto struct {
+	OldPassword string `json:"old_password" binding:"max=20"`
+	NewPassword string `json:"new_password" binding:"required,min=6,max=20"`
+}
+
+// ListUserDto 用户列表查询，keyword 匹配用户名、昵称与邮箱
+type ListUserDto struct {
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Keyword  string `form:"keyword" binding:"max=64"`
+	Role     string `form:"role" binding:"omitempty,oneof=admin member guest"`
+	Status   int    `form:"status" binding:"omitempty,oneof=1 2"`
+}
+
+// UpdateUserDto 管理员更新用户信息，未传字段保持不变
+type UpdateUserDto struct {
+	Nickname *string `json:"nickname" binding:"omitempty,max=64"`
+	Email    *string `json:"email" binding:"omitempty,email,max=128"`
+	Role     *string `json:"role" binding:"omitempty,oneof=admin member guest"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+// WorkspaceIDDto 工作区ID路径参数
+type WorkspaceIDDto struct {
+	WorkspaceID uint `uri:"workspace_id" binding:"required,min=1"`
+}
+
+// WorkspaceMemberUriDto 工作区成员路径参数
+type WorkspaceMemberUriDto struct {
+	WorkspaceID uint `uri:"workspace_id" binding:"required,min=1"`
+	UserID      uint `uri:"user_id" binding:"required,min=1"`
+}
+
+// CreateWorkspaceDto 创建工作区
+type CreateWorkspaceDto struct {
+	Name        string `json:"name" binding:"required,min=1,max=64"`
+	Slug        string `json:"slug" binding:"required,min=3,max=64,slug"`
+	Description string `json:"description" binding:"max=255"`
+}
+
+// UpdateWorkspaceDto 更新工作区，未传字段保持不变
+type UpdateWorkspaceDto struct {
+	Name        *string `json:"name" binding:"omitempty,min=1,max=64"`
+	Description *string `json:"description" binding:"omitempty,max=255"`
+}
+
+// AddWorkspaceMemberDto 添加工作区成员，user_id 与 username 二选一
+type AddWorkspaceMemberDto struct {
+	UserID   uint   `json:"user_id" binding:"required_without=Username,omitempty,min=1"`
+	Username string `json:"username" binding:"required_without=UserID,omitempty,max=64"`
+	Role     string `json:"role" binding:"required,oneof=admin editor viewer"`
+}
+
+// UpdateWorkspaceMemberDto 修改工作区成员角色
+type UpdateWorkspaceMemberDto struct {
+	Role string `json:"role" binding:"required,oneof=admin editor viewer"`
+}
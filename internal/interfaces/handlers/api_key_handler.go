@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"proomet/internal/application/services"
+	"proomet/internal/interfaces/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler API Key endpoint
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+}
+
+func NewAPIKeyHandler() *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: services.APIKeyService{},
+	}
+}
+
+// Create godoc
+// @Summary 创建 API Key
+// @Description 返回的 key 为完整密钥，只在创建时返回一次，请妥善保存；调用时通过 X-API-Key 请求头或 Authorization: Bearer pk_... 传递
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateAPIKeyDto true "创建请求"
+// @Success 200 {object} res.Response{data=vo.APIKeyCreatedVO} "创建成功"
+// @Router /api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req dto.CreateAPIKeyDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.apiKeyService.Create(CurrentUser(c), &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// List godoc
+// @Summary 查询 API Key
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param query query dto.ListAPIKeyDto false "查询条件"
+// @Success 200 {object} res.Response{data=[]vo.APIKeyVO} "查询成功"
+// @Router /api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	var req dto.ListAPIKeyDto
+	if err := BindQuery(c, &req); err != nil {
+		return
+	}
+	vo, err := h.apiKeyService.List(CurrentUser(c), &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Get godoc
+// @Summary 获取 API Key 详情
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API Key ID"
+// @Success 200 {object} res.Response{data=vo.APIKeyVO} "查询成功"
+// @Router /api-keys/{id} [get]
+func (h *APIKeyHandler) Get(c *gin.Context) {
+	var uri dto.APIKeyIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.apiKeyService.Get(CurrentUser(c), uri.ID)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Update godoc
+// @Summary 更新 API Key
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API Key ID"
+// @Param request body dto.UpdateAPIKeyDto true "更新请求"
+// @Success 200 {object} res.Response{data=vo.APIKeyVO} "更新成功"
+// @Router /api-keys/{id} [put]
+func (h *APIKeyHandler) Update(c *gin.Context) {
+	var uri dto.APIKeyIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.UpdateAPIKeyDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.apiKeyService.Update(CurrentUser(c), uri.ID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Delete godoc
+// @Summary 吊销 API Key
+// @Tags API Key
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "API Key ID"
+// @Success 200 {object} res.Response{data=bool} "吊销成功"
+// @Router /api-keys/{id} [delete]
+func (h *APIKeyHandler) Delete(c *gin.Context) {
+	var uri dto.APIKeyIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	if err := h.apiKeyService.Delete(CurrentUser(c), uri.ID); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
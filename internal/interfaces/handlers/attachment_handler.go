@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"proomet/config"
+	"proomet/internal/application/services"
+	"proomet/internal/interfaces/dto"
+	"proomet/pkg/utils/res"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// attachmentFormOverhead multipart 请求中文件以外部分允许的大小
+const attachmentFormOverhead = 1 << 20
+
+// AttachmentHandler 提示词附件endpoint
+type AttachmentHandler struct {
+	attachmentService services.AttachmentService
+}
+
+func NewAttachmentHandler() *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: services.AttachmentService{},
+	}
+}
+
+// List godoc
+// @Summary 查询提示词附件
+// @Tags 提示词附件
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Success 200 {object} res.Response{data=[]vo.AttachmentVO} "查询成功"
+// @Router /prompts/{id}/attachments [get]
+func (h *AttachmentHandler) List(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.attachmentService.List(CurrentUser(c), uri.ID)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Upload godoc
+// @Summary 上传提示词附件
+// @Description 通过服务端上传附件，文件类型按内容识别，大小与类型限制见 attachment 配置
+// @Tags 提示词附件
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param file formData file true "附件文件"
+// @Success 200 {object} res.Response{data=vo.AttachmentVO} "上传成功"
+// @Router /prompts/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	maxSize := config.Get().Attachment.MaxSize
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+attachmentFormOverhead)
+	file, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			Error(c, res.ErrAttachmentTooLarge.Msgf("附件大小不能超过 %d KB", maxSize>>10))
+			return
+		}
+		Error(c, res.ErrInvalidParam.Msg("请选择要上传的文件"))
+		return
+	}
+	vo, err := h.attachmentService.Upload(c.Request.Context(), CurrentUser(c), uri.ID, file)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Presign godoc
+// @Summary 申请附件直传地址
+// @Description 返回预签名上传 URL，客户端使用返回的 method 与 headers 直接上传文件到对象存储，完成后调用 complete 接口
+// @Tags 提示词附件
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param request body dto.PresignAttachmentDto true "文件信息"
+// @Success 200 {object} res.Response{data=vo.PresignAttachmentVO} "申请成功"
+// @Router /prompts/{id}/attachments/presign [post]
+func (h *AttachmentHandler) Presign(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.PresignAttachmentDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.attachmentService.Presign(c.Request.Context(), CurrentUser(c), uri.ID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Complete godoc
+// @Summary 确认附件直传完成
+// @Description 校验已上传文件的大小与内容类型，不通过时删除文件
+// @Tags 提示词附件
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param attachment_id path int true "附件ID"
+// @Success 200 {object} res.Response{data=vo.AttachmentVO} "上传完成"
+// @Router /prompts/{id}/attachments/{attachment_id}/complete [post]
+func (h *AttachmentHandler) Complete(c *gin.Context) {
+	var uri dto.AttachmentUriDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.attachmentService.Complete(c.Request.Context(), CurrentUser(c), uri.ID, uri.AttachmentID)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Get godoc
+// @Summary 获取附件详情
+// @Description 返回附件信息与短期有效的预签名下载地址
+// @Tags 提示词附件
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param attachment_id path int true "附件ID"
+// @Success 200 {object} res.Response{data=vo.AttachmentVO} "查询成功"
+// @Router /prompts/{id}/attachments/{attachment_id} [get]
+func (h *AttachmentHandler) Get(c *gin.Context) {
+	var uri dto.AttachmentUriDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.attachmentService.Get(c.Request.Context(), CurrentUser(c), uri.ID, uri.AttachmentID)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Download godoc
+// @Summary 下载附件
+// @Description 图片以内联方式返回，其他类型作为附件下载
+// @Tags 提示词附件
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param attachment_id path int true "附件ID"
+// @Success 200 {file} file "附件内容"
+// @Router /prompts/{id}/attachments/{attachment_id}/download [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	var uri dto.AttachmentUriDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	body, attachment, err := h.attachmentService.Download(c.Request.Context(), CurrentUser(c), uri.ID, uri.AttachmentID)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	defer body.Close()
+
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, body, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, max-age=300",
+	})
+}
+
+// Delete godoc
+// @Summary 删除附件
+// @Tags 提示词附件
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param attachment_id path int true "附件ID"
+// @Success 200 {object} res.Response{data=bool} "删除成功"
+// @Router /prompts/{id}/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	var uri dto.AttachmentUriDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	if err := h.attachmentService.Delete(CurrentUser(c), uri.ID, uri.AttachmentID); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
@@ -9,17 +9,22 @@ import (
 
 // AuthHandler 认证endpoint
 type AuthHandler struct {
-	authService services.AuthService
+	authService    services.AuthService
+	oidcService    services.OIDCService
+	accountService services.AccountService
 }
 
 func NewAuthHandler() *AuthHandler {
 	return &AuthHandler{
-		authService: services.AuthService{},
+		authService:    services.AuthService{},
+		oidcService:    services.OIDCService{},
+		accountService: services.AccountService{},
 	}
 }
 
 // LoginWithPwd godoc
 // @Summary 使用用户名密码登陆
+// @Description 账号启用两步验证时返回 challenge_token，需要继续调用 /auth/sign/2fa；同一账号或 IP 连续失败过多会被暂时锁定
 // @Tags 认证
 // @Accept json
 // @Produce json
@@ -31,15 +36,39 @@ func (h *AuthHandler) LoginWithPwd(c *gin.Context) {
 	if err := Bind(c, &req); err != nil {
 		return
 	}
-	vo, err := h.authService.LoginWithPwd(&req)
+	vo, err := h.authService.LoginWithPwd(&req, c.ClientIP())
 	if err != nil {
 		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// LoginTwoFactor godoc
+// @Summary 两步验证登录
+// @Description 密码登录返回 two_factor_required 时，使用 challenge_token 与身份验证器验证码（或恢复码）完成登录；验证失败与密码错误一起计入登录锁定
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body dto.LoginTwoFactorDto true "两步验证请求"
+// @Success 200 {object} res.Response{data=vo.AuthLoginVO} "登录成功"
+// @Router /auth/sign/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req dto.LoginTwoFactorDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.authService.LoginTwoFactor(&req, c.ClientIP())
+	if err != nil {
+		Error(c, err)
+		return
 	}
 	Success(c, vo)
 }
 
 // Register godoc
 // @Summary 用户注册
+// @Description 注册成功后会向邮箱发送验证邮件
 // @Tags 认证
 // @Accept json
 // @Produce json
@@ -58,3 +87,181 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 	Success(c, vo)
 }
+
+// Refresh godoc
+// @Summary 刷新访问令牌
+// @Description 使用刷新令牌换取新的访问令牌与刷新令牌，旧刷新令牌立即失效；重复使用已失效的刷新令牌会吊销整个登录会话
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenDto true "刷新请求"
+// @Success 200 {object} res.Response{data=vo.AuthLoginVO} "刷新成功"
+// @Router /auth/token/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req dto.RefreshTokenDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.authService.Refresh(&req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Logout godoc
+// @Summary 退出登录
+// @Description 吊销当前访问令牌及其登录会话下的全部刷新令牌
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} res.Response{data=bool} "退出成功"
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.authService.Logout(CurrentClaims(c)); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// OIDCProviders godoc
+// @Summary 获取可用的第三方登录提供方
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Success 200 {object} res.Response{data=[]string} "查询成功"
+// @Router /auth/oidc/providers [get]
+func (h *AuthHandler) OIDCProviders(c *gin.Context) {
+	Success(c, h.oidcService.Providers())
+}
+
+// OIDCAuthorize godoc
+// @Summary 发起第三方登录
+// @Description 生成 state、nonce 与 PKCE 参数，返回提供方授权地址，客户端需要将浏览器重定向到该地址
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param provider path string true "提供方名称"
+// @Success 200 {object} res.Response{data=vo.OIDCAuthorizeVO} "生成成功"
+// @Router /auth/oidc/{provider}/authorize [get]
+func (h *AuthHandler) OIDCAuthorize(c *gin.Context) {
+	var uri dto.OIDCProviderDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.oidcService.Authorize(c.Request.Context(), uri.Provider)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// OIDCCallback godoc
+// @Summary 第三方登录回调
+// @Description 校验 state 与 ID Token 后关联或创建用户，并签发访问令牌与刷新令牌
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param provider path string true "提供方名称"
+// @Param query query dto.OIDCCallbackDto true "回调参数"
+// @Success 200 {object} res.Response{data=vo.AuthLoginVO} "登录成功"
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	var uri dto.OIDCProviderDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.OIDCCallbackDto
+	if err := BindQuery(c, &req); err != nil {
+		return
+	}
+	vo, err := h.oidcService.Callback(c.Request.Context(), uri.Provider, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// RequestEmailVerification godoc
+// @Summary 发送邮箱验证邮件
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} res.Response{data=bool} "发送成功"
+// @Router /auth/email/verification [post]
+func (h *AuthHandler) RequestEmailVerification(c *gin.Context) {
+	if err := h.accountService.RequestEmailVerification(CurrentUser(c)); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// ConfirmEmail godoc
+// @Summary 确认邮箱验证
+// @Description 使用验证邮件中的令牌完成验证，令牌只能使用一次
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body dto.ConfirmEmailDto true "验证请求"
+// @Success 200 {object} res.Response{data=bool} "验证成功"
+// @Router /auth/email/verify [post]
+func (h *AuthHandler) ConfirmEmail(c *gin.Context) {
+	var req dto.ConfirmEmailDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	if err := h.accountService.ConfirmEmail(&req); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// ForgotPassword godoc
+// @Summary 找回密码
+// @Description 向邮箱发送重置密码邮件，无论邮箱是否已注册都返回成功
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordDto true "找回密码请求"
+// @Success 200 {object} res.Response{data=bool} "请求成功"
+// @Router /auth/password/forgot [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	if err := h.accountService.ForgotPassword(&req); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// ResetPassword godoc
+// @Summary 重置密码
+// @Description 使用重置邮件中的令牌设置新密码，成功后该用户的全部登录会话失效
+// @Tags 认证
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordDto true "重置密码请求"
+// @Success 200 {object} res.Response{data=bool} "重置成功"
+// @Router /auth/password/reset [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	if err := h.accountService.ResetPassword(&req); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
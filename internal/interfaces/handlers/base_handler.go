@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"proomet/internal/domain/models"
 	"proomet/internal/interfaces/validators"
 	"proomet/internal/middleware"
+	"proomet/pkg/utils/jwt"
 	"proomet/pkg/utils/res"
 
 	"github.com/gin-gonic/gin"
@@ -66,3 +68,51 @@ func Bind(c *gin.Context, req any) error {
 	}
 	return nil
 }
+
+// BindUri 绑定并验证路径参数
+func BindUri(c *gin.Context, req any) error {
+	if err := c.ShouldBindUri(req); err != nil {
+		res.ErrInvalidParam.ThrowMsg(c, validators.GetValidationError(err))
+		return err
+	}
+	return nil
+}
+
+// BindQuery 绑定并验证查询参数
+func BindQuery(c *gin.Context, req any) error {
+	if err := c.ShouldBindQuery(req); err != nil {
+		res.ErrInvalidParam.ThrowMsg(c, validators.GetValidationError(err))
+		return err
+	}
+	return nil
+}
+
+// CurrentUser 获取当前登录用户（由 Authenticate 中间件注入）
+func CurrentUser(c *gin.Context) models.JwtUser {
+	if userRaw, exists := c.Get("currentUser"); exists {
+		if user, ok := userRaw.(models.JwtUser); ok {
+			return user
+		}
+	}
+	return models.JwtUser{Role: models.RoleGuest, Username: "guest"}
+}
+
+// CurrentWorkspaceID 获取当前请求所属的工作区ID（由 Authorize 中间件解析），未指定时返回 nil
+func CurrentWorkspaceID(c *gin.Context) *uint {
+	if raw, exists := c.Get("workspaceID"); exists {
+		if workspaceID, ok := raw.(uint); ok && workspaceID != 0 {
+			return &workspaceID
+		}
+	}
+	return nil
+}
+
+// CurrentClaims 获取当前请求的令牌声明（由 Authenticate 中间件注入），未登录时返回 nil
+func CurrentClaims(c *gin.Context) *jwt.Claims {
+	if raw, exists := c.Get("tokenClaims"); exists {
+		if claims, ok := raw.(*jwt.Claims); ok {
+			return claims
+		}
+	}
+	return nil
+}
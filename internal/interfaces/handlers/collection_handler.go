@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"proomet/internal/application/services"
+	"proomet/internal/interfaces/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CollectionHandler 集合endpoint
+type CollectionHandler struct {
+	collectionService services.CollectionService
+}
+
+func NewCollectionHandler() *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: services.CollectionService{},
+	}
+}
+
+// Create godoc
+// @Summary 创建集合
+// @Tags 集合
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateCollectionDto true "创建请求"
+// @Success 200 {object} res.Response{data=vo.CollectionVO} "创建成功"
+// @Router /collections [post]
+func (h *CollectionHandler) Create(c *gin.Context) {
+	var req dto.CreateCollectionDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.collectionService.Create(CurrentUser(c), &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// List godoc
+// @Summary 查询我的集合
+// @Tags 集合
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} res.Response{data=[]vo.CollectionVO} "查询成功"
+// @Router /collections [get]
+func (h *CollectionHandler) List(c *gin.Context) {
+	vo, err := h.collectionService.List(CurrentUser(c))
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Get godoc
+// @Summary 获取集合详情
+// @Tags 集合
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "集合ID"
+// @Success 200 {object} res.Response{data=vo.CollectionVO} "查询成功"
+// @Router /collections/{id} [get]
+func (h *CollectionHandler) Get(c *gin.Context) {
+	var uri dto.CollectionIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.collectionService.Get(CurrentUser(c), uri.ID)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Update godoc
+// @Summary 更新集合
+// @Tags 集合
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "集合ID"
+// @Param request body dto.UpdateCollectionDto true "更新请求"
+// @Success 200 {object} res.Response{data=vo.CollectionVO} "更新成功"
+// @Router /collections/{id} [put]
+func (h *CollectionHandler) Update(c *gin.Context) {
+	var uri dto.CollectionIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.UpdateCollectionDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.collectionService.Update(CurrentUser(c), uri.ID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Move godoc
+// @Summary 移动集合
+// @Tags 集合
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "集合ID"
+// @Param request body dto.MoveCollectionDto true "移动请求"
+// @Success 200 {object} res.Response{data=vo.CollectionVO} "移动成功"
+// @Router /collections/{id}/move [put]
+func (h *CollectionHandler) Move(c *gin.Context) {
+	var uri dto.CollectionIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.MoveCollectionDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.collectionService.Move(CurrentUser(c), uri.ID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Delete godoc
+// @Summary 删除集合
+// @Description 子集合与提示词会移动到被删除集合的父级
+// @Tags 集合
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "集合ID"
+// @Success 200 {object} res.Response{data=bool} "删除成功"
+// @Router /collections/{id} [delete]
+func (h *CollectionHandler) Delete(c *gin.Context) {
+	var uri dto.CollectionIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	if err := h.collectionService.Delete(CurrentUser(c), uri.ID); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// Tree godoc
+// @Summary 获取集合子树
+// @Tags 集合
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "集合ID"
+// @Param query query dto.CollectionTreeDto false "查询参数"
+// @Success 200 {object} res.Response{data=vo.CollectionTreeVO} "查询成功"
+// @Router /collections/{id}/tree [get]
+func (h *CollectionHandler) Tree(c *gin.Context) {
+	var uri dto.CollectionIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.CollectionTreeDto
+	if err := BindQuery(c, &req); err != nil {
+		return
+	}
+	vo, err := h.collectionService.Tree(CurrentUser(c), uri.ID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"proomet/internal/application/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigHandler 运行时配置endpoint，仅管理员可用
+type ConfigHandler struct {
+	configService services.ConfigService
+}
+
+func NewConfigHandler() *ConfigHandler {
+	return &ConfigHandler{
+		configService: services.ConfigService{},
+	}
+}
+
+// Current godoc
+// @Summary 查询当前生效的配置
+// @Description 返回配置版本号、加载时间与脱敏后的配置内容，配置文件修改并校验通过后版本号递增
+// @Tags 系统管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} res.Response{data=vo.ConfigVO} "查询成功"
+// @Router /admin/config [get]
+func (h *ConfigHandler) Current(c *gin.Context) {
+	Success(c, h.configService.Current())
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"proomet/internal/application/services"
+	"proomet/internal/interfaces/vo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler 存活与就绪探针，供负载均衡与容器编排使用，直接返回 JSON，不使用统一响应结构
+type HealthHandler struct {
+	healthService services.HealthService
+}
+
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{
+		healthService: services.HealthService{},
+	}
+}
+
+// Liveness godoc
+// @Summary 存活检查
+// @Description 进程能够处理请求即返回 200，不检查外部依赖
+// @Tags 健康检查
+// @Produce json
+// @Success 200 {object} vo.HealthVO "存活"
+// @Router /healthz [get]
+func (h *HealthHandler) Liveness(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, h.healthService.Liveness())
+}
+
+// Readiness godoc
+// @Summary 就绪检查
+// @Description 检查数据库、对象存储与权限策略，返回各组件的状态与耗时，任一组件不可用时返回 503
+// @Tags 健康检查
+// @Produce json
+// @Success 200 {object} vo.HealthVO "就绪"
+// @Failure 503 {object} vo.HealthVO "存在不可用的组件"
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(c *gin.Context) {
+	result := h.healthService.Readiness(c.Request.Context())
+	status := http.StatusOK
+	if result.Status != vo.HealthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, result)
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"proomet/internal/application/services"
+	"proomet/internal/interfaces/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromptHandler 提示词endpoint
+type PromptHandler struct {
+	promptService       services.PromptService
+	promptRenderService services.PromptRenderService
+	promptSearchService services.PromptSearchService
+}
+
+func NewPromptHandler() *PromptHandler {
+	return &PromptHandler{
+		promptService:       services.PromptService{},
+		promptRenderService: services.PromptRenderService{},
+		promptSearchService: services.PromptSearchService{},
+	}
+}
+
+// Create godoc
+// @Summary 创建提示词
+// @Tags 提示词
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreatePromptDto true "创建请求"
+// @Success 200 {object} res.Response{data=vo.PromptVO} "创建成功"
+// @Router /prompts [post]
+func (h *PromptHandler) Create(c *gin.Context) {
+	var req dto.CreatePromptDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	if req.WorkspaceID == nil {
+		req.WorkspaceID = CurrentWorkspaceID(c)
+	}
+	vo, err := h.promptService.Create(CurrentUser(c), &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// List godoc
+// @Summary 分页查询提示词
+// @Tags 提示词
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param query query dto.ListPromptDto false "查询条件"
+// @Success 200 {object} res.Response{data=vo.PageVO[vo.PromptVO]} "查询成功"
+// @Router /prompts [get]
+func (h *PromptHandler) List(c *gin.Context) {
+	var req dto.ListPromptDto
+	if err := BindQuery(c, &req); err != nil {
+		return
+	}
+	if workspaceID := CurrentWorkspaceID(c); req.WorkspaceID == 0 && workspaceID != nil {
+		req.WorkspaceID = *workspaceID
+	}
+	vo, err := h.promptService.List(CurrentUser(c), &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Search godoc
+// @Summary 全文检索提示词
+// @Description 基于 PostgreSQL tsvector 检索标题、描述与正文，按相关度排序，使用 next_cursor 获取下一页
+// @Tags 提示词
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param query query dto.SearchPromptDto true "检索条件"
+// @Success 200 {object} res.Response{data=vo.CursorPageVO[vo.PromptSearchHitVO]} "检索成功"
+// @Router /prompts/search [get]
+func (h *PromptHandler) Search(c *gin.Context) {
+	var req dto.SearchPromptDto
+	if err := BindQuery(c, &req); err != nil {
+		return
+	}
+	vo, err := h.promptSearchService.Search(CurrentUser(c), &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Get godoc
+// @Summary 获取提示词详情
+// @Tags 提示词
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Success 200 {object} res.Response{data=vo.PromptVO} "查询成功"
+// @Router /prompts/{id} [get]
+func (h *PromptHandler) Get(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.promptService.Get(CurrentUser(c), uri.ID)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Update godoc
+// @Summary 更新提示词
+// @Tags 提示词
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param request body dto.UpdatePromptDto true "更新请求"
+// @Success 200 {object} res.Response{data=vo.PromptVO} "更新成功"
+// @Router /prompts/{id} [put]
+func (h *PromptHandler) Update(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.UpdatePromptDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.promptService.Update(CurrentUser(c), uri.ID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Delete godoc
+// @Summary 删除提示词
+// @Tags 提示词
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Success 200 {object} res.Response{data=bool} "删除成功"
+// @Router /prompts/{id} [delete]
+func (h *PromptHandler) Delete(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	if err := h.promptService.Delete(CurrentUser(c), uri.ID); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// Render godoc
+// @Summary 渲染提示词
+// @Description 使用传入的变量值替换正文中的 {{name}} 占位符，缺失或不合法的变量会以参数错误返回
+// @Tags 提示词
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param request body dto.RenderPromptDto true "渲染请求"
+// @Success 200 {object} res.Response{data=vo.RenderPromptVO} "渲染成功"
+// @Router /prompts/{id}/render [post]
+func (h *PromptHandler) Render(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.RenderPromptDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.promptRenderService.Render(CurrentUser(c), uri.ID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Export godoc
+// @Summary 导出提示词为模型消息格式
+// @Description 渲染提示词后按 OpenAI Chat Completions 或 Anthropic Messages API 的消息结构返回
+// @Tags 提示词
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param request body dto.ExportPromptDto true "导出请求"
+// @Success 200 {object} res.Response{data=object} "导出成功，data 为 vo.OpenAIExportVO 或 vo.AnthropicExportVO"
+// @Router /prompts/{id}/export [post]
+func (h *PromptHandler) Export(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.ExportPromptDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.promptRenderService.Export(CurrentUser(c), uri.ID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Move godoc
+// @Summary 移动提示词到集合
+// @Tags 提示词
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param request body dto.MovePromptDto true "移动请求"
+// @Success 200 {object} res.Response{data=vo.PromptVO} "移动成功"
+// @Router /prompts/{id}/collection [put]
+func (h *PromptHandler) Move(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.MovePromptDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.promptService.Move(CurrentUser(c), uri.ID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// SetTags godoc
+// @Summary 设置提示词标签
+// @Tags 提示词
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param request body dto.SetPromptTagsDto true "标签列表"
+// @Success 200 {object} res.Response{data=vo.PromptVO} "设置成功"
+// @Router /prompts/{id}/tags [put]
+func (h *PromptHandler) SetTags(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.SetPromptTagsDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.promptService.SetTags(CurrentUser(c), uri.ID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
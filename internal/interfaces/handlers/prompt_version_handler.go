@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+	"proomet/internal/application/services"
+	"proomet/internal/interfaces/dto"
+	"proomet/pkg/utils/res"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PromptVersionHandler 提示词版本endpoint
+type PromptVersionHandler struct {
+	promptVersionService services.PromptVersionService
+}
+
+func NewPromptVersionHandler() *PromptVersionHandler {
+	return &PromptVersionHandler{
+		promptVersionService: services.PromptVersionService{},
+	}
+}
+
+// List godoc
+// @Summary 查询提示词版本历史
+// @Tags 提示词版本
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param query query dto.ListPromptVersionDto false "分页参数"
+// @Success 200 {object} res.Response{data=vo.PageVO[vo.PromptVersionVO]} "查询成功"
+// @Router /prompts/{id}/versions [get]
+func (h *PromptVersionHandler) List(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.ListPromptVersionDto
+	if err := BindQuery(c, &req); err != nil {
+		return
+	}
+	vo, err := h.promptVersionService.List(CurrentUser(c), uri.ID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Get godoc
+// @Summary 获取提示词指定版本
+// @Tags 提示词版本
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param version path int true "版本号"
+// @Success 200 {object} res.Response{data=vo.PromptVersionVO} "查询成功"
+// @Router /prompts/{id}/versions/{version} [get]
+func (h *PromptVersionHandler) Get(c *gin.Context) {
+	var uri dto.PromptVersionUriDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.promptVersionService.Get(CurrentUser(c), uri.ID, uri.Version)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Diff godoc
+// @Summary 对比提示词两个版本
+// @Tags 提示词版本
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param query query dto.DiffPromptVersionDto true "对比参数"
+// @Success 200 {object} res.Response{data=vo.PromptDiffVO} "对比成功"
+// @Failure 422 {object} res.BusinessError "版本差异过大"
+// @Router /prompts/{id}/diff [get]
+func (h *PromptVersionHandler) Diff(c *gin.Context) {
+	var uri dto.PromptIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.DiffPromptVersionDto
+	if err := BindQuery(c, &req); err != nil {
+		return
+	}
+	vo, err := h.promptVersionService.Diff(CurrentUser(c), uri.ID, &req)
+	if err == res.ErrPromptDiffTooLarge {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, err)
+		return
+	}
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Rollback godoc
+// @Summary 回滚提示词到指定版本
+// @Description 以历史版本的内容创建一个新版本，历史记录本身不会被修改
+// @Tags 提示词版本
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "提示词ID"
+// @Param version path int true "版本号"
+// @Param request body dto.RollbackPromptDto false "回滚请求"
+// @Success 200 {object} res.Response{data=vo.PromptVO} "回滚成功"
+// @Router /prompts/{id}/versions/{version}/rollback [post]
+func (h *PromptVersionHandler) Rollback(c *gin.Context) {
+	var uri dto.PromptVersionUriDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.RollbackPromptDto
+	if c.Request.ContentLength > 0 {
+		if err := Bind(c, &req); err != nil {
+			return
+		}
+	}
+	vo, err := h.promptVersionService.Rollback(CurrentUser(c), uri.ID, uri.Version, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
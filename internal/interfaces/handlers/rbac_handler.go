@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"proomet/internal/application/services"
+	"proomet/internal/interfaces/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RbacHandler 权限策略管理endpoint，仅管理员可用
+type RbacHandler struct {
+	rbacService services.RbacService
+}
+
+func NewRbacHandler() *RbacHandler {
+	return &RbacHandler{
+		rbacService: services.RbacService{},
+	}
+}
+
+// ListPolicies godoc
+// @Summary 查询权限策略
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param query query dto.ListPolicyDto false "查询条件"
+// @Success 200 {object} res.Response{data=[]vo.PolicyVO} "查询成功"
+// @Router /admin/rbac/policies [get]
+func (h *RbacHandler) ListPolicies(c *gin.Context) {
+	var req dto.ListPolicyDto
+	if err := BindQuery(c, &req); err != nil {
+		return
+	}
+	vo, err := h.rbacService.ListPolicies(&req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// AddPolicy godoc
+// @Summary 添加权限策略
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.PolicyDto true "策略"
+// @Success 200 {object} res.Response{data=vo.PolicyVO} "添加成功"
+// @Router /admin/rbac/policies [post]
+func (h *RbacHandler) AddPolicy(c *gin.Context) {
+	var req dto.PolicyDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.rbacService.AddPolicy(&req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// RemovePolicy godoc
+// @Summary 删除权限策略
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.PolicyDto true "策略"
+// @Success 200 {object} res.Response{data=bool} "删除成功"
+// @Router /admin/rbac/policies [delete]
+func (h *RbacHandler) RemovePolicy(c *gin.Context) {
+	var req dto.PolicyDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	if err := h.rbacService.RemovePolicy(&req); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// ListGroupings godoc
+// @Summary 查询角色授予
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param query query dto.ListGroupingDto false "查询条件"
+// @Success 200 {object} res.Response{data=[]vo.GroupingVO} "查询成功"
+// @Router /admin/rbac/groupings [get]
+func (h *RbacHandler) ListGroupings(c *gin.Context) {
+	var req dto.ListGroupingDto
+	if err := BindQuery(c, &req); err != nil {
+		return
+	}
+	vo, err := h.rbacService.ListGroupings(&req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// AddGrouping godoc
+// @Summary 添加角色授予
+// @Description 角色也可以授予另一个角色以形成继承关系
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.GroupingDto true "角色授予"
+// @Success 200 {object} res.Response{data=vo.GroupingVO} "添加成功"
+// @Router /admin/rbac/groupings [post]
+func (h *RbacHandler) AddGrouping(c *gin.Context) {
+	var req dto.GroupingDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.rbacService.AddGrouping(&req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// RemoveGrouping godoc
+// @Summary 删除角色授予
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.GroupingDto true "角色授予"
+// @Success 200 {object} res.Response{data=bool} "删除成功"
+// @Router /admin/rbac/groupings [delete]
+func (h *RbacHandler) RemoveGrouping(c *gin.Context) {
+	var req dto.GroupingDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	if err := h.rbacService.RemoveGrouping(&req); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// GetUserRoles godoc
+// @Summary 查询用户角色
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Success 200 {object} res.Response{data=vo.UserRolesVO} "查询成功"
+// @Router /admin/rbac/users/{user_id}/roles [get]
+func (h *RbacHandler) GetUserRoles(c *gin.Context) {
+	var uri dto.UserIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.rbacService.GetUserRoles(uri.UserID)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// GrantUserRole godoc
+// @Summary 为用户授予角色
+// @Description 授予记录在用户主体 user:<id> 上，立即生效；工作区内的角色需通过工作区成员接口管理
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Param request body dto.UserRoleGrantDto true "角色"
+// @Success 200 {object} res.Response{data=vo.GroupingVO} "授予成功"
+// @Router /admin/rbac/users/{user_id}/roles [post]
+func (h *RbacHandler) GrantUserRole(c *gin.Context) {
+	var uri dto.UserIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.UserRoleGrantDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.rbacService.GrantUserRole(uri.UserID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// RevokeUserRole godoc
+// @Summary 撤销用户角色
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Param request body dto.UserRoleGrantDto true "角色"
+// @Success 200 {object} res.Response{data=bool} "撤销成功"
+// @Router /admin/rbac/users/{user_id}/roles [delete]
+func (h *RbacHandler) RevokeUserRole(c *gin.Context) {
+	var uri dto.UserIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.UserRoleGrantDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	if err := h.rbacService.RevokeUserRole(uri.UserID, &req); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// SetUserRole godoc
+// @Summary 设置用户全局角色
+// @Description 写入用户表，用户重新登录后生效；不能修改自己的角色
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Param request body dto.SetUserRoleDto true "角色"
+// @Success 200 {object} res.Response{data=bool} "设置成功"
+// @Router /admin/rbac/users/{user_id}/role [put]
+func (h *RbacHandler) SetUserRole(c *gin.Context) {
+	var uri dto.UserIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.SetUserRoleDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	if err := h.rbacService.SetUserRole(CurrentUser(c), uri.UserID, &req); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// Enforce godoc
+// @Summary 权限决策试运行
+// @Description 按给定主体（或用户）、域、资源与操作执行一次 Casbin 决策并返回命中的策略，不产生副作用
+// @Tags 权限管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.EnforceDto true "决策请求"
+// @Success 200 {object} res.Response{data=vo.EnforceVO} "决策完成"
+// @Router /admin/rbac/enforce [post]
+func (h *RbacHandler) Enforce(c *gin.Context) {
+	var req dto.EnforceDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.rbacService.Enforce(&req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
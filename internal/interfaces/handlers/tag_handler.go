@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"proomet/internal/application/services"
+	"proomet/internal/interfaces/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler 标签endpoint
+type TagHandler struct {
+	tagService services.TagService
+}
+
+func NewTagHandler() *TagHandler {
+	return &TagHandler{
+		tagService: services.TagService{},
+	}
+}
+
+// List godoc
+// @Summary 查询标签
+// @Description 只返回当前用户可见的提示词使用的标签
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param query query dto.ListTagDto false "查询条件"
+// @Success 200 {object} res.Response{data=[]vo.TagVO} "查询成功"
+// @Router /tags [get]
+func (h *TagHandler) List(c *gin.Context) {
+	var req dto.ListTagDto
+	if err := BindQuery(c, &req); err != nil {
+		return
+	}
+	vo, err := h.tagService.List(CurrentUser(c), &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Delete godoc
+// @Summary 删除标签
+// @Tags 标签
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "标签ID"
+// @Success 200 {object} res.Response{data=bool} "删除成功"
+// @Router /tags/{id} [delete]
+func (h *TagHandler) Delete(c *gin.Context) {
+	var uri dto.TagIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	if err := h.tagService.Delete(CurrentUser(c), uri.ID); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
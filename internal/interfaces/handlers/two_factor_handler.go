@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"proomet/internal/application/services"
+	"proomet/internal/interfaces/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler 两步验证endpoint，仅支持登录令牌访问（API Key 不可用）
+type TwoFactorHandler struct {
+	twoFactorService services.TwoFactorService
+}
+
+func NewTwoFactorHandler() *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: services.TwoFactorService{},
+	}
+}
+
+// Status godoc
+// @Summary 查询两步验证状态
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} res.Response{data=vo.TwoFactorStatusVO} "查询成功"
+// @Router /auth/2fa [get]
+func (h *TwoFactorHandler) Status(c *gin.Context) {
+	vo, err := h.twoFactorService.Status(CurrentClaims(c))
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Enroll godoc
+// @Summary 生成两步验证密钥
+// @Description 返回 TOTP 密钥、otpauth URI 与二维码，使用身份验证器扫码后调用启用接口确认
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} res.Response{data=vo.TwoFactorEnrollVO} "生成成功"
+// @Router /auth/2fa/enroll [post]
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	vo, err := h.twoFactorService.Enroll(CurrentClaims(c))
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Enable godoc
+// @Summary 启用两步验证
+// @Description 校验身份验证器中的验证码后启用，返回的恢复码只展示一次
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TwoFactorCodeDto true "验证码"
+// @Success 200 {object} res.Response{data=vo.RecoveryCodesVO} "启用成功"
+// @Router /auth/2fa/enable [post]
+func (h *TwoFactorHandler) Enable(c *gin.Context) {
+	var req dto.TwoFactorCodeDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.twoFactorService.Enable(CurrentClaims(c), &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Disable godoc
+// @Summary 关闭两步验证
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TwoFactorCodeDto true "验证码或恢复码"
+// @Success 200 {object} res.Response{data=bool} "关闭成功"
+// @Router /auth/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req dto.TwoFactorCodeDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	if err := h.twoFactorService.Disable(CurrentClaims(c), &req); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary 重新生成恢复码
+// @Description 旧恢复码全部失效，新的恢复码只展示一次
+// @Tags 两步验证
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.TwoFactorCodeDto true "验证码或恢复码"
+// @Success 200 {object} res.Response{data=vo.RecoveryCodesVO} "生成成功"
+// @Router /auth/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.TwoFactorCodeDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.twoFactorService.RegenerateRecoveryCodes(CurrentClaims(c), &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"proomet/internal/application/services"
+	"proomet/internal/interfaces/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler 用户endpoint
+type UserHandler struct {
+	userService services.UserService
+}
+
+func NewUserHandler() *UserHandler {
+	return &UserHandler{
+		userService: services.UserService{},
+	}
+}
+
+// Me godoc
+// @Summary 获取当前用户信息
+// @Tags 用户
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} res.Response{data=vo.UserVO} "查询成功"
+// @Router /users/me [get]
+func (h *UserHandler) Me(c *gin.Context) {
+	vo, err := h.userService.Me(CurrentUser(c))
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// UpdateMe godoc
+// @Summary 更新个人资料
+// @Description 修改邮箱后需要重新验证，新邮箱会收到验证邮件
+// @Tags 用户
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.UpdateProfileDto true "更新请求"
+// @Success 200 {object} res.Response{data=vo.UserVO} "更新成功"
+// @Router /users/me [put]
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	var req dto.UpdateProfileDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.userService.UpdateMe(CurrentUser(c), &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// ChangePassword godoc
+// @Summary 修改密码
+// @Description 修改成功后当前会话以外的登录会话全部失效
+// @Tags 用户
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ChangePasswordDto true "修改密码请求"
+// @Success 200 {object} res.Response{data=bool} "修改成功"
+// @Router /users/me/password [put]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	if err := h.userService.ChangePassword(CurrentClaims(c), &req); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// List godoc
+// @Summary 分页查询用户
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param query query dto.ListUserDto false "查询条件"
+// @Success 200 {object} res.Response{data=vo.PageVO[vo.UserVO]} "查询成功"
+// @Router /users [get]
+func (h *UserHandler) List(c *gin.Context) {
+	var req dto.ListUserDto
+	if err := BindQuery(c, &req); err != nil {
+		return
+	}
+	vo, err := h.userService.List(&req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Get godoc
+// @Summary 获取用户详情
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Success 200 {object} res.Response{data=vo.UserVO} "查询成功"
+// @Router /users/{user_id} [get]
+func (h *UserHandler) Get(c *gin.Context) {
+	var uri dto.UserIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.userService.Get(uri.UserID)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Update godoc
+// @Summary 更新用户信息
+// @Description 只有全局管理员可以修改角色，角色变更立即生效
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Param request body dto.UpdateUserDto true "更新请求"
+// @Success 200 {object} res.Response{data=vo.UserVO} "更新成功"
+// @Router /users/{user_id} [put]
+func (h *UserHandler) Update(c *gin.Context) {
+	var uri dto.UserIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.UpdateUserDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.userService.Update(CurrentUser(c), uri.UserID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Disable godoc
+// @Summary 禁用用户
+// @Description 禁用后该用户的访问令牌、刷新令牌与 API Key 立即失效
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Success 200 {object} res.Response{data=bool} "禁用成功"
+// @Router /users/{user_id}/disable [post]
+func (h *UserHandler) Disable(c *gin.Context) {
+	var uri dto.UserIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	if err := h.userService.Disable(CurrentUser(c), uri.UserID); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// Enable godoc
+// @Summary 启用用户
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Success 200 {object} res.Response{data=bool} "启用成功"
+// @Router /users/{user_id}/enable [post]
+func (h *UserHandler) Enable(c *gin.Context) {
+	var uri dto.UserIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	if err := h.userService.Enable(CurrentUser(c), uri.UserID); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// Delete godoc
+// @Summary 删除用户
+// @Tags 用户管理
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "用户ID"
+// @Success 200 {object} res.Response{data=bool} "删除成功"
+// @Router /users/{user_id} [delete]
+func (h *UserHandler) Delete(c *gin.Context) {
+	var uri dto.UserIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	if err := h.userService.Delete(CurrentUser(c), uri.UserID); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+	"proomet/pkg/utils/jwt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WellKnownHandler 标准发现端点，按规范直接返回 JSON，不使用统一响应结构
+type WellKnownHandler struct{}
+
+func NewWellKnownHandler() *WellKnownHandler {
+	return &WellKnownHandler{}
+}
+
+// JWKS godoc
+// @Summary 访问令牌验证公钥
+// @Description 返回 RS256/EdDSA 签名公钥（包含轮换后仍在保留期内的旧密钥），使用 HS256 时为空
+// @Tags 认证
+// @Produce json
+// @Success 200 {object} jwt.JWKS "公钥集合"
+// @Router /.well-known/jwks.json [get]
+func (h *WellKnownHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwt.PublicJWKS())
+}
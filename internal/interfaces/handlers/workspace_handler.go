@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"proomet/internal/application/services"
+	"proomet/internal/interfaces/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkspaceHandler 工作区endpoint
+type WorkspaceHandler struct {
+	workspaceService services.WorkspaceService
+}
+
+func NewWorkspaceHandler() *WorkspaceHandler {
+	return &WorkspaceHandler{
+		workspaceService: services.WorkspaceService{},
+	}
+}
+
+// Create godoc
+// @Summary 创建工作区
+// @Description 创建者自动成为工作区所有者
+// @Tags 工作区
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateWorkspaceDto true "创建请求"
+// @Success 200 {object} res.Response{data=vo.WorkspaceVO} "创建成功"
+// @Router /workspaces [post]
+func (h *WorkspaceHandler) Create(c *gin.Context) {
+	var req dto.CreateWorkspaceDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.workspaceService.Create(CurrentUser(c), &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// List godoc
+// @Summary 查询我加入的工作区
+// @Tags 工作区
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} res.Response{data=[]vo.WorkspaceVO} "查询成功"
+// @Router /workspaces [get]
+func (h *WorkspaceHandler) List(c *gin.Context) {
+	vo, err := h.workspaceService.List(CurrentUser(c))
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Get godoc
+// @Summary 获取工作区详情
+// @Tags 工作区
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id path int true "工作区ID"
+// @Success 200 {object} res.Response{data=vo.WorkspaceVO} "查询成功"
+// @Router /workspaces/{workspace_id} [get]
+func (h *WorkspaceHandler) Get(c *gin.Context) {
+	var uri dto.WorkspaceIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.workspaceService.Get(CurrentUser(c), uri.WorkspaceID)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Update godoc
+// @Summary 更新工作区
+// @Tags 工作区
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id path int true "工作区ID"
+// @Param request body dto.UpdateWorkspaceDto true "更新请求"
+// @Success 200 {object} res.Response{data=vo.WorkspaceVO} "更新成功"
+// @Router /workspaces/{workspace_id} [put]
+func (h *WorkspaceHandler) Update(c *gin.Context) {
+	var uri dto.WorkspaceIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.UpdateWorkspaceDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.workspaceService.Update(CurrentUser(c), uri.WorkspaceID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// Delete godoc
+// @Summary 删除工作区
+// @Description 仅所有者可操作，工作区内的提示词转为各自作者的个人提示词
+// @Tags 工作区
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id path int true "工作区ID"
+// @Success 200 {object} res.Response{data=bool} "删除成功"
+// @Router /workspaces/{workspace_id} [delete]
+func (h *WorkspaceHandler) Delete(c *gin.Context) {
+	var uri dto.WorkspaceIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	if err := h.workspaceService.Delete(CurrentUser(c), uri.WorkspaceID); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// ListMembers godoc
+// @Summary 查询工作区成员
+// @Tags 工作区
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id path int true "工作区ID"
+// @Success 200 {object} res.Response{data=[]vo.WorkspaceMemberVO} "查询成功"
+// @Router /workspaces/{workspace_id}/members [get]
+func (h *WorkspaceHandler) ListMembers(c *gin.Context) {
+	var uri dto.WorkspaceIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	vo, err := h.workspaceService.ListMembers(CurrentUser(c), uri.WorkspaceID)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// AddMember godoc
+// @Summary 添加工作区成员
+// @Tags 工作区
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id path int true "工作区ID"
+// @Param request body dto.AddWorkspaceMemberDto true "成员信息"
+// @Success 200 {object} res.Response{data=vo.WorkspaceMemberVO} "添加成功"
+// @Router /workspaces/{workspace_id}/members [post]
+func (h *WorkspaceHandler) AddMember(c *gin.Context) {
+	var uri dto.WorkspaceIDDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.AddWorkspaceMemberDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	vo, err := h.workspaceService.AddMember(CurrentUser(c), uri.WorkspaceID, &req)
+	if err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, vo)
+}
+
+// UpdateMember godoc
+// @Summary 修改工作区成员角色
+// @Tags 工作区
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id path int true "工作区ID"
+// @Param user_id path int true "用户ID"
+// @Param request body dto.UpdateWorkspaceMemberDto true "角色"
+// @Success 200 {object} res.Response{data=bool} "修改成功"
+// @Router /workspaces/{workspace_id}/members/{user_id} [put]
+func (h *WorkspaceHandler) UpdateMember(c *gin.Context) {
+	var uri dto.WorkspaceMemberUriDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	var req dto.UpdateWorkspaceMemberDto
+	if err := Bind(c, &req); err != nil {
+		return
+	}
+	if err := h.workspaceService.UpdateMember(CurrentUser(c), uri.WorkspaceID, uri.UserID, &req); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
+
+// RemoveMember godoc
+// @Summary 移除工作区成员
+// @Description 成员可以移除自己以退出工作区
+// @Tags 工作区
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id path int true "工作区ID"
+// @Param user_id path int true "用户ID"
+// @Success 200 {object} res.Response{data=bool} "移除成功"
+// @Router /workspaces/{workspace_id}/members/{user_id} [delete]
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	var uri dto.WorkspaceMemberUriDto
+	if err := BindUri(c, &uri); err != nil {
+		return
+	}
+	if err := h.workspaceService.RemoveMember(CurrentUser(c), uri.WorkspaceID, uri.UserID); err != nil {
+		Error(c, err)
+		return
+	}
+	Success(c, true)
+}
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"proomet/internal/interfaces/handlers"
+	"proomet/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyRouter struct {
+	apiKeyHandler handlers.APIKeyHandler
+}
+
+// NewAPIKeyRouter 创建 API Key 路由实例
+func NewAPIKeyRouter() *APIKeyRouter {
+	return &APIKeyRouter{
+		apiKeyHandler: *handlers.NewAPIKeyHandler(),
+	}
+}
+
+// RegisterRoutes 注册路由
+func (ar *APIKeyRouter) RegisterRoutes(router *gin.RouterGroup) {
+	apiKeyGroup := router.Group("/api-keys")
+	apiKeyGroup.Use(middleware.Authenticate(), middleware.Authorize())
+	{
+		apiKeyGroup.POST("", ar.apiKeyHandler.Create)
+		apiKeyGroup.GET("", ar.apiKeyHandler.List)
+		apiKeyGroup.GET("/:id", ar.apiKeyHandler.Get)
+		apiKeyGroup.PUT("/:id", ar.apiKeyHandler.Update)
+		apiKeyGroup.DELETE("/:id", ar.apiKeyHandler.Delete)
+	}
+}
@@ -1,19 +1,23 @@
 package routes
 
 import (
+	"proomet/internal/infra/oidc"
 	"proomet/internal/interfaces/handlers"
+	"proomet/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthRouter struct {
-	authHandler handlers.AuthHandler
+	authHandler      handlers.AuthHandler
+	twoFactorHandler handlers.TwoFactorHandler
 }
 
 // NewUserRouter 创建用户路由实例
 func NewAuthRouter() *AuthRouter {
 	return &AuthRouter{
-		authHandler: *handlers.NewAuthHandler(),
+		authHandler:      *handlers.NewAuthHandler(),
+		twoFactorHandler: *handlers.NewTwoFactorHandler(),
 	}
 }
 
@@ -27,6 +31,59 @@ func (tr *AuthRouter) RegisterRoutes(router *gin.RouterGroup) {
 				tr.authHandler.LoginWithPwd)
 			signGroup.POST("/register",
 				tr.authHandler.Register)
+			signGroup.POST("/2fa",
+				tr.authHandler.LoginTwoFactor)
 		}
+		tokenGroup := authGroup.Group("/token")
+		{
+			tokenGroup.POST("/refresh",
+				tr.authHandler.Refresh)
+		}
+		authGroup.POST("/logout",
+			middleware.Authenticate(),
+			tr.authHandler.Logout)
+		emailGroup := authGroup.Group("/email")
+		{
+			emailGroup.POST("/verification",
+				middleware.Authenticate(),
+				tr.authHandler.RequestEmailVerification)
+			emailGroup.POST("/verify",
+				tr.authHandler.ConfirmEmail)
+		}
+		passwordGroup := authGroup.Group("/password")
+		{
+			passwordGroup.POST("/forgot",
+				tr.authHandler.ForgotPassword)
+			passwordGroup.POST("/reset",
+				tr.authHandler.ResetPassword)
+		}
+		twoFactorGroup := authGroup.Group("/2fa", middleware.Authenticate())
+		{
+			twoFactorGroup.GET("",
+				tr.twoFactorHandler.Status)
+			twoFactorGroup.POST("/enroll",
+				tr.twoFactorHandler.Enroll)
+			twoFactorGroup.POST("/enable",
+				tr.twoFactorHandler.Enable)
+			twoFactorGroup.POST("/disable",
+				tr.twoFactorHandler.Disable)
+			twoFactorGroup.POST("/recovery-codes",
+				tr.twoFactorHandler.RegenerateRecoveryCodes)
+		}
+		oidcGroup := authGroup.Group("/oidc")
+		{
+			oidcGroup.GET("/providers",
+				tr.authHandler.OIDCProviders)
+			oidcGroup.GET("/:provider/authorize",
+				tr.authHandler.OIDCAuthorize)
+			oidcGroup.GET("/:provider/callback",
+				tr.authHandler.OIDCCallback)
+		}
+	}
+
+	// 模拟提供方的授权、令牌与 JWKS 端点，仅在非生产环境启用
+	for _, mock := range oidc.MockProviders() {
+		router.GET(mock.BasePath()+"/*any", gin.WrapH(mock))
+		router.POST(mock.BasePath()+"/*any", gin.WrapH(mock))
 	}
 }
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"proomet/internal/interfaces/handlers"
+	"proomet/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CollectionRouter struct {
+	collectionHandler handlers.CollectionHandler
+}
+
+// NewCollectionRouter 创建集合路由实例
+func NewCollectionRouter() *CollectionRouter {
+	return &CollectionRouter{
+		collectionHandler: *handlers.NewCollectionHandler(),
+	}
+}
+
+// RegisterRoutes 注册路由
+func (cr *CollectionRouter) RegisterRoutes(router *gin.RouterGroup) {
+	collectionGroup := router.Group("/collections")
+	collectionGroup.Use(middleware.Authenticate(), middleware.Authorize())
+	{
+		collectionGroup.POST("", cr.collectionHandler.Create)
+		collectionGroup.GET("", cr.collectionHandler.List)
+		collectionGroup.GET("/:id", cr.collectionHandler.Get)
+		collectionGroup.PUT("/:id", cr.collectionHandler.Update)
+		collectionGroup.DELETE("/:id", cr.collectionHandler.Delete)
+		collectionGroup.PUT("/:id/move", cr.collectionHandler.Move)
+		collectionGroup.GET("/:id/tree", cr.collectionHandler.Tree)
+	}
+}
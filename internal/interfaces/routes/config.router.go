@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"proomet/internal/domain/models"
+	"proomet/internal/interfaces/handlers"
+	"proomet/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConfigRouter struct {
+	configHandler handlers.ConfigHandler
+}
+
+// NewConfigRouter 创建运行时配置路由实例
+func NewConfigRouter() *ConfigRouter {
+	return &ConfigRouter{
+		configHandler: *handlers.NewConfigHandler(),
+	}
+}
+
+// RegisterRoutes 注册路由
+func (cr *ConfigRouter) RegisterRoutes(router *gin.RouterGroup) {
+	configGroup := router.Group("/admin/config")
+	configGroup.Use(middleware.Authenticate(), middleware.RequireRole(models.RoleAdmin))
+	{
+		configGroup.GET("", cr.configHandler.Current)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"proomet/internal/interfaces/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthRouter struct {
+	healthHandler handlers.HealthHandler
+}
+
+// NewHealthRouter 创建健康检查路由实例
+func NewHealthRouter() *HealthRouter {
+	return &HealthRouter{
+		healthHandler: *handlers.NewHealthHandler(),
+	}
+}
+
+// RegisterRoutes 注册路由
+// 探针不需要认证，也不经过 Casbin，策略未加载时仍能报告状态
+func (hr *HealthRouter) RegisterRoutes(router *gin.RouterGroup) {
+	router.GET("/healthz", hr.healthHandler.Liveness)
+	router.GET("/readyz", hr.healthHandler.Readiness)
+}
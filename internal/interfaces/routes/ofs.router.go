@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"net/http"
+	"proomet/internal/infra/ofs"
+	"proomet/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OfsRouter struct{}
+
+// NewOfsRouter 创建对象存储路由实例
+func NewOfsRouter() *OfsRouter {
+	return &OfsRouter{}
+}
+
+// RegisterRoutes 注册路由，本地存储需要由本服务处理预签名 URL 的上传与下载
+func (or *OfsRouter) RegisterRoutes(router *gin.RouterGroup) {
+	local, ok := ofs.GetStorage().(*ofs.LocalStorage)
+	if !ok {
+		return
+	}
+	handler := gin.WrapH(http.StripPrefix(local.BasePath(), local))
+	ofsGroup := router.Group(local.BasePath())
+	ofsGroup.Use(middleware.Transfer())
+	{
+		ofsGroup.GET("/*any", handler)
+		ofsGroup.HEAD("/*any", handler)
+		ofsGroup.PUT("/*any", handler)
+	}
+}
@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"proomet/internal/interfaces/handlers"
+	"proomet/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromptRouter struct {
+	promptHandler        handlers.PromptHandler
+	promptVersionHandler handlers.PromptVersionHandler
+	attachmentHandler    handlers.AttachmentHandler
+}
+
+// NewPromptRouter 创建提示词路由实例
+func NewPromptRouter() *PromptRouter {
+	return &PromptRouter{
+		promptHandler:        *handlers.NewPromptHandler(),
+		promptVersionHandler: *handlers.NewPromptVersionHandler(),
+		attachmentHandler:    *handlers.NewAttachmentHandler(),
+	}
+}
+
+// RegisterRoutes 注册路由
+func (pr *PromptRouter) RegisterRoutes(router *gin.RouterGroup) {
+	promptGroup := router.Group("/prompts")
+	promptGroup.Use(middleware.Authenticate(), middleware.Authorize())
+	{
+		promptGroup.POST("", pr.promptHandler.Create)
+		promptGroup.GET("", pr.promptHandler.List)
+		promptGroup.GET("/search", pr.promptHandler.Search)
+		promptGroup.GET("/:id", pr.promptHandler.Get)
+		promptGroup.PUT("/:id", pr.promptHandler.Update)
+		promptGroup.DELETE("/:id", pr.promptHandler.Delete)
+		promptGroup.POST("/:id/render", pr.promptHandler.Render)
+		promptGroup.POST("/:id/export", pr.promptHandler.Export)
+		promptGroup.PUT("/:id/collection", pr.promptHandler.Move)
+		promptGroup.PUT("/:id/tags", pr.promptHandler.SetTags)
+
+		// 版本历史
+		promptGroup.GET("/:id/versions", pr.promptVersionHandler.List)
+		promptGroup.GET("/:id/versions/:version", pr.promptVersionHandler.Get)
+		promptGroup.POST("/:id/versions/:version/rollback", pr.promptVersionHandler.Rollback)
+		promptGroup.GET("/:id/diff", pr.promptVersionHandler.Diff)
+
+		// 附件
+		promptGroup.GET("/:id/attachments", pr.attachmentHandler.List)
+		promptGroup.POST("/:id/attachments", middleware.Transfer(), pr.attachmentHandler.Upload)
+		promptGroup.POST("/:id/attachments/presign", pr.attachmentHandler.Presign)
+		promptGroup.GET("/:id/attachments/:attachment_id", pr.attachmentHandler.Get)
+		promptGroup.DELETE("/:id/attachments/:attachment_id", pr.attachmentHandler.Delete)
+		promptGroup.POST("/:id/attachments/:attachment_id/complete", pr.attachmentHandler.Complete)
+		promptGroup.GET("/:id/attachments/:attachment_id/download", middleware.Transfer(), pr.attachmentHandler.Download)
+	}
+}
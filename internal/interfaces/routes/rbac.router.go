@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"proomet/internal/domain/models"
+	"proomet/internal/interfaces/handlers"
+	"proomet/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RbacRouter struct {
+	rbacHandler handlers.RbacHandler
+}
+
+// NewRbacRouter 创建权限管理路由实例
+func NewRbacRouter() *RbacRouter {
+	return &RbacRouter{
+		rbacHandler: *handlers.NewRbacHandler(),
+	}
+}
+
+// RegisterRoutes 注册路由
+// 权限管理接口只校验全局管理员角色而不经过 Casbin，保证策略为空时也能完成初始配置
+func (rr *RbacRouter) RegisterRoutes(router *gin.RouterGroup) {
+	rbacGroup := router.Group("/admin/rbac")
+	rbacGroup.Use(middleware.Authenticate(), middleware.RequireRole(models.RoleAdmin))
+	{
+		rbacGroup.GET("/policies", rr.rbacHandler.ListPolicies)
+		rbacGroup.POST("/policies", rr.rbacHandler.AddPolicy)
+		rbacGroup.DELETE("/policies", rr.rbacHandler.RemovePolicy)
+		rbacGroup.GET("/groupings", rr.rbacHandler.ListGroupings)
+		rbacGroup.POST("/groupings", rr.rbacHandler.AddGrouping)
+		rbacGroup.DELETE("/groupings", rr.rbacHandler.RemoveGrouping)
+		rbacGroup.GET("/users/:user_id/roles", rr.rbacHandler.GetUserRoles)
+		rbacGroup.POST("/users/:user_id/roles", rr.rbacHandler.GrantUserRole)
+		rbacGroup.DELETE("/users/:user_id/roles", rr.rbacHandler.RevokeUserRole)
+		rbacGroup.PUT("/users/:user_id/role", rr.rbacHandler.SetUserRole)
+		rbacGroup.POST("/enforce", rr.rbacHandler.Enforce)
+	}
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"proomet/internal/interfaces/handlers"
+	"proomet/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagRouter struct {
+	tagHandler handlers.TagHandler
+}
+
+// NewTagRouter 创建标签路由实例
+func NewTagRouter() *TagRouter {
+	return &TagRouter{
+		tagHandler: *handlers.NewTagHandler(),
+	}
+}
+
+// RegisterRoutes 注册路由
+func (tr *TagRouter) RegisterRoutes(router *gin.RouterGroup) {
+	tagGroup := router.Group("/tags")
+	tagGroup.Use(middleware.Authenticate(), middleware.Authorize())
+	{
+		tagGroup.GET("", tr.tagHandler.List)
+		tagGroup.DELETE("/:id", tr.tagHandler.Delete)
+	}
+}
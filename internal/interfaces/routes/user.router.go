@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"proomet/internal/interfaces/handlers"
+	"proomet/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserRouter struct {
+	userHandler handlers.UserHandler
+}
+
+// NewUserRouter 创建用户路由实例
+func NewUserRouter() *UserRouter {
+	return &UserRouter{
+		userHandler: *handlers.NewUserHandler(),
+	}
+}
+
+// RegisterRoutes 注册路由
+// /users/me 只需要登录即可访问，用户管理接口通过 Casbin 授权（默认仅管理员）
+func (ur *UserRouter) RegisterRoutes(router *gin.RouterGroup) {
+	meGroup := router.Group("/users/me")
+	meGroup.Use(middleware.Authenticate())
+	{
+		meGroup.GET("", ur.userHandler.Me)
+		meGroup.PUT("", ur.userHandler.UpdateMe)
+		meGroup.PUT("/password", ur.userHandler.ChangePassword)
+	}
+
+	userGroup := router.Group("/users")
+	userGroup.Use(middleware.Authenticate(), middleware.Authorize())
+	{
+		userGroup.GET("", ur.userHandler.List)
+		userGroup.GET("/:user_id", ur.userHandler.Get)
+		userGroup.PUT("/:user_id", ur.userHandler.Update)
+		userGroup.DELETE("/:user_id", ur.userHandler.Delete)
+		userGroup.POST("/:user_id/disable", ur.userHandler.Disable)
+		userGroup.POST("/:user_id/enable", ur.userHandler.Enable)
+	}
+}
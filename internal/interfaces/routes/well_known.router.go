@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"proomet/internal/interfaces/handlers"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WellKnownRouter struct {
+	wellKnownHandler handlers.WellKnownHandler
+}
+
+// NewWellKnownRouter 创建发现端点路由实例
+func NewWellKnownRouter() *WellKnownRouter {
+	return &WellKnownRouter{
+		wellKnownHandler: *handlers.NewWellKnownHandler(),
+	}
+}
+
+// RegisterRoutes 注册路由
+func (wr *WellKnownRouter) RegisterRoutes(router *gin.RouterGroup) {
+	wellKnownGroup := router.Group("/.well-known")
+	{
+		wellKnownGroup.GET("/jwks.json", wr.wellKnownHandler.JWKS)
+	}
+}
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"proomet/internal/interfaces/handlers"
+	"proomet/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkspaceRouter struct {
+	workspaceHandler handlers.WorkspaceHandler
+}
+
+// NewWorkspaceRouter 创建工作区路由实例
+func NewWorkspaceRouter() *WorkspaceRouter {
+	return &WorkspaceRouter{
+		workspaceHandler: *handlers.NewWorkspaceHandler(),
+	}
+}
+
+// RegisterRoutes 注册路由，路径参数 workspace_id 同时作为 Casbin 的域
+func (wr *WorkspaceRouter) RegisterRoutes(router *gin.RouterGroup) {
+	workspaceGroup := router.Group("/workspaces")
+	workspaceGroup.Use(middleware.Authenticate(), middleware.Authorize())
+	{
+		workspaceGroup.POST("", wr.workspaceHandler.Create)
+		workspaceGroup.GET("", wr.workspaceHandler.List)
+		workspaceGroup.GET("/:workspace_id", wr.workspaceHandler.Get)
+		workspaceGroup.PUT("/:workspace_id", wr.workspaceHandler.Update)
+		workspaceGroup.DELETE("/:workspace_id", wr.workspaceHandler.Delete)
+		workspaceGroup.GET("/:workspace_id/members", wr.workspaceHandler.ListMembers)
+		workspaceGroup.POST("/:workspace_id/members", wr.workspaceHandler.AddMember)
+		workspaceGroup.PUT("/:workspace_id/members/:user_id", wr.workspaceHandler.UpdateMember)
+		workspaceGroup.DELETE("/:workspace_id/members/:user_id", wr.workspaceHandler.RemoveMember)
+	}
+}
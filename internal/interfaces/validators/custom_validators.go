@@ -10,18 +10,59 @@ import (
 
 // fieldNameMap 字段名称中英文映射
 var fieldNameMap = map[string]string{
-	"Username":    "用户名",
-	"Password":    "密码",
-	"Email":       "邮箱",
-	"Name":        "名称",
-	"Description": "描述",
-	"ParentID":    "父级ID",
-	"UserID":      "用户ID",
-	"Role":        "角色",
-	"Department":  "部门",
-	"Sub":         "主体",
-	"Obj":         "对象",
-	"Act":         "操作",
+	"Username":       "用户名",
+	"Password":       "密码",
+	"Email":          "邮箱",
+	"Name":           "名称",
+	"Description":    "描述",
+	"ParentID":       "父级ID",
+	"UserID":         "用户ID",
+	"Role":           "角色",
+	"Department":     "部门",
+	"Sub":            "主体",
+	"Obj":            "对象",
+	"Act":            "操作",
+	"Title":          "标题",
+	"Body":           "正文",
+	"Visibility":     "可见性",
+	"Page":           "页码",
+	"PageSize":       "每页数量",
+	"Message":        "变更说明",
+	"Version":        "版本号",
+	"From":           "起始版本",
+	"To":             "目标版本",
+	"Mode":           "对比模式",
+	"Variables":      "变量",
+	"Type":           "类型",
+	"Messages":       "消息",
+	"Content":        "内容",
+	"Format":         "导出格式",
+	"Tags":           "标签",
+	"AnyTags":        "任一标签",
+	"CollectionID":   "集合ID",
+	"Q":              "检索关键词",
+	"OwnerID":        "所有者ID",
+	"Cursor":         "游标",
+	"Limit":          "数量",
+	"Slug":           "唯一标识",
+	"WorkspaceID":    "工作区ID",
+	"Dom":            "域",
+	"RefreshToken":   "刷新令牌",
+	"Scopes":         "权限范围",
+	"ExpiresAt":      "过期时间",
+	"Provider":       "身份提供方",
+	"Code":           "验证码",
+	"ChallengeToken": "挑战令牌",
+	"Token":          "令牌",
+	"Nickname":       "昵称",
+	"OldPassword":    "旧密码",
+	"NewPassword":    "新密码",
+	"Status":         "状态",
+	"State":          "状态参数",
+	"FileName":       "文件名",
+	"ContentType":    "文件类型",
+	"Size":           "文件大小",
+	"AttachmentID":   "附件ID",
 }
 
 // getFieldName 获取字段中文名称
@@ -38,6 +79,8 @@ func RegisterCustomValidators() {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		// 注册自定义验证器
 		v.RegisterValidation("username", validateUsername)
+		v.RegisterValidation("varname", validateVarName)
+		v.RegisterValidation("slug", validateSlug)
 
 		// 注册自定义验证错误消息翻译器
 		v.RegisterTagNameFunc(func(fld reflect.StructField) string {
@@ -79,6 +122,20 @@ func validateUsername(fl validator.FieldLevel) bool {
 	return true
 }
 
+// validateSlug 唯一标识验证器：小写字母、数字和连字符
+func validateSlug(fl validator.FieldLevel) bool {
+	slug := fl.Field().String()
+	if slug == "" || slug[0] == '-' || slug[len(slug)-1] == '-' {
+		return false
+	}
+	for _, r := range slug {
+		if !((r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
 // ValidateStruct 验证结构体
 func ValidateStruct(s any) error {
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
@@ -104,7 +161,7 @@ func formatValidationError(fe validator.FieldError) string {
 	fieldName := getFieldName(fe.Field())
 
 	switch fe.Tag() {
-	case "required":
+	case "required", "required_if", "required_unless":
 		return fieldName + "为必填字段"
 	case "email":
 		return fieldName + "必须是有效的邮箱地址"
@@ -112,8 +169,16 @@ func formatValidationError(fe validator.FieldError) string {
 		return fieldName + "长度不能少于" + fe.Param() + "个字符"
 	case "max":
 		return fieldName + "长度不能超过" + fe.Param() + "个字符"
+	case "oneof":
+		return fieldName + "必须是以下值之一: " + fe.Param()
 	case "username":
 		return fieldName + "必须是3-50个字符，只能包含字母、数字、下划线和连字符，且不能以下划线或连字符开头或结尾"
+	case "slug":
+		return fieldName + "只能包含小写字母、数字和连字符，且不能以连字符开头或结尾"
+	case "required_without":
+		return fieldName + "与" + getFieldName(fe.Param()) + "至少填写一项"
+	case "varname":
+		return fieldName + "只能包含字母、数字和下划线，且不能以数字开头"
 	default:
 		return fieldName + "格式不正确"
 	}
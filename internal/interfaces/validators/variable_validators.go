@@ -0,0 +1,178 @@
+package validators
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"proomet/internal/domain/models"
+	"proomet/pkg/utils/render"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validateVarName 模板变量名验证器
+func validateVarName(fl validator.FieldLevel) bool {
+	return render.IsValidName(fl.Field().String())
+}
+
+// ValidateVariableSchema 校验变量定义本身：名称唯一、默认值与枚举值符合声明的类型
+func ValidateVariableSchema(schema []models.PromptVariable) []string {
+	var errorMessages []string
+	seen := make(map[string]bool)
+
+	for _, variable := range schema {
+		if seen[variable.Name] {
+			errorMessages = append(errorMessages, fmt.Sprintf("变量 %s 重复定义", variable.Name))
+			continue
+		}
+		seen[variable.Name] = true
+
+		for _, option := range variable.Enum {
+			if _, err := coerceVariable(variable.Type, option); err != nil {
+				errorMessages = append(errorMessages, fmt.Sprintf("变量 %s 的枚举值 %v %s", variable.Name, option, err))
+			}
+		}
+		if variable.Default == nil {
+			continue
+		}
+		value, err := coerceVariable(variable.Type, variable.Default)
+		if err != nil {
+			errorMessages = append(errorMessages, fmt.Sprintf("变量 %s 的默认值%s", variable.Name, err))
+			continue
+		}
+		if !inEnum(variable, value) {
+			errorMessages = append(errorMessages, fmt.Sprintf("变量 %s 的默认值不在枚举范围内", variable.Name))
+		}
+	}
+	return errorMessages
+}
+
+// ValidateVariableValues 按变量定义校验传入的变量值，返回可直接用于渲染的字符串值。
+// 正文中出现但未声明的占位符视为必填的字符串变量。
+func ValidateVariableValues(schema []models.PromptVariable, placeholders []string, values map[string]any) (map[string]string, []string) {
+	declared := make(map[string]bool, len(schema))
+	variables := make([]models.PromptVariable, 0, len(schema)+len(placeholders))
+	for _, variable := range schema {
+		declared[variable.Name] = true
+		variables = append(variables, variable)
+	}
+	for _, name := range placeholders {
+		if !declared[name] {
+			variables = append(variables, models.PromptVariable{
+				Name:     name,
+				Type:     models.VariableTypeString,
+				Required: true,
+			})
+		}
+	}
+
+	resolved := make(map[string]string, len(variables))
+	var errorMessages []string
+	for _, variable := range variables {
+		raw, ok := values[variable.Name]
+		if !ok || raw == nil {
+			raw = variable.Default
+		}
+		if raw == nil {
+			if variable.Required {
+				errorMessages = append(errorMessages, fmt.Sprintf("缺少变量 %s", variable.Name))
+			} else {
+				resolved[variable.Name] = ""
+			}
+			continue
+		}
+
+		value, err := coerceVariable(variable.Type, raw)
+		if err != nil {
+			errorMessages = append(errorMessages, fmt.Sprintf("变量 %s %s", variable.Name, err))
+			continue
+		}
+		if !inEnum(variable, value) {
+			errorMessages = append(errorMessages, fmt.Sprintf("变量 %s 必须是以下值之一: %s", variable.Name, formatEnum(variable.Enum)))
+			continue
+		}
+		resolved[variable.Name] = value
+	}
+	return resolved, errorMessages
+}
+
+// coerceVariable 将变量值按类型转换为字符串形式
+func coerceVariable(varType string, raw any) (string, error) {
+	switch varType {
+	case models.VariableTypeString:
+		if s, ok := raw.(string); ok {
+			return s, nil
+		}
+		return "", fmt.Errorf("必须是字符串")
+	case models.VariableTypeNumber:
+		n, ok := toNumber(raw)
+		if !ok {
+			return "", fmt.Errorf("必须是数字")
+		}
+		return strconv.FormatFloat(n, 'f', -1, 64), nil
+	case models.VariableTypeInteger:
+		n, ok := toNumber(raw)
+		if !ok || n != math.Trunc(n) {
+			return "", fmt.Errorf("必须是整数")
+		}
+		return strconv.FormatInt(int64(n), 10), nil
+	case models.VariableTypeBoolean:
+		switch v := raw.(type) {
+		case bool:
+			return strconv.FormatBool(v), nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return strconv.FormatBool(b), nil
+			}
+		}
+		return "", fmt.Errorf("必须是布尔值")
+	default:
+		return "", fmt.Errorf("类型 %s 不受支持", varType)
+	}
+}
+
+// toNumber 兼容 JSON 数字与数字字符串
+func toNumber(raw any) (float64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// inEnum 判断值是否在枚举范围内，未定义枚举时总是返回 true
+func inEnum(variable models.PromptVariable, value string) bool {
+	if len(variable.Enum) == 0 {
+		return true
+	}
+	for _, option := range variable.Enum {
+		if coerced, err := coerceVariable(variable.Type, option); err == nil && coerced == value {
+			return true
+		}
+	}
+	return false
+}
+
+// formatEnum 格式化枚举值列表
+func formatEnum(options []any) string {
+	parts := make([]string, 0, len(options))
+	for _, option := range options {
+		parts = append(parts, fmt.Sprintf("%v", option))
+	}
+	return strings.Join(parts, ", ")
+}
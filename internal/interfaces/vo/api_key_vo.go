@@ -0,0 +1,22 @@
+package vo
+
+import "time"
+
+// APIKeyVO API Key 信息，不包含密钥本身
+type APIKeyVO struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Scopes      []string   `json:"scopes"`
+	UserID      uint       `json:"user_id"`
+	WorkspaceID *uint      `json:"workspace_id"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// APIKeyCreatedVO 新建的 API Key，完整密钥只在创建时返回一次
+type APIKeyCreatedVO struct {
+	APIKeyVO
+	Key string `json:"key"`
+}
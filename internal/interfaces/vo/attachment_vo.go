@@ -0,0 +1,26 @@
+package vo
+
+import "time"
+
+// AttachmentVO 提示词附件
+type AttachmentVO struct {
+	ID          uint      `json:"id"`
+	PromptID    uint      `json:"prompt_id"`
+	UploaderID  uint      `json:"uploader_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	// DownloadURL 预签名下载地址，仅在查询附件详情时返回
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// PresignAttachmentVO 预签名上传信息，客户端使用 Method 与 Headers 将文件直接上传到 UploadURL，完成后调用 complete 接口
+type PresignAttachmentVO struct {
+	Attachment AttachmentVO      `json:"attachment"`
+	UploadURL  string            `json:"upload_url"`
+	Method     string            `json:"method"`
+	Headers    map[string]string `json:"headers"`
+	ExpiresIn  int64             `json:"expires_in"`
+}
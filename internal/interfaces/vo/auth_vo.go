@@ -1,13 +1,48 @@
 package vo
 
+import "time"
+
 // LoginVO 用户登陆
+// token 为短期访问令牌，过期后使用 refresh_token 换取新的令牌对（刷新令牌每次使用后轮换）
+// 账号启用两步验证时不返回令牌，而是返回 challenge_token，需要通过 /auth/sign/2fa 完成登录
 type AuthLoginVO struct {
-	Token string `json:"token"`
+	Token              string `json:"token,omitempty"`
+	RefreshToken       string `json:"refresh_token,omitempty"`
+	ExpiresIn          int64  `json:"expires_in,omitempty"`
+	RefreshExpiresIn   int64  `json:"refresh_expires_in,omitempty"`
+	TwoFactorRequired  bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken     string `json:"challenge_token,omitempty"`
+	ChallengeExpiresIn int64  `json:"challenge_expires_in,omitempty"`
 }
 
 // RegisterVO 用户注册
 type AuthRegisterVO struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
-	Token    string `json:"token"`
+	AuthLoginVO
+}
+
+// OIDCAuthorizeVO OIDC 授权地址，客户端需要将用户重定向到该地址
+type OIDCAuthorizeVO struct {
+	AuthorizationURL string `json:"authorization_url"`
+	State            string `json:"state"`
+}
+
+// TwoFactorStatusVO 两步验证状态
+type TwoFactorStatusVO struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+// TwoFactorEnrollVO 绑定身份验证器所需的信息，qr_code 为 PNG 图片的 data URI
+type TwoFactorEnrollVO struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCode     string `json:"qr_code"`
+}
+
+// RecoveryCodesVO 恢复码，仅在生成时返回一次
+type RecoveryCodesVO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
 }
@@ -0,0 +1,25 @@
+package vo
+
+import "time"
+
+// CollectionVO 集合详情
+type CollectionVO struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ParentID    *uint     `json:"parent_id"`
+	OwnerID     uint      `json:"owner_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CollectionTreeVO 集合子树节点
+type CollectionTreeVO struct {
+	ID          uint                `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	ParentID    *uint               `json:"parent_id"`
+	PromptCount int64               `json:"prompt_count"`
+	Prompts     []PromptSummaryVO   `json:"prompts,omitempty"`
+	Children    []*CollectionTreeVO `json:"children"`
+}
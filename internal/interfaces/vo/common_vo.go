@@ -0,0 +1,16 @@
+package vo
+
+// PageVO 分页结果
+type PageVO[T any] struct {
+	List     []T   `json:"list"`
+	Total    int64 `json:"total"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+}
+
+// CursorPageVO 游标分页结果
+type CursorPageVO[T any] struct {
+	List       []T    `json:"list"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
@@ -0,0 +1,10 @@
+package vo
+
+import "time"
+
+// ConfigVO 当前生效的配置，敏感项已脱敏
+type ConfigVO struct {
+	Version  int64          `json:"version"`
+	LoadedAt time.Time      `json:"loaded_at"`
+	Config   map[string]any `json:"config"`
+}
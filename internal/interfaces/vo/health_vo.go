@@ -0,0 +1,20 @@
+package vo
+
+// 健康检查状态
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDown     = "down"
+	HealthStatusDegraded = "degraded"
+)
+
+// ComponentHealthVO 依赖组件的检查结果，LatencyMS 为检查耗时（毫秒），失败原因只记录到日志
+type ComponentHealthVO struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+// HealthVO 健康检查结果，存活检查不包含 Components
+type HealthVO struct {
+	Status     string                       `json:"status"`
+	Components map[string]ComponentHealthVO `json:"components,omitempty"`
+}
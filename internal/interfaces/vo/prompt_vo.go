@@ -0,0 +1,143 @@
+package vo
+
+import (
+	"proomet/pkg/utils/diff"
+	"time"
+)
+
+// PromptVO 提示词详情
+type PromptVO struct {
+	ID             uint               `json:"id"`
+	Title          string             `json:"title"`
+	Type           string             `json:"type"`
+	Messages       []PromptMessageVO  `json:"messages,omitempty"`
+	Body           string             `json:"body"`
+	Description    string             `json:"description"`
+	Variables      []PromptVariableVO `json:"variables"`
+	WorkspaceID    *uint              `json:"workspace_id"`
+	CollectionID   *uint              `json:"collection_id"`
+	Tags           []TagVO            `json:"tags"`
+	OwnerID        uint               `json:"owner_id"`
+	Visibility     string             `json:"visibility"`
+	CurrentVersion int                `json:"current_version"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+}
+
+// PromptSummaryVO 提示词摘要，用于树形结构等嵌套展示
+type PromptSummaryVO struct {
+	ID         uint      `json:"id"`
+	Title      string    `json:"title"`
+	Type       string    `json:"type"`
+	Visibility string    `json:"visibility"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// PromptSearchHitVO 全文检索命中结果，高亮片段已做 HTML 转义，仅命中词使用 <mark></mark> 包裹
+type PromptSearchHitVO struct {
+	ID             uint      `json:"id"`
+	Title          string    `json:"title"`
+	TitleHighlight string    `json:"title_highlight"`
+	Description    string    `json:"description"`
+	Snippet        string    `json:"snippet"`
+	Type           string    `json:"type"`
+	OwnerID        uint      `json:"owner_id"`
+	CollectionID   *uint     `json:"collection_id"`
+	Visibility     string    `json:"visibility"`
+	Rank           float64   `json:"rank"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// PromptMessageVO 对话消息
+type PromptMessageVO struct {
+	Role    string `json:"role"`
+	Name    string `json:"name,omitempty"`
+	Content string `json:"content"`
+}
+
+// PromptVariableVO 模板变量定义
+type PromptVariableVO struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Default     any    `json:"default,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// PromptVersionVO 提示词版本
+type PromptVersionVO struct {
+	ID          uint               `json:"id"`
+	PromptID    uint               `json:"prompt_id"`
+	Version     int                `json:"version"`
+	Title       string             `json:"title"`
+	Type        string             `json:"type"`
+	Messages    []PromptMessageVO  `json:"messages,omitempty"`
+	Body        string             `json:"body"`
+	Description string             `json:"description"`
+	Variables   []PromptVariableVO `json:"variables"`
+	AuthorID    uint               `json:"author_id"`
+	Message     string             `json:"message"`
+	ContentHash string             `json:"content_hash"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+// PromptFieldDiffVO 单个字段的差异，Insertions、Deletions 为新增与删除的 token 数
+type PromptFieldDiffVO struct {
+	Field      string    `json:"field"`
+	Ops        []diff.Op `json:"ops"`
+	Insertions int       `json:"insertions"`
+	Deletions  int       `json:"deletions"`
+}
+
+// PromptDiffVO 两个版本之间的差异
+type PromptDiffVO struct {
+	PromptID    uint                `json:"prompt_id"`
+	FromVersion int                 `json:"from_version"`
+	ToVersion   int                 `json:"to_version"`
+	Mode        string              `json:"mode"`
+	Changes     []PromptFieldDiffVO `json:"changes"`
+}
+
+// RenderPromptVO 提示词渲染结果
+type RenderPromptVO struct {
+	PromptID  uint              `json:"prompt_id"`
+	Version   int               `json:"version"`
+	Type      string            `json:"type"`
+	Text      string            `json:"text"`
+	Messages  []PromptMessageVO `json:"messages,omitempty"`
+	Variables map[string]string `json:"variables"`
+}
+
+// OpenAIMessageVO OpenAI Chat Completions 消息
+type OpenAIMessageVO struct {
+	Role       string `json:"role"`
+	Content    string `json:"content"`
+	Name       string `json:"name,omitempty"`
+	ToolCallID string `json:"tool_call_id,omitempty"`
+}
+
+// OpenAIExportVO OpenAI Chat Completions 请求中的 messages 部分
+type OpenAIExportVO struct {
+	Messages []OpenAIMessageVO `json:"messages"`
+}
+
+// AnthropicContentBlockVO Anthropic 消息内容块
+type AnthropicContentBlockVO struct {
+	Type      string `json:"type"`
+	Text      string `json:"text,omitempty"`
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   string `json:"content,omitempty"`
+}
+
+// AnthropicMessageVO Anthropic Messages API 消息
+type AnthropicMessageVO struct {
+	Role    string                    `json:"role"`
+	Content []AnthropicContentBlockVO `json:"content"`
+}
+
+// AnthropicExportVO Anthropic Messages API 请求中的 system 与 messages 部分
+type AnthropicExportVO struct {
+	System   string               `json:"system,omitempty"`
+	Messages []AnthropicMessageVO `json:"messages"`
+}
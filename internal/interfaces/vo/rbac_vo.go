@@ -0,0 +1,33 @@
+package vo
+
+// PolicyVO 权限策略(p)
+type PolicyVO struct {
+	Sub string `json:"sub"`
+	Dom string `json:"dom"`
+	Obj string `json:"obj"`
+	Act string `json:"act"`
+}
+
+// GroupingVO 角色授予(g)
+type GroupingVO struct {
+	Sub  string `json:"sub"`
+	Role string `json:"role"`
+	Dom  string `json:"dom"`
+}
+
+// UserRolesVO 用户的全局角色及 Casbin 中的角色授予
+type UserRolesVO struct {
+	UserID   uint         `json:"user_id"`
+	Username string       `json:"username"`
+	Role     string       `json:"role"`
+	Subject  string       `json:"subject"`
+	Grants   []GroupingVO `json:"grants"`
+}
+
+// EnforceVO 权限决策试运行结果，matched 为命中的策略
+type EnforceVO struct {
+	Allowed bool      `json:"allowed"`
+	Subject string    `json:"subject"`
+	Dom     string    `json:"dom"`
+	Matched *PolicyVO `json:"matched"`
+}
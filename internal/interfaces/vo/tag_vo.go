@@ -0,0 +1,8 @@
+package vo
+
+// TagVO 标签
+type TagVO struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	PromptCount int64  `json:"prompt_count,omitempty"`
+}
@@ -0,0 +1,16 @@
+package vo
+
+import "time"
+
+// UserVO 用户信息
+type UserVO struct {
+	ID              uint       `json:"id"`
+	Username        string     `json:"username"`
+	Nickname        string     `json:"nickname"`
+	Email           string     `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	Role            string     `json:"role"`
+	Status          int        `json:"status"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
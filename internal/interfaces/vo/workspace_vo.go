@@ -0,0 +1,24 @@
+package vo
+
+import "time"
+
+// WorkspaceVO 工作区详情
+type WorkspaceVO struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	OwnerID     uint      `json:"owner_id"`
+	Role        string    `json:"role,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WorkspaceMemberVO 工作区成员
+type WorkspaceMemberVO struct {
+	UserID   uint      `json:"user_id"`
+	Username string    `json:"username"`
+	Nickname string    `json:"nickname"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
@@ -1,35 +1,51 @@
 package middleware
 
 import (
+	"errors"
 	"proomet/internal/domain/models"
 	"proomet/internal/infra/auth"
+	"proomet/pkg/utils"
 	"proomet/pkg/utils/jwt"
 	"proomet/pkg/utils/res"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 func Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		apiKey := c.GetHeader("X-API-Key")
 
 		// 1. 无 Header，注入 Guest 身份
-		if authHeader == "" {
+		if authHeader == "" && apiKey == "" {
 			c.Set("currentUser", models.JwtUser{Role: "guest", Username: "guest"})
 			c.Next()
 			return
 		}
 
-		// 2. 格式校验
+		// 2. API Key：X-API-Key 请求头或 Bearer pk_...
+		if apiKey != "" {
+			authenticateAPIKey(c, apiKey)
+			return
+		}
+
+		// 3. 格式校验
 		parts := strings.SplitN(authHeader, " ", 2)
 		if !(len(parts) == 2 && parts[0] == "Bearer") {
 			res.ErrUnauthorized.Msg("Authorization 格式错误").Throw(c)
 			c.Abort()
 			return
 		}
+		if auth.IsAPIKey(parts[1]) {
+			authenticateAPIKey(c, parts[1])
+			return
+		}
 
-		// 3. 解析 Token
+		// 4. 解析 Token
 		claims, err := jwt.ParseToken(parts[1])
 		if err != nil {
 			// Token 过期或非法，必须明确报错，而不是降级为 Guest
@@ -38,11 +54,59 @@ func Authenticate() gin.HandlerFunc {
 			return
 		}
 
+		// 5. 校验令牌是否已被吊销（退出登录）
+		if claims.ID != "" {
+			revoked, err := auth.IsTokenRevoked(claims.ID)
+			if err != nil {
+				res.ErrInternalServer.Msg("令牌校验失败").Throw(c)
+			}
+			if revoked {
+				res.ErrUnauthorized.Msg("登录已失效，请重新登录").Throw(c)
+			}
+		}
+
+		// 6. 以数据库中的最新状态为准：禁用或删除的用户立即失效，角色变更立即生效
+		user, err := auth.LoadUserState(claims.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				res.ErrUnauthorized.Msg("用户不存在，请重新登录").Throw(c)
+			}
+			res.ErrInternalServer.Msg("令牌校验失败").Throw(c)
+		}
+		if user.Status == models.UserStatusDisabled {
+			res.ErrAccountDisabled.Throw(c)
+		}
+		claims.Role = user.Role
+
 		c.Set("currentUser", claims.JwtUser)
+		c.Set("tokenClaims", claims)
 		c.Next()
 	}
 }
 
+// authenticateAPIKey 校验 API Key 及其权限范围，并注入对应的身份
+func authenticateAPIKey(c *gin.Context, key string) {
+	apiKey, user, err := auth.AuthenticateAPIKey(key)
+	if err != nil {
+		if errors.Is(err, auth.ErrAPIKeyInvalid) {
+			res.ErrUnauthorized.Msg("API Key 无效或已过期").Throw(c)
+		}
+		res.ErrInternalServer.Msg("API Key 校验失败").Throw(c)
+	}
+	if user.Status == models.UserStatusDisabled {
+		res.ErrAccountDisabled.Throw(c)
+	}
+
+	scope := auth.RequiredScope(c.Request.Method, c.Request.URL.Path)
+	if scope == "" || !apiKey.HasScope(scope) {
+		res.ErrForbidden.Msgf("API Key 缺少所需的权限范围 %s", utils.DefaultString(scope, "(不支持)")).Throw(c)
+	}
+
+	c.Set("currentUser", auth.APIKeyIdentity(apiKey, user))
+	c.Set("apiKey", apiKey)
+	c.Next()
+}
+
 // 权限校验中间件
 func Authorize() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -55,10 +119,31 @@ func Authorize() gin.HandlerFunc {
 		// 获取请求的资源(Object)和动作(Action)
 		obj := c.Request.URL.Path
 		act := c.Request.Method
-		e := auth.GetEnforcer()
+
+		// 获取请求所属的工作区(Domain)
+		dom := auth.PersonalDomain
+		workspaceID, err := resolveWorkspaceID(c)
+		if err != nil {
+			message := "工作区ID格式错误"
+			if errors.Is(err, errWorkspaceMismatch) {
+				message = "X-Workspace-ID 与路径中的工作区不一致"
+			}
+			res.ErrInvalidParam.Msg(message).Throw(c)
+		}
+		// 工作区密钥只能在所属工作区内使用
+		if user.WorkspaceID != 0 {
+			if workspaceID != 0 && workspaceID != user.WorkspaceID {
+				res.ErrForbidden.Msg("API Key 不属于该工作区").Throw(c)
+			}
+			workspaceID = user.WorkspaceID
+		}
+		if workspaceID != 0 {
+			dom = auth.WorkspaceDomain(workspaceID)
+			c.Set("workspaceID", workspaceID)
+		}
 
 		// 调用 Casbin 进行决策
-		ok, err := e.Enforce(user.Role, obj, act)
+		ok, err := auth.Enforce(user, dom, obj, act)
 		if err != nil {
 			res.ErrInternalServer.Msg("权限系统发生错误").Throw(c)
 		}
@@ -70,3 +155,58 @@ func Authorize() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// errWorkspaceMismatch X-Workspace-ID 请求头与路径参数指向不同的工作区
+var errWorkspaceMismatch = errors.New("workspace id mismatch")
+
+// resolveWorkspaceID 解析请求所属的工作区：路由带有 workspace_id 路径参数时以路径参数为准，
+// 同时传入的 X-Workspace-ID 请求头必须与之一致；其余路由使用请求头
+func resolveWorkspaceID(c *gin.Context) (uint, error) {
+	header, err := parseWorkspaceID(c.GetHeader("X-Workspace-ID"))
+	if err != nil {
+		return 0, err
+	}
+	raw := c.Param("workspace_id")
+	if raw == "" {
+		return header, nil
+	}
+	path, err := parseWorkspaceID(raw)
+	if err != nil {
+		return 0, err
+	}
+	if header != 0 && header != path {
+		return 0, errWorkspaceMismatch
+	}
+	return path, nil
+}
+
+// parseWorkspaceID 解析工作区ID，为空时返回 0
+func parseWorkspaceID(raw string) (uint, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// RequireRole 要求当前用户具有指定的全局角色之一，不经过 Casbin 决策
+// 用于策略管理等接口，避免策略为空时管理员也无法访问
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userRaw, exists := c.Get("currentUser")
+		if !exists {
+			res.ErrUnauthorized.Throw(c)
+		}
+		user := userRaw.(models.JwtUser)
+		if user.UserID == 0 {
+			res.ErrUnauthorized.Throw(c)
+		}
+		if !slices.Contains(roles, user.Role) {
+			res.ErrForbidden.Throw(c)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestResolveWorkspaceID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name    string
+		route   string
+		path    string
+		header  string
+		want    uint
+		wantErr error
+	}{
+		{name: "无工作区", route: "/prompts", path: "/prompts"},
+		{name: "仅请求头", route: "/prompts", path: "/prompts", header: "7", want: 7},
+		{name: "仅路径参数", route: "/workspaces/:workspace_id", path: "/workspaces/3", want: 3},
+		{name: "请求头与路径一致", route: "/workspaces/:workspace_id", path: "/workspaces/3", header: "3", want: 3},
+		{name: "请求头与路径不一致", route: "/workspaces/:workspace_id", path: "/workspaces/3", header: "7", wantErr: errWorkspaceMismatch},
+		{name: "请求头格式错误", route: "/prompts", path: "/prompts", header: "abc", wantErr: errAny},
+		{name: "路径参数格式错误", route: "/workspaces/:workspace_id", path: "/workspaces/abc", wantErr: errAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got uint
+			var err error
+			r := gin.New()
+			r.GET(tt.route, func(c *gin.Context) {
+				got, err = resolveWorkspaceID(c)
+			})
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("X-Workspace-ID", tt.header)
+			}
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			switch {
+			case tt.wantErr == errAny:
+				if err == nil {
+					t.Fatalf("期望返回错误，实际为 %d", got)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("期望错误 %v，实际为 %v", tt.wantErr, err)
+				}
+			case err != nil:
+				t.Fatalf("意外的错误: %v", err)
+			case got != tt.want:
+				t.Fatalf("期望工作区 %d，实际为 %d", tt.want, got)
+			}
+		})
+	}
+}
+
+// errAny 表示期望返回任意错误
+var errAny = errors.New("any")
@@ -5,9 +5,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"net/http"
+	"proomet/config"
 	"proomet/pkg/utils"
 	"proomet/pkg/utils/res"
 	"runtime/debug"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -62,13 +64,25 @@ func RecoveryMiddleware() gin.HandlerFunc {
 }
 
 // CORSMiddleware 跨域中间件
+// 每个请求读取当前配置，server.enable_cors 与 server.cors_origins 修改后即时生效
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Request-ID")
-		c.Header("Access-Control-Expose-Headers", "Content-Length")
-		c.Header("Access-Control-Allow-Credentials", "true")
+		server := config.Get().Server
+		if !server.EnableCORS {
+			c.Next()
+			return
+		}
+
+		c.Header("Vary", "Origin")
+		if origin, credentials := allowedOrigin(server.CORSOrigins, c.GetHeader("Origin")); origin != "" {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Request-ID, X-Workspace-ID, X-API-Key")
+			c.Header("Access-Control-Expose-Headers", "Content-Length")
+			if credentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		}
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -79,6 +93,25 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
+// allowedOrigin 返回允许的跨域来源以及是否允许携带凭证
+// 只有明确配置的来源允许携带凭证；仅匹配 * 时返回字面量 *，浏览器不会为其发送 Cookie 等凭证
+func allowedOrigin(origins []string, origin string) (string, bool) {
+	if origin == "" {
+		return "", false
+	}
+	wildcard := false
+	for _, allowed := range origins {
+		if strings.EqualFold(allowed, origin) {
+			return origin, true
+		}
+		wildcard = wildcard || allowed == "*"
+	}
+	if wildcard {
+		return "*", false
+	}
+	return "", false
+}
+
 // RequestIDMiddleware 请求ID中间件
 func RequestIDMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"proomet/config"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name            string
+		origins         []string
+		origin          string
+		wantOrigin      string
+		wantCredentials bool
+	}{
+		{name: "通配符返回字面量且不允许凭证", origins: []string{"*"}, origin: "https://evil.example", wantOrigin: "*"},
+		{name: "明确配置的来源允许凭证", origins: []string{"https://app.example"}, origin: "https://app.example", wantOrigin: "https://app.example", wantCredentials: true},
+		{name: "明确配置优先于通配符", origins: []string{"*", "https://app.example"}, origin: "https://app.example", wantOrigin: "https://app.example", wantCredentials: true},
+		{name: "未配置的来源", origins: []string{"https://app.example"}, origin: "https://evil.example"},
+		{name: "非跨域请求", origins: []string{"*"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Set(&config.Config{Server: config.ServerConfig{EnableCORS: true, CORSOrigins: tt.origins}})
+			r := gin.New()
+			r.Use(CORSMiddleware())
+			r.GET("/", func(c *gin.Context) {})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			recorder := httptest.NewRecorder()
+			r.ServeHTTP(recorder, req)
+
+			if got := recorder.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Fatalf("Access-Control-Allow-Origin 为 %q，期望 %q", got, tt.wantOrigin)
+			}
+			if got := recorder.Header().Get("Access-Control-Allow-Credentials") == "true"; got != tt.wantCredentials {
+				t.Fatalf("允许凭证为 %v，期望 %v", got, tt.wantCredentials)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+	"proomet/config"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Transfer 将请求的读写超时放宽到 server.transfer_timeout，用于大文件上传下载
+// http.Server 的 ReadTimeout/WriteTimeout 按 server.timeout 设置，会截断耗时较长的传输
+func Transfer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deadline := time.Now().Add(config.Get().Server.TransferTimeout)
+		rc := http.NewResponseController(c.Writer)
+		// 测试中的 ResponseRecorder 等不支持设置超时，忽略即可
+		_ = rc.SetReadDeadline(deadline)
+		_ = rc.SetWriteDeadline(deadline)
+		c.Next()
+	}
+}
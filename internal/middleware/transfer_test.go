@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"proomet/config"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTransfer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.Set(&config.Config{Server: config.ServerConfig{TransferTimeout: time.Minute}})
+
+	// 分块缓慢输出，总耗时超过服务器的写超时
+	slow := func(c *gin.Context) {
+		for range 5 {
+			c.Writer.WriteString("chunk\n")
+			c.Writer.Flush()
+			time.Sleep(60 * time.Millisecond)
+		}
+	}
+	r := gin.New()
+	r.GET("/plain", slow)
+	r.GET("/transfer", Transfer(), slow)
+
+	srv := httptest.NewUnstartedServer(r)
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		complete bool
+	}{
+		{name: "普通路由受写超时限制", path: "/plain", complete: false},
+		{name: "传输路由放宽写超时", path: "/transfer", complete: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + tt.path)
+			if err != nil {
+				t.Fatalf("请求失败: %v", err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			complete := err == nil && strings.Count(string(body), "chunk") == 5
+			if complete != tt.complete {
+				t.Fatalf("期望完整响应 %v，实际读取 %q，错误 %v", tt.complete, body, err)
+			}
+		})
+	}
+}
@@ -1,19 +1,19 @@
 package main
 
 import (
-	"fmt"
-	"log"
+	"context"
 	"os"
 	"proomet/config"
 	_ "proomet/docs"
 	"proomet/internal/infra/auth"
 	"proomet/internal/infra/database"
+	"proomet/internal/infra/mailer"
 	"proomet/internal/infra/ofs"
+	"proomet/internal/infra/oidc"
 	"proomet/internal/interfaces/routes"
 	"proomet/internal/interfaces/validators"
 	"proomet/internal/middleware"
 	"proomet/pkg/utils"
-	"slices"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,16 +23,27 @@ import (
 // @description proomet api docs
 // @host localhost:7071
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
 func main() {
-	// 检查是否有迁移参数
-	args := os.Args[1:]
-	runMigration := slices.Contains(args, "migrate")
+	// 子命令：migrate up|down|status|create <name>，config check [dir]
+	if args := os.Args[1:]; len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			runMigrate(args[1:])
+			return
+		case "config":
+			runConfig(args[1:])
+			return
+		}
+	}
 
 	// 初始化配置
 	config.Init("")
 
 	// 设置Gin模式
-	if config.AppConfig.Server.Environment == "production" {
+	if config.Get().IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
 	} else {
 		gin.SetMode(gin.DebugMode)
@@ -42,42 +53,60 @@ func main() {
 	validators.RegisterCustomValidators()
 
 	// 初始化日志系统
-	if err := utils.SetupLogging(config.AppConfig.Log); err != nil {
+	if err := utils.SetupLogging(config.Get().Log); err != nil {
 		utils.Log.Fatalf("日志系统初始化失败: %v", err)
 	}
+	// 监听配置文件变化，日志级别等配置修改后即时生效
+	config.Subscribe(utils.ReloadLogging)
+	config.Watch()
 
 	// 记录启动日志
 	utils.Log.Info("服务启动中...")
 
-	// 初始化数据库
-	defer database.Close()
+	// 初始化数据库，连接在服务停止时关闭
 	database.InitDatabase()
-	// 初始化s3
-	if runMigration {
-		utils.Log.Info("执行数据库迁移...")
-		database.AutoMigrate()
-
-		utils.Log.Info("数据库迁移完成")
-		return
+	// 数据库结构落后于程序时拒绝启动
+	if err := database.CheckSchema(); err != nil {
+		utils.Log.Fatalf("数据库结构检查失败: %v", err)
 	}
+
+	// 初始化对象存储
 	ofs.InitOfs()
 	auth.InitCasbin(database.GetDB())
+	auth.InitKeyring(config.Get().JWT)
+	// 后台任务，服务停止时取消并等待退出
+	bg := newWorkers()
+	bg.Go(func(ctx context.Context) {
+		auth.RunKeyRotation(ctx)
+	})
+	oidc.InitOIDC(config.Get().OIDC, config.Get().Server.Environment)
+	mailer.InitMailer(config.Get().Mail)
 
 	r := gin.New()
+	// 只采信可信代理转发的客户端地址，避免伪造 X-Forwarded-For 绕过按 IP 的登录锁定
+	if err := r.SetTrustedProxies(config.Get().Server.TrustedProxies); err != nil {
+		utils.Log.Fatalf("可信代理配置无效: %v", err)
+	}
 	r.Use(middleware.RecoveryMiddleware())
 	r.Use(middleware.RequestIDMiddleware())
+	r.Use(middleware.CORSMiddleware())
 
 	routerManager := routes.NewRouterManager()
+	routerManager.RegisterRouter(routes.NewHealthRouter())
 	routerManager.RegisterRouter(routes.NewTestRouter())
 	routerManager.RegisterRouter(routes.NewAuthRouter())
+	routerManager.RegisterRouter(routes.NewWellKnownRouter())
+	routerManager.RegisterRouter(routes.NewPromptRouter())
+	routerManager.RegisterRouter(routes.NewCollectionRouter())
+	routerManager.RegisterRouter(routes.NewTagRouter())
+	routerManager.RegisterRouter(routes.NewWorkspaceRouter())
+	routerManager.RegisterRouter(routes.NewRbacRouter())
+	routerManager.RegisterRouter(routes.NewConfigRouter())
+	routerManager.RegisterRouter(routes.NewAPIKeyRouter())
+	routerManager.RegisterRouter(routes.NewUserRouter())
+	routerManager.RegisterRouter(routes.NewOfsRouter())
 	routerManager.SetupRoutes(r)
 
-	addr := fmt.Sprintf("%s:%s", config.AppConfig.Server.Host, config.AppConfig.Server.Port)
-	utils.Log.Success("🎉[proomet-server] 服务启动完成")
-
-	// 启动服务器
-	if err := r.Run(addr); err != nil {
-		log.Fatal("服务器启动失败:", err)
-	}
-
+	// 启动服务器，收到 SIGINT/SIGTERM 后等待处理中的请求完成再退出
+	serve(newHTTPServer(config.Get().Server, r), bg)
 }
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"proomet/config"
+	"proomet/internal/infra/auth"
+	"proomet/internal/infra/database"
+	"proomet/pkg/utils"
+	"strconv"
+	"text/tabwriter"
+)
+
+const migrateUsage = `用法: proomet migrate <命令>
+
+命令:
+  up [n]         执行未执行的迁移（默认全部），完成后写入默认策略并创建初始管理员
+  down [n]       回滚最近执行的 n 个迁移（默认 1 个）
+  status         查看迁移执行状态
+  create <name>  在 ` + database.MigrationsDir + ` 下生成新的迁移文件，需重新编译后生效
+`
+
+// runMigrate 执行 migrate 子命令
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	// create 只生成文件，不需要连接数据库
+	if args[0] == "create" {
+		if len(args) != 2 {
+			fmt.Fprint(os.Stderr, migrateUsage)
+			os.Exit(2)
+		}
+		upPath, downPath, err := database.CreateMigration(database.MigrationsDir, args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "创建迁移文件失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("已创建:\n  %s\n  %s\n", upPath, downPath)
+		return
+	}
+
+	steps := 0
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			fmt.Fprintf(os.Stderr, "迁移数量必须为正整数: %s\n", args[1])
+			os.Exit(2)
+		}
+		steps = n
+	}
+
+	config.Init("")
+	if err := utils.SetupLogging(config.Get().Log); err != nil {
+		utils.Log.Fatalf("日志系统初始化失败: %v", err)
+	}
+	database.InitDatabase()
+	defer database.Close()
+
+	switch args[0] {
+	case "up":
+		migrateUp(steps)
+	case "down":
+		migrations, err := database.MigrateDown(steps)
+		for _, migration := range migrations {
+			utils.Log.Infof("已回滚 %d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			utils.Log.Fatalf("数据库迁移回滚失败: %v", err)
+		}
+		if len(migrations) == 0 {
+			utils.Log.Info("没有可回滚的迁移")
+		}
+	case "status":
+		migrateStatus()
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+}
+
+// migrateUp 执行迁移并写入初始数据
+func migrateUp(steps int) {
+	utils.Log.Info("执行数据库迁移...")
+	migrations, err := database.MigrateUp(steps)
+	for _, migration := range migrations {
+		utils.Log.Infof("已执行 %d_%s", migration.Version, migration.Name)
+	}
+	if err != nil {
+		utils.Log.Fatalf("数据库迁移失败: %v", err)
+	}
+	if len(migrations) == 0 {
+		utils.Log.Info("数据库结构已是最新")
+	}
+
+	// 只执行了部分迁移时表结构可能不完整，跳过初始数据写入
+	if steps > 0 {
+		if err := database.CheckSchema(); err != nil {
+			utils.Log.Info("仍有未执行的迁移，跳过初始数据写入")
+			return
+		}
+	}
+
+	// 写入默认策略并创建初始管理员，重复执行不会产生副作用
+	auth.InitCasbin(database.GetDB())
+	if _, err := auth.SeedPolicies(auth.DefaultPolicyFile); err != nil {
+		utils.Log.Fatalf("默认策略写入失败: %v", err)
+	}
+	if err := database.SeedAdmin(config.Get().Admin); err != nil {
+		utils.Log.Fatalf("初始管理员创建失败: %v", err)
+	}
+	utils.Log.Info("数据库迁移完成")
+}
+
+// migrateStatus 输出迁移状态
+func migrateStatus() {
+	states, err := database.MigrationStatus()
+	if err != nil {
+		utils.Log.Fatalf("查询迁移状态失败: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, state := range states {
+		status, appliedAt := "pending", "-"
+		switch {
+		case state.Missing:
+			status = "applied (文件缺失)"
+		case state.Modified:
+			status = "applied (文件已修改)"
+		case state.Applied:
+			status = "applied"
+		}
+		if state.AppliedAt != nil {
+			appliedAt = state.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", state.Version, state.Name, status, appliedAt)
+	}
+	w.Flush()
+}
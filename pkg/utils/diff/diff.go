@@ -0,0 +1,298 @@
+package diff
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// OpType 差异操作类型
+type OpType string
+
+const (
+	OpEqual  OpType = "equal"  // 未变化
+	OpInsert OpType = "insert" // 新增
+	OpDelete OpType = "delete" // 删除
+)
+
+// ErrTooComplex 编辑距离超过限制
+var ErrTooComplex = errors.New("diff: edit distance exceeds limit")
+
+// Op 差异片段，连续的同类型 token 会被合并为一个片段，Tokens 为合并的 token 数
+type Op struct {
+	Type   OpType `json:"type"`
+	Text   string `json:"text"`
+	Tokens int    `json:"tokens"`
+}
+
+// Lines 按行比较两段文本
+func Lines(a, b string) []Op {
+	return Diff(SplitLines(a), SplitLines(b))
+}
+
+// Words 按单词比较两段文本（空白字符作为独立 token 保留，便于还原原文）
+func Words(a, b string) []Op {
+	return Diff(SplitWords(a), SplitWords(b))
+}
+
+// SplitLines 拆分为行，每行保留末尾换行符
+func SplitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// SplitWords 拆分为单词与空白片段，CJK 字符逐字拆分
+func SplitWords(s string) []string {
+	var tokens []string
+	var current strings.Builder
+	currentIsSpace := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range s {
+		isSpace := unicode.IsSpace(r)
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.IsPunct(r):
+			flush()
+			tokens = append(tokens, string(r))
+			continue
+		case current.Len() > 0 && isSpace != currentIsSpace:
+			flush()
+		}
+		current.WriteRune(r)
+		currentIsSpace = isSpace
+	}
+	flush()
+	return tokens
+}
+
+// Diff 使用 Myers 算法计算 token 序列的最短编辑脚本
+func Diff(a, b []string) []Op {
+	ops, _ := DiffLimit(a, b, -1)
+	return ops
+}
+
+// DiffLimit 同 Diff，编辑距离（新增与删除的 token 数之和）超过 maxCost 时返回 ErrTooComplex，maxCost 小于 0 表示不限制
+// 使用线性空间的 Myers 算法（从两端同时搜索中间蛇形并递归拆分），内存占用为 O(N+M)
+func DiffLimit(a, b []string, maxCost int) ([]Op, error) {
+	d := &differ{tokensA: a, tokensB: b, maxCost: maxCost, ops: []Op{}}
+	// token 映射为整数，比较时不再逐个比较字符串
+	ids := make(map[string]int, len(a)+len(b))
+	d.a = toIDs(a, ids)
+	d.b = toIDs(b, ids)
+	size := len(a) + len(b) + 4
+	d.v1 = make([]int, size)
+	d.v2 = make([]int, size)
+
+	if err := d.compare(0, len(a), 0, len(b)); err != nil {
+		return nil, err
+	}
+	return d.ops, nil
+}
+
+// differ 计算过程中的状态，v1、v2 为正向与反向搜索的 V 数组，在各层递归之间复用
+type differ struct {
+	tokensA, tokensB []string
+	a, b             []int
+	v1, v2           []int
+	maxCost          int
+	ops              []Op
+}
+
+// toIDs 将 token 映射为整数
+func toIDs(tokens []string, ids map[string]int) []int {
+	out := make([]int, len(tokens))
+	for i, token := range tokens {
+		id, ok := ids[token]
+		if !ok {
+			id = len(ids)
+			ids[token] = id
+		}
+		out[i] = id
+	}
+	return out
+}
+
+// emit 追加差异片段，与上一个片段类型相同时合并
+func (d *differ) emit(typ OpType, text string) {
+	if last := len(d.ops) - 1; last >= 0 && d.ops[last].Type == typ {
+		d.ops[last].Text += text
+		d.ops[last].Tokens++
+		return
+	}
+	d.ops = append(d.ops, Op{Type: typ, Text: text, Tokens: 1})
+}
+
+// compare 比较 a[aLo:aHi] 与 b[bLo:bHi]，按顺序输出差异片段
+func (d *differ) compare(aLo, aHi, bLo, bHi int) error {
+	// 去掉公共前缀与后缀
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		d.emit(OpEqual, d.tokensA[aLo])
+		aLo++
+		bLo++
+	}
+	suffix := aHi
+	for aHi > aLo && bHi > bLo && d.a[aHi-1] == d.b[bHi-1] {
+		aHi--
+		bHi--
+	}
+
+	switch {
+	case aLo == aHi:
+		for _, token := range d.tokensB[bLo:bHi] {
+			d.emit(OpInsert, token)
+		}
+	case bLo == bHi:
+		for _, token := range d.tokensA[aLo:aHi] {
+			d.emit(OpDelete, token)
+		}
+	default:
+		x, y, found, err := d.bisect(aLo, aHi, bLo, bHi)
+		if err != nil {
+			return err
+		}
+		if !found {
+			// 没有公共 token
+			for _, token := range d.tokensA[aLo:aHi] {
+				d.emit(OpDelete, token)
+			}
+			for _, token := range d.tokensB[bLo:bHi] {
+				d.emit(OpInsert, token)
+			}
+			break
+		}
+		if err := d.compare(aLo, x, bLo, y); err != nil {
+			return err
+		}
+		if err := d.compare(x, aHi, y, bHi); err != nil {
+			return err
+		}
+	}
+
+	for _, token := range d.tokensA[aHi:suffix] {
+		d.emit(OpEqual, token)
+	}
+	return nil
+}
+
+// bisect 从两端同时搜索，返回正反两条路径相遇的位置，以此将问题拆分为两个更小的子问题
+func (d *differ) bisect(aLo, aHi, bLo, bHi int) (int, int, bool, error) {
+	n, m := aHi-aLo, bHi-bLo
+	maxD := (n + m + 1) / 2
+	offset := maxD
+	length := 2*maxD + 2
+	v1, v2 := d.v1[:length], d.v2[:length]
+	for i := range v1 {
+		v1[i] = -1
+		v2[i] = -1
+	}
+	v1[offset+1] = 0
+	v2[offset+1] = 0
+
+	delta := n - m
+	// 差值为奇数时正向路径先与反向路径重叠
+	front := delta%2 != 0
+	k1Start, k1End, k2Start, k2End := 0, 0, 0, 0
+
+	for step := 0; step < maxD; step++ {
+		// 编辑距离至少为 2*step-1
+		if d.maxCost >= 0 && 2*step-1 > d.maxCost {
+			return 0, 0, false, ErrTooComplex
+		}
+
+		// 正向搜索一步
+		for k1 := -step + k1Start; k1 <= step-k1End; k1 += 2 {
+			k1Offset := offset + k1
+			var x1 int
+			if k1 == -step || (k1 != step && v1[k1Offset-1] < v1[k1Offset+1]) {
+				x1 = v1[k1Offset+1]
+			} else {
+				x1 = v1[k1Offset-1] + 1
+			}
+			y1 := x1 - k1
+			for x1 < n && y1 < m && d.a[aLo+x1] == d.b[bLo+y1] {
+				x1++
+				y1++
+			}
+			v1[k1Offset] = x1
+			switch {
+			case x1 > n:
+				k1End += 2
+			case y1 > m:
+				k1Start += 2
+			case front:
+				k2Offset := offset + delta - k1
+				if k2Offset >= 0 && k2Offset < length && v2[k2Offset] != -1 && x1 >= n-v2[k2Offset] {
+					return aLo + x1, bLo + y1, true, nil
+				}
+			}
+		}
+
+		// 反向搜索一步
+		for k2 := -step + k2Start; k2 <= step-k2End; k2 += 2 {
+			k2Offset := offset + k2
+			var x2 int
+			if k2 == -step || (k2 != step && v2[k2Offset-1] < v2[k2Offset+1]) {
+				x2 = v2[k2Offset+1]
+			} else {
+				x2 = v2[k2Offset-1] + 1
+			}
+			y2 := x2 - k2
+			for x2 < n && y2 < m && d.a[aHi-x2-1] == d.b[bHi-y2-1] {
+				x2++
+				y2++
+			}
+			v2[k2Offset] = x2
+			switch {
+			case x2 > n:
+				k2End += 2
+			case y2 > m:
+				k2Start += 2
+			case !front:
+				k1Offset := offset + delta - k2
+				if k1Offset >= 0 && k1Offset < length && v1[k1Offset] != -1 {
+					x1 := v1[k1Offset]
+					y1 := offset + x1 - k1Offset
+					if x1 >= n-x2 {
+						return aLo + x1, bLo + y1, true, nil
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false, nil
+}
+
+// Stats 统计新增与删除的 token 数
+func Stats(ops []Op) (insertions, deletions int) {
+	for _, op := range ops {
+		switch op.Type {
+		case OpInsert:
+			insertions += op.Tokens
+		case OpDelete:
+			deletions += op.Tokens
+		}
+	}
+	return
+}
+
+// Changed 是否存在差异
+func Changed(ops []Op) bool {
+	for _, op := range ops {
+		if op.Type != OpEqual {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package diff
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want []Op
+	}{
+		{"empty", "", "", []Op{}},
+		{"equal", "a\nb\n", "a\nb\n", []Op{{OpEqual, "a\nb\n", 2}}},
+		{"insert", "a\n", "a\nb\n", []Op{{OpEqual, "a\n", 1}, {OpInsert, "b\n", 1}}},
+		{"delete", "a\nb\n", "b\n", []Op{{OpDelete, "a\n", 1}, {OpEqual, "b\n", 1}}},
+		{"replace middle", "a\nb\nc\n", "a\nx\nc\n", []Op{{OpEqual, "a\n", 1}, {OpDelete, "b\n", 1}, {OpInsert, "x\n", 1}, {OpEqual, "c\n", 1}}},
+		{"all new", "a\n", "b\n", []Op{{OpDelete, "a\n", 1}, {OpInsert, "b\n", 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lines(tt.a, tt.b); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"hello world", []string{"hello", " ", "world"}},
+		{"你好，世界", []string{"你", "好", "，", "世", "界"}},
+		{"a  b\n", []string{"a", "  ", "b", "\n"}},
+	}
+	for _, tt := range tests {
+		if got := SplitWords(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitWords(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestDiffMinimal 随机输入下编辑脚本能还原两侧文本，且编辑距离等于最长公共子序列计算出的最小值
+func TestStats(t *testing.T) {
+	tests := []struct {
+		name                  string
+		a, b                  string
+		insertions, deletions int
+	}{
+		{"equal", "a\nb\n", "a\nb\n", 0, 0},
+		{"merged insert", "a\n", "a\nb\nc\nd\n", 3, 0},
+		{"merged delete", "a\nb\nc\n", "c\n", 0, 2},
+		{"replace block", "a\nb\nc\nd\n", "a\nx\ny\nd\n", 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			insertions, deletions := Stats(Lines(tt.a, tt.b))
+			if insertions != tt.insertions || deletions != tt.deletions {
+				t.Errorf("Stats() = %d, %d, want %d, %d", insertions, deletions, tt.insertions, tt.deletions)
+			}
+		})
+	}
+}
+
+func TestDiffMinimal(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "b", "c", "d"}
+	random := func() []string {
+		tokens := make([]string, rnd.Intn(30))
+		for i := range tokens {
+			tokens[i] = alphabet[rnd.Intn(len(alphabet))]
+		}
+		return tokens
+	}
+	for i := 0; i < 500; i++ {
+		a, b := random(), random()
+		ops := Diff(a, b)
+
+		var gotA, gotB strings.Builder
+		cost := 0
+		for _, op := range ops {
+			if op.Type != OpInsert {
+				gotA.WriteString(op.Text)
+			}
+			if op.Type != OpDelete {
+				gotB.WriteString(op.Text)
+			}
+			if op.Type != OpEqual {
+				cost += len(op.Text)
+			}
+		}
+		if gotA.String() != strings.Join(a, "") || gotB.String() != strings.Join(b, "") {
+			t.Fatalf("ops %v do not reproduce %q -> %q", ops, a, b)
+		}
+		if want := len(a) + len(b) - 2*lcs(a, b); cost != want {
+			t.Fatalf("edit cost %d, want %d for %q -> %q", cost, want, a, b)
+		}
+	}
+}
+
+func TestDiffLimit(t *testing.T) {
+	a := strings.Split(strings.Repeat("x", 100), "")
+	b := strings.Split(strings.Repeat("y", 100), "")
+	if _, err := DiffLimit(a, b, 50); !errors.Is(err, ErrTooComplex) {
+		t.Errorf("DiffLimit() error = %v, want ErrTooComplex", err)
+	}
+	if _, err := DiffLimit(a, b, 200); err != nil {
+		t.Errorf("DiffLimit() error = %v, want nil", err)
+	}
+}
+
+func lcs(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			if a[i-1] == b[j-1] {
+				dp[i][j] = dp[i-1][j-1] + 1
+			} else {
+				dp[i][j] = max(dp[i-1][j], dp[i][j-1])
+			}
+		}
+	}
+	return dp[len(a)][len(b)]
+}
@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"proomet/config"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ActionClaims 一次性操作令牌（邮箱验证、重置密码）声明
+// 使用由 jwt.secret 派生的独立密钥签名，不能作为访问令牌使用；Audience 为操作类型
+type ActionClaims struct {
+	UserID uint   `json:"uid"`
+	Email  string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// GenerateActionToken 生成操作令牌，返回令牌与其 jti，调用方需要记录 jti 以保证令牌只能使用一次
+func GenerateActionToken(userID uint, email, purpose string, ttl time.Duration) (string, string, error) {
+	now := time.Now()
+	claims := &ActionClaims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Audience:  jwt.ClaimStrings{purpose},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "proomet",
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(actionKey())
+	if err != nil {
+		return "", "", err
+	}
+	return token, claims.ID, nil
+}
+
+// ParseActionToken 校验操作令牌的签名、有效期与操作类型
+func ParseActionToken(tokenString, purpose string) (*ActionClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ActionClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return actionKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(purpose), jwt.WithIssuer("proomet"))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*ActionClaims); ok && token.Valid && claims.ID != "" {
+		return claims, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+// actionKey 由 jwt.secret 派生的操作令牌签名密钥
+func actionKey() []byte {
+	mac := hmac.New(sha256.New, []byte(config.Get().JWT.Secret))
+	mac.Write([]byte("proomet-action-token"))
+	return mac.Sum(nil)
+}
@@ -7,18 +7,22 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // Claims 自定义声明结构体
+// ID(jti) 用于单独吊销访问令牌，SessionID 关联签发该令牌的刷新令牌家族
 type Claims struct {
 	models.JwtUser
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成JWT Token
-func GenerateToken(userID uint, username, role string) (string, error) {
+// GenerateToken 生成JWT Token，有效期为 jwt.expired 秒，sessionID 为签发时所属的刷新令牌家族
+func GenerateToken(userID uint, username, role, sessionID string) (string, error) {
+	now := time.Now()
 	// 设置Token过期时间
-	expirationTime := time.Now().Add(time.Duration(config.AppConfig.JWT.Expired) * time.Second) // Token 24小时后过期
+	expirationTime := now.Add(AccessTokenTTL())
 	// 创建声明
 	claims := &Claims{
 		JwtUser: models.JwtUser{
@@ -26,19 +30,32 @@ func GenerateToken(userID uint, username, role string) (string, error) {
 			Username: username,
 			Role:     role,
 		},
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    "proomet",
 		},
 	}
 
+	// 配置了非对称密钥时使用当前密钥签名并在头部写入 kid，否则使用共享密钥 HS256
+	if key := activeKey(); key != nil {
+		method, err := signingMethod(key.Algorithm)
+		if err != nil {
+			return "", err
+		}
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = key.KID
+		return token.SignedString(key.Private)
+	}
+
 	// 创建Token
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// 签名Token
-	tokenString, err := token.SignedString([]byte(config.AppConfig.JWT.Secret))
+	tokenString, err := token.SignedString([]byte(config.Get().JWT.Secret))
 	if err != nil {
 		return "", err
 	}
@@ -46,12 +63,32 @@ func GenerateToken(userID uint, username, role string) (string, error) {
 	return tokenString, nil
 }
 
+// AccessTokenTTL 访问令牌有效期
+func AccessTokenTTL() time.Duration {
+	return time.Duration(config.Get().JWT.Expired) * time.Second
+}
+
+// RefreshTokenTTL 刷新令牌有效期
+func RefreshTokenTTL() time.Duration {
+	return time.Duration(config.Get().JWT.RefreshExpired) * time.Second
+}
+
 // ParseToken 解析JWT Token
+// 使用非对称算法时按 kid 查找验证公钥（轮换后的旧密钥在保留期内仍可验证），HS256 令牌不再被接受
 func ParseToken(tokenString string) (*Claims, error) {
+	algorithm := config.Get().JWT.Algorithm
+	if algorithm == "" {
+		algorithm = AlgorithmHS256
+	}
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		return []byte(config.Get().JWT.Secret), nil
+	}
+	if algorithm != AlgorithmHS256 {
+		keyFunc = verificationKey
+	}
+
 	// 解析Token
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(config.AppConfig.JWT.Secret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keyFunc, jwt.WithValidMethods([]string{algorithm}))
 
 	if err != nil {
 		return nil, err
@@ -0,0 +1,93 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"proomet/config"
+	"testing"
+)
+
+// newEdDSAKey 生成 EdDSA 签名密钥
+func newEdDSAKey(t *testing.T, kid string) *SigningKey {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &SigningKey{KID: kid, Algorithm: AlgorithmEdDSA, Private: priv, Public: pub}
+}
+
+// useConfig 设置测试使用的 JWT 配置与密钥环
+func useConfig(algorithm string, active *SigningKey, keys ...*SigningKey) {
+	config.Set(&config.Config{JWT: config.JWTConfig{Secret: "test-secret", Expired: 900, Algorithm: algorithm}})
+	SetKeys(active, keys)
+}
+
+func TestParseToken(t *testing.T) {
+	oldKey, newKey := newEdDSAKey(t, "old"), newEdDSAKey(t, "new")
+	tests := []struct {
+		name string
+		// sign 签发令牌时的配置，verify 校验令牌时的配置
+		sign, verify func()
+		wantErr      bool
+	}{
+		{
+			name:   "HS256 共享密钥",
+			sign:   func() { useConfig(AlgorithmHS256, nil) },
+			verify: func() { useConfig(AlgorithmHS256, nil) },
+		},
+		{
+			name:   "EdDSA 当前密钥",
+			sign:   func() { useConfig(AlgorithmEdDSA, newKey, newKey) },
+			verify: func() { useConfig(AlgorithmEdDSA, newKey, newKey) },
+		},
+		{
+			name:   "轮换后旧密钥在保留期内仍可验证",
+			sign:   func() { useConfig(AlgorithmEdDSA, oldKey, oldKey) },
+			verify: func() { useConfig(AlgorithmEdDSA, newKey, newKey, oldKey) },
+		},
+		{
+			name:    "旧密钥移除后不再接受",
+			sign:    func() { useConfig(AlgorithmEdDSA, oldKey, oldKey) },
+			verify:  func() { useConfig(AlgorithmEdDSA, newKey, newKey) },
+			wantErr: true,
+		},
+		{
+			name:    "切换到非对称算法后不再接受 HS256 令牌",
+			sign:    func() { useConfig(AlgorithmHS256, nil) },
+			verify:  func() { useConfig(AlgorithmEdDSA, newKey, newKey) },
+			wantErr: true,
+		},
+		{
+			name: "共享密钥不一致",
+			sign: func() { useConfig(AlgorithmHS256, nil) },
+			verify: func() {
+				config.Set(&config.Config{JWT: config.JWTConfig{Secret: "another-secret", Algorithm: AlgorithmHS256}})
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.sign()
+			token, err := GenerateToken(1, "alice", "member", "session")
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.verify()
+			claims, err := ParseToken(token)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("期望校验失败")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims.UserID != 1 || claims.Username != "alice" || claims.SessionID != "session" {
+				t.Fatalf("声明错误: %+v", claims)
+			}
+		})
+	}
+}
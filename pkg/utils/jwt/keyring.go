@@ -0,0 +1,145 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 支持的签名算法
+const (
+	AlgorithmHS256 = "HS256" // 共享密钥，验证方需要持有 jwt.secret
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// SigningKey 非对称签名密钥，Private 为空时只用于验证
+type SigningKey struct {
+	KID       string
+	Algorithm string
+	Private   crypto.Signer
+	Public    crypto.PublicKey
+}
+
+// JWK JSON Web Key 公钥
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// keyring 当前使用的密钥环，active 用于签发，keys 包含全部可用于验证的密钥
+type keyring struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+var currentKeyring atomic.Pointer[keyring]
+
+// SetKeys 替换密钥环，active 为空时回退到 HS256 共享密钥签发
+func SetKeys(active *SigningKey, keys []*SigningKey) {
+	ring := &keyring{active: active, keys: make(map[string]*SigningKey, len(keys))}
+	for _, key := range keys {
+		ring.keys[key.KID] = key
+	}
+	currentKeyring.Store(ring)
+}
+
+// PublicJWKS 导出全部验证公钥，供其他服务独立校验 proomet 签发的令牌
+func PublicJWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	ring := currentKeyring.Load()
+	if ring == nil {
+		return set
+	}
+	for _, key := range ring.keys {
+		if jwk, err := key.JWK(); err == nil {
+			set.Keys = append(set.Keys, *jwk)
+		}
+	}
+	return set
+}
+
+// JWK 转换为 JWK 公钥
+func (k *SigningKey) JWK() (*JWK, error) {
+	switch pub := k.Public.(type) {
+	case *rsa.PublicKey:
+		return &JWK{
+			Kty: "RSA",
+			Kid: k.KID,
+			Use: "sig",
+			Alg: AlgorithmRS256,
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, nil
+	case ed25519.PublicKey:
+		return &JWK{
+			Kty: "OKP",
+			Kid: k.KID,
+			Use: "sig",
+			Alg: AlgorithmEdDSA,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", k.Public)
+	}
+}
+
+// signingMethod 算法对应的签名方法
+func signingMethod(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	case AlgorithmHS256, "":
+		return jwt.SigningMethodHS256, nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %s", algorithm)
+	}
+}
+
+// activeKey 当前用于签发的非对称密钥，未配置时返回 nil
+func activeKey() *SigningKey {
+	if ring := currentKeyring.Load(); ring != nil {
+		return ring.active
+	}
+	return nil
+}
+
+// verificationKey 按 kid 查找验证密钥，并校验令牌算法与密钥一致
+func verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, fmt.Errorf("missing kid")
+	}
+	ring := currentKeyring.Load()
+	if ring == nil {
+		return nil, fmt.Errorf("unknown kid %s", kid)
+	}
+	key, ok := ring.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %s", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+	return key.Public, nil
+}
@@ -40,20 +40,7 @@ func SetupLogging(configLog config.LogConfig) error {
 	})
 
 	// 设置日志格式
-	if configLog.Format == "json" {
-		logger.SetFormatter(&logrus.JSONFormatter{
-			TimestampFormat: configLog.TimestampFormat,
-		})
-	} else {
-		// 创建带颜色的格式化器
-		formatter := &logrus.TextFormatter{
-			TimestampFormat: configLog.TimestampFormat,
-			FullTimestamp:   true,
-			ForceColors:     configLog.EnableColors,
-			DisableColors:   !configLog.EnableColors,
-		}
-		logger.SetFormatter(formatter)
-	}
+	logger.SetFormatter(newFormatter(configLog))
 
 	// 创建支持颜色的输出
 	colorableOutput := colorable.NewColorableStdout()
@@ -90,6 +77,37 @@ func SetupLogging(configLog config.LogConfig) error {
 	return nil
 }
 
+// ReloadLogging 配置热加载时更新日志级别与格式，日志文件相关配置需要重启生效
+func ReloadLogging(old, new *config.Config) {
+	if Log == nil || (old != nil && old.Log == new.Log) {
+		return
+	}
+	level, err := logrus.ParseLevel(new.Log.Level)
+	if err != nil {
+		Log.Warnf("日志级别 %s 无效，保持为 %s", new.Log.Level, Log.GetLevel())
+	} else if level != Log.GetLevel() {
+		Log.SetLevel(level)
+		Log.Infof("日志级别已调整为 %s", level)
+	}
+	Log.SetFormatter(newFormatter(new.Log))
+}
+
+// newFormatter 根据配置创建日志格式化器
+func newFormatter(configLog config.LogConfig) logrus.Formatter {
+	if configLog.Format == "json" {
+		return &logrus.JSONFormatter{
+			TimestampFormat: configLog.TimestampFormat,
+		}
+	}
+	// 创建带颜色的格式化器
+	return &logrus.TextFormatter{
+		TimestampFormat: configLog.TimestampFormat,
+		FullTimestamp:   true,
+		ForceColors:     configLog.EnableColors,
+		DisableColors:   !configLog.EnableColors,
+	}
+}
+
 // WithField 添加字段到日志条目
 func (l *Logger) WithField(key string, value interface{}) *logrus.Entry {
 	return l.Logger.WithField(key, value)
@@ -0,0 +1,41 @@
+package render
+
+import (
+	"regexp"
+)
+
+// placeholderPattern 匹配 {{name}} 形式的占位符，允许花括号内两侧有空白
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// namePattern 合法的变量名
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// IsValidName 判断变量名是否合法
+func IsValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Placeholders 按出现顺序返回文本中引用的变量名（去重）
+func Placeholders(text string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, match := range placeholderPattern.FindAllStringSubmatch(text, -1) {
+		name := match[1]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Render 将文本中的占位符替换为对应的值，未提供值的占位符保持原样
+func Render(text string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := placeholderPattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
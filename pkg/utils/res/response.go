@@ -146,7 +146,12 @@ var (
 	ErrInternalServer  = &BusinessError{Code: 500001, Message: "服务器内部错误"}
 
 	// 认证相关错误
-	ErrInvalidCredentials = &BusinessError{Code: 400002, Message: "凭证错误"}
+	ErrInvalidCredentials  = &BusinessError{Code: 400002, Message: "凭证错误"}
+	ErrRefreshTokenInvalid = &BusinessError{Code: 400005, Message: "刷新令牌无效或已过期"}
+	ErrAccountDisabled     = &BusinessError{Code: 400006, Message: "账号已被禁用"}
+	ErrOIDCLoginFailed     = &BusinessError{Code: 400007, Message: "第三方登录失败"}
+	ErrActionTokenInvalid  = &BusinessError{Code: 400008, Message: "链接无效或已过期"}
+	ErrLoginLocked         = &BusinessError{Code: 400011, Message: "登录失败次数过多，请稍后再试"}
 
 	// 用户相关错误
 	ErrUserNotFound      = &BusinessError{Code: 400101, Message: "用户不存在"}
@@ -157,4 +162,33 @@ var (
 
 	// 权限相关错误
 	ErrInsufficientPermissions = &BusinessError{Code: 400009, Message: "权限不足"}
+
+	// 提示词相关错误
+	ErrPromptNotFound        = &BusinessError{Code: 400201, Message: "提示词不存在"}
+	ErrPromptVersionNotFound = &BusinessError{Code: 400202, Message: "提示词版本不存在"}
+	ErrCollectionNotFound    = &BusinessError{Code: 400203, Message: "集合不存在"}
+	ErrTagNotFound           = &BusinessError{Code: 400204, Message: "标签不存在"}
+	ErrAttachmentNotFound    = &BusinessError{Code: 400205, Message: "附件不存在"}
+	ErrAttachmentTooLarge    = &BusinessError{Code: 400206, Message: "附件大小超出限制"}
+	ErrAttachmentType        = &BusinessError{Code: 400207, Message: "不支持的附件类型"}
+	ErrPromptDiffTooLarge    = &BusinessError{Code: 400208, Message: "版本差异过大，无法对比"}
+
+	// 工作区相关错误
+	ErrWorkspaceNotFound    = &BusinessError{Code: 400301, Message: "工作区不存在"}
+	ErrWorkspaceSlugTaken   = &BusinessError{Code: 400302, Message: "工作区标识已存在"}
+	ErrNotWorkspaceMember   = &BusinessError{Code: 400303, Message: "不是该工作区成员"}
+	ErrWorkspaceMemberExist = &BusinessError{Code: 400304, Message: "用户已是该工作区成员"}
+
+	// 权限策略相关错误
+	ErrPolicyExists   = &BusinessError{Code: 400401, Message: "策略已存在"}
+	ErrPolicyNotFound = &BusinessError{Code: 400402, Message: "策略不存在"}
+
+	// API Key 相关错误
+	ErrAPIKeyNotFound = &BusinessError{Code: 400501, Message: "API Key 不存在"}
+
+	// 两步验证相关错误
+	ErrTwoFactorCodeInvalid      = &BusinessError{Code: 400601, Message: "验证码错误"}
+	ErrTwoFactorChallengeInvalid = &BusinessError{Code: 400602, Message: "两步验证已过期，请重新登录"}
+	ErrTwoFactorAlreadyEnabled   = &BusinessError{Code: 400603, Message: "已启用两步验证"}
+	ErrTwoFactorNotEnabled       = &BusinessError{Code: 400604, Message: "未启用两步验证"}
 )
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"proomet/config"
+	"proomet/internal/infra/database"
+	"proomet/internal/infra/mailer"
+	"proomet/internal/infra/ofs"
+	"proomet/pkg/utils"
+	"sync"
+	"syscall"
+)
+
+// workers 后台任务，停止服务时取消 ctx 并等待全部退出
+type workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWorkers() *workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workers{ctx: ctx, cancel: cancel}
+}
+
+// Go 启动后台任务，fn 需要在 ctx 取消后尽快返回
+func (w *workers) Go(fn func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		fn(w.ctx)
+	}()
+}
+
+// Stop 取消全部后台任务并等待退出，ctx 结束时返回错误
+func (w *workers) Stop(ctx context.Context) error {
+	w.cancel()
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// newHTTPServer 按服务器配置创建 HTTP 服务
+func newHTTPServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              net.JoinHostPort(cfg.Host, cfg.Port),
+		Handler:           handler,
+		ReadHeaderTimeout: cfg.Timeout,
+		ReadTimeout:       cfg.Timeout,
+		WriteTimeout:      cfg.Timeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// serve 启动 HTTP 服务并阻塞，收到 SIGINT/SIGTERM 后停止服务
+func serve(srv *http.Server, bg *workers) {
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		utils.Log.Fatalf("服务器启动失败: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+	utils.Log.Successf("🎉[proomet-server] 服务启动完成，监听地址: %s", srv.Addr)
+
+	select {
+	case err := <-serveErr:
+		utils.Log.Errorf("服务器异常退出: %v", err)
+	case <-ctx.Done():
+		// 恢复默认信号处理，再次收到信号时直接退出
+		stop()
+		utils.Log.Info("收到停止信号，开始停止服务...")
+	}
+	shutdown(srv, bg)
+}
+
+// shutdown 按顺序停止服务，全部步骤共用 server.shutdown_timeout：
+// 停止接收新请求并等待处理中的请求完成 → 停止后台任务并等待邮件发送完成 → 关闭对象存储与数据库连接
+func shutdown(srv *http.Server, bg *workers) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.Get().Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		utils.Log.Errorf("等待处理中的请求超时，强制关闭连接: %v", err)
+		srv.Close()
+	}
+	if err := bg.Stop(ctx); err != nil {
+		utils.Log.Errorf("等待后台任务退出超时: %v", err)
+	}
+	if err := mailer.Wait(ctx); err != nil {
+		utils.Log.Errorf("等待邮件发送超时，未发送完成的邮件将丢失: %v", err)
+	}
+
+	ofs.Close()
+	database.Close()
+	utils.Log.Info("服务已停止")
+}